@@ -1,87 +1,230 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
 
 	"github.com/rd2w/jira-parser/internal/domain"
 )
 
 type CommentService struct {
-	repo domain.CommentRepository
+	repo   domain.CommentRepository
+	parser domain.CommentParser
+	// projectParsers переопределяют parser для тикетов отдельных проектов (ключ - проект в верхнем регистре)
+	projectParsers map[string]domain.CommentParser
+	// filter оставляет только подходящие QA комментарии; nil - все комментарии
+	filter      domain.CommentFilter
+	concurrency int
 }
 
-func NewCommentService(repo domain.CommentRepository) *CommentService {
-	return &CommentService{repo: repo}
+// Option настраивает CommentService
+type Option func(*CommentService)
+
+// WithConcurrency задает число тикетов, обрабатываемых одновременно в ParseMultipleTickets
+func WithConcurrency(n int) Option {
+	return func(s *CommentService) {
+		s.concurrency = n
+	}
+}
+
+// WithCommentFilter оставляет в результатах ParseComments, ParseMultipleTickets и GetLastComment
+// только комментарии, прошедшие filter
+func WithCommentFilter(filter domain.CommentFilter) Option {
+	return func(s *CommentService) {
+		s.filter = filter
+	}
+}
+
+// WithProjectParser разбирает комментарии тикетов проекта project (например, "TOS") парсером parser
+func WithProjectParser(project string, parser domain.CommentParser) Option {
+	return func(s *CommentService) {
+		if s.projectParsers == nil {
+			s.projectParsers = make(map[string]domain.CommentParser)
+		}
+		s.projectParsers[strings.ToUpper(project)] = parser
+	}
+}
+
+// NewCommentService создает сервис; parser разбирает комментарии всех проектов,
+// для которых не задан собственный парсер через WithProjectParser
+func NewCommentService(repo domain.CommentRepository, parser domain.CommentParser, opts ...Option) *CommentService {
+	s := &CommentService{repo: repo, parser: parser, concurrency: 1}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.concurrency < 1 {
+		s.concurrency = 1
+	}
+	return s
 }
 
 func (s *CommentService) ParseComments(issueKey string) (*domain.Issue, error) {
+	return s.ParseCommentsWithContext(context.Background(), issueKey)
+}
+
+func (s *CommentService) ParseCommentsWithContext(ctx context.Context, issueKey string) (*domain.Issue, error) {
 	if issueKey == "" {
 		return nil, fmt.Errorf("issue key cannot be empty")
 	}
 
 	log.Printf("Starting to parse comments for issue %s", issueKey)
-	comments, err := s.repo.GetIssueComments(issueKey)
+	// Информация о тикете и комментарии приходят из одного запроса к репозиторию
+	issueInfo, rawComments, err := s.repo.GetIssueWithCommentsWithContext(ctx, issueKey)
 	if err != nil {
 		log.Printf("Error getting comments for issue %s: %v", issueKey, err)
 		return nil, fmt.Errorf("failed to get comments for issue %s: %w", issueKey, err)
 	}
 
-	// Get issue info to populate the Summary
-	issueInfo, err := s.repo.GetIssueInfo(issueKey)
-	if err != nil {
-		log.Printf("Warning: Could not get issue info for %s: %v", issueKey, err)
-		// Continue with empty summary if we can't get the issue info
-		issueInfo = &domain.IssueInfo{
-			Key:           issueKey,
-			Summary:       "",
-			AssigneeEmail: "",
-			QaOwnerEmail:  "",
-		}
+	parser := s.parserFor(issueKey)
+	comments, rejections := parseComments(parser, rawComments)
+	comments = s.filterComments(comments)
+	for _, rejection := range rejections {
+		log.Printf("Rejected QA comment %s on issue %s: %s", rejection.CommentID, issueKey, rejection.Reason)
+	}
+
+	qaOwnerEmail := issueInfo.QaOwnerEmail
+	// Если поле QA владельца пустое, используем резервную логику - автора последнего QA комментария
+	if qaOwnerEmail == "" {
+		qaOwnerEmail = lastQAAuthor(parser, rawComments)
 	}
 
 	log.Printf("Successfully parsed %d comments for issue %s", len(comments), issueKey)
 	return &domain.Issue{
-		Key:           issueInfo.Key,
-		Summary:       issueInfo.Summary,
-		AssigneeEmail: issueInfo.AssigneeEmail,
-		QaOwnerEmail:  issueInfo.QaOwnerEmail,
-		Comments:      comments,
+		Key:                issueInfo.Key,
+		Summary:            issueInfo.Summary,
+		AssigneeEmail:      issueInfo.AssigneeEmail,
+		QaOwnerEmail:       qaOwnerEmail,
+		Comments:           comments,
+		LatestVersion:      domain.LatestVersion(comments),
+		CommentsIncomplete: issueInfo.CommentsIncomplete,
+		Rejections:         rejections,
 	}, nil
 }
 
 func (s *CommentService) ParseMultipleTickets(ticketKeys []string) (*domain.IssuesList, error) {
+	return s.ParseMultipleTicketsWithContext(context.Background(), ticketKeys)
+}
+
+// ParseMultipleTicketsWithContext обрабатывает тикеты пулом воркеров. При отмене ctx
+// новые тикеты не запускаются, а необработанные попадают в Failures; уже готовые
+// результаты возвращаются вместе с ошибкой ctx.
+func (s *CommentService) ParseMultipleTicketsWithContext(ctx context.Context, ticketKeys []string) (*domain.IssuesList, error) {
 	if len(ticketKeys) == 0 {
 		return &domain.IssuesList{Issues: []domain.Issue{}}, nil
 	}
 
-	issues := make([]domain.Issue, 0, len(ticketKeys))
+	// Кэш репозитория проверяется одним запросом на все тикеты, а не запросом на каждый
+	if revalidator, ok := s.repo.(domain.CacheRevalidator); ok {
+		if err := revalidator.RevalidateCacheWithContext(ctx, ticketKeys); err != nil {
+			log.Printf("Warning: could not revalidate cached tickets: %v", err)
+		}
+	}
+
+	type ticketResult struct {
+		issue *domain.Issue
+		err   error
+		done  bool
+	}
+
+	// Результаты складываются по индексу тикета, чтобы порядок вывода не зависел от воркеров
+	results := make([]ticketResult, len(ticketKeys))
+	jobs := make(chan int)
+
+	workers := s.concurrency
+	if workers > len(ticketKeys) {
+		workers = len(ticketKeys)
+	}
 
-	for _, ticketKey := range ticketKeys {
-		issue, err := s.ParseComments(ticketKey)
-		if err != nil {
-			log.Printf("Error parsing comments for ticket %s: %v", ticketKey, err)
-			// Продолжаем обработку других тикетов даже если один не удался
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				issue, err := s.ParseCommentsWithContext(ctx, ticketKeys[i])
+				results[i] = ticketResult{issue: issue, err: err, done: true}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range ticketKeys {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	issuesList := &domain.IssuesList{Issues: make([]domain.Issue, 0, len(ticketKeys))}
+	for i, result := range results {
+		if !result.done {
+			result.err = fmt.Errorf("not processed: %w", ctx.Err())
+		}
+		if result.err != nil {
+			log.Printf("Error parsing comments for ticket %s: %v", ticketKeys[i], result.err)
+			// Продолжаем обработку других тикетов, а ошибку сохраняем в результате
+			issuesList.Failures = append(issuesList.Failures, domain.TicketFailure{
+				Key:   ticketKeys[i],
+				Error: result.err.Error(),
+			})
 			continue
 		}
-		issues = append(issues, *issue)
+		issuesList.Issues = append(issuesList.Issues, *result.issue)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return issuesList, fmt.Errorf("processing interrupted after %d of %d tickets: %w",
+			len(issuesList.Issues), len(ticketKeys), err)
 	}
 
-	return &domain.IssuesList{Issues: issues}, nil
+	return issuesList, nil
+}
+
+func (s *CommentService) SearchTickets(jql string) ([]string, error) {
+	return s.SearchTicketsWithContext(context.Background(), jql)
+}
+
+func (s *CommentService) SearchTicketsWithContext(ctx context.Context, jql string) ([]string, error) {
+	if strings.TrimSpace(jql) == "" {
+		return nil, fmt.Errorf("jql query cannot be empty")
+	}
+
+	log.Printf("Resolving tickets for JQL query: %s", jql)
+	keys, err := s.repo.SearchIssueKeysWithContext(ctx, jql)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tickets for jql %q: %w", jql, err)
+	}
+
+	return keys, nil
 }
 
 func (s *CommentService) GetLastComment(issueKey string) (*domain.QAComment, error) {
+	return s.GetLastCommentWithContext(context.Background(), issueKey)
+}
+
+func (s *CommentService) GetLastCommentWithContext(ctx context.Context, issueKey string) (*domain.QAComment, error) {
 	if issueKey == "" {
 		return nil, fmt.Errorf("issue key cannot be empty")
 	}
 
 	log.Printf("Getting last QA comment for issue %s", issueKey)
-	comment, err := s.repo.GetLastQAComment(issueKey)
+	rawComments, err := s.repo.GetIssueCommentsWithContext(ctx, issueKey)
 	if err != nil {
 		log.Printf("Error getting last comment for issue %s: %v", issueKey, err)
 		return nil, fmt.Errorf("failed to get last comment for issue %s: %w", issueKey, err)
 	}
 
+	comment := s.lastQAComment(s.parserFor(issueKey), rawComments)
+
 	if comment == nil {
 		log.Printf("No QA comment found for issue %s", issueKey)
 	} else {
@@ -90,3 +233,136 @@ func (s *CommentService) GetLastComment(issueKey string) (*domain.QAComment, err
 
 	return comment, nil
 }
+
+func (s *CommentService) ExplainComments(issueKey, commentID string) ([]domain.CommentExplanation, error) {
+	return s.ExplainCommentsWithContext(context.Background(), issueKey, commentID)
+}
+
+// ExplainCommentsWithContext объясняет разбор всех комментариев тикета парсером его проекта;
+// непустой commentID оставляет только этот комментарий
+func (s *CommentService) ExplainCommentsWithContext(ctx context.Context, issueKey, commentID string) ([]domain.CommentExplanation, error) {
+	if issueKey == "" {
+		return nil, fmt.Errorf("issue key cannot be empty")
+	}
+
+	explainer, err := s.explainerFor(issueKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rawComments, err := s.repo.GetIssueCommentsWithContext(ctx, issueKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments for issue %s: %w", issueKey, err)
+	}
+
+	explanations := []domain.CommentExplanation{}
+	for _, raw := range rawComments {
+		if commentID != "" && raw.ID != commentID {
+			continue
+		}
+		explanations = append(explanations, explainer.ExplainComment(raw))
+	}
+
+	if commentID != "" && len(explanations) == 0 {
+		return nil, fmt.Errorf("comment %s not found in issue %s", commentID, issueKey)
+	}
+	return explanations, nil
+}
+
+// ExplainText объясняет разбор произвольного текста, например тела комментария из stdin.
+// issueKey (или только ключ проекта) выбирает парсер; пустой ключ - парсер по умолчанию.
+func (s *CommentService) ExplainText(issueKey, body string) (domain.CommentExplanation, error) {
+	explainer, err := s.explainerFor(issueKey)
+	if err != nil {
+		return domain.CommentExplanation{}, err
+	}
+	return explainer.ExplainComment(domain.RawComment{Body: body}), nil
+}
+
+// explainerFor возвращает парсер тикета, если он умеет объяснять разбор
+func (s *CommentService) explainerFor(issueKey string) (domain.CommentExplainer, error) {
+	explainer, ok := s.parserFor(issueKey).(domain.CommentExplainer)
+	if !ok {
+		return nil, fmt.Errorf("comment parser for %s cannot explain its decisions", issueKey)
+	}
+	return explainer, nil
+}
+
+// parserFor выбирает парсер по проекту тикета (часть ключа до последнего дефиса);
+// ключ без дефиса считается ключом проекта
+func (s *CommentService) parserFor(issueKey string) domain.CommentParser {
+	project := issueKey
+	if i := strings.LastIndex(issueKey, "-"); i > 0 {
+		project = issueKey[:i]
+	}
+	if parser, ok := s.projectParsers[strings.ToUpper(project)]; ok {
+		return parser
+	}
+	return s.parser
+}
+
+// parseComments оставляет только QA комментарии, сохраняя их порядок.
+// Строгий парсер дополнительно сообщает, какие QA комментарии он отклонил и почему.
+func parseComments(parser domain.CommentParser, rawComments []domain.RawComment) ([]domain.QAComment, []domain.CommentRejection) {
+	strict, isStrict := parser.(domain.StrictCommentParser)
+
+	comments := []domain.QAComment{}
+	var rejections []domain.CommentRejection
+	for _, raw := range rawComments {
+		if !isStrict {
+			if comment, ok := parser.ParseComment(raw); ok {
+				comments = append(comments, comment)
+			}
+			continue
+		}
+
+		comment, ok, reason := strict.CheckComment(raw)
+		switch {
+		case ok:
+			comments = append(comments, comment)
+		case reason != "":
+			rejections = append(rejections, domain.CommentRejection{
+				CommentID:   raw.ID,
+				Created:     raw.Created,
+				AuthorEmail: raw.AuthorEmail,
+				Reason:      reason,
+			})
+		}
+	}
+	return comments, rejections
+}
+
+// filterComments оставляет комментарии, прошедшие фильтр сервиса, сохраняя их порядок
+func (s *CommentService) filterComments(comments []domain.QAComment) []domain.QAComment {
+	if s.filter == nil {
+		return comments
+	}
+	filtered := []domain.QAComment{}
+	for _, comment := range comments {
+		if s.filter.Match(comment) {
+			filtered = append(filtered, comment)
+		}
+	}
+	return filtered
+}
+
+// lastQAComment возвращает последний QA комментарий, прошедший фильтр сервиса, или nil, если таких нет
+func (s *CommentService) lastQAComment(parser domain.CommentParser, rawComments []domain.RawComment) *domain.QAComment {
+	for i := len(rawComments) - 1; i >= 0; i-- {
+		comment, ok := parser.ParseComment(rawComments[i])
+		if ok && (s.filter == nil || s.filter.Match(comment)) {
+			return &comment
+		}
+	}
+	return nil
+}
+
+// lastQAAuthor возвращает email автора последнего QA комментария
+func lastQAAuthor(parser domain.CommentParser, rawComments []domain.RawComment) string {
+	for i := len(rawComments) - 1; i >= 0; i-- {
+		if rawComments[i].AuthorEmail != "" && parser.IsQAComment(rawComments[i].Body) {
+			return rawComments[i].AuthorEmail
+		}
+	}
+	return ""
+}
@@ -1,9 +1,13 @@
 package application
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/rd2w/jira-parser/internal/domain"
 	"github.com/stretchr/testify/assert"
@@ -11,21 +15,25 @@ import (
 
 // MockCommentRepository implements domain.CommentRepository for testing
 type MockCommentRepository struct {
-	GetIssueCommentsFunc func(issueKey string) ([]domain.QAComment, error)
-	GetLastQACommentFunc func(issueKey string) (*domain.QAComment, error)
+	GetIssueCommentsFunc func(issueKey string) ([]domain.RawComment, error)
 	GetIssueInfoFunc     func(issueKey string) (*domain.IssueInfo, error)
+	SearchIssueKeysFunc  func(jql string) ([]string, error)
+	// GetIssueWithCommentsFunc, если не задана, собирается из GetIssueInfoFunc и GetIssueCommentsFunc
+	GetIssueWithCommentsFunc func(issueKey string) (*domain.IssueInfo, []domain.RawComment, error)
 }
 
-func (m *MockCommentRepository) GetIssueComments(issueKey string) ([]domain.QAComment, error) {
-	if m.GetIssueCommentsFunc != nil {
-		return m.GetIssueCommentsFunc(issueKey)
-	}
-	return nil, nil
+// mustVersion разбирает заведомо корректную версию из тестовых данных
+func mustVersion(t *testing.T, value string) domain.Version {
+	t.Helper()
+
+	v, err := domain.ParseVersion(value)
+	assert.NoError(t, err)
+	return v
 }
 
-func (m *MockCommentRepository) GetLastQAComment(issueKey string) (*domain.QAComment, error) {
-	if m.GetLastQACommentFunc != nil {
-		return m.GetLastQACommentFunc(issueKey)
+func (m *MockCommentRepository) GetIssueComments(issueKey string) ([]domain.RawComment, error) {
+	if m.GetIssueCommentsFunc != nil {
+		return m.GetIssueCommentsFunc(issueKey)
 	}
 	return nil, nil
 }
@@ -40,6 +48,131 @@ func (m *MockCommentRepository) GetIssueInfo(issueKey string) (*domain.IssueInfo
 	}, nil
 }
 
+func (m *MockCommentRepository) GetIssueWithComments(issueKey string) (*domain.IssueInfo, []domain.RawComment, error) {
+	if m.GetIssueWithCommentsFunc != nil {
+		return m.GetIssueWithCommentsFunc(issueKey)
+	}
+
+	comments, err := m.GetIssueComments(issueKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := m.GetIssueInfo(issueKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, comments, nil
+}
+
+func (m *MockCommentRepository) SearchIssueKeys(jql string) ([]string, error) {
+	if m.SearchIssueKeysFunc != nil {
+		return m.SearchIssueKeysFunc(jql)
+	}
+	return nil, nil
+}
+
+// Варианты WithContext возвращают ошибку отмененного ctx, иначе делегируют обычным методам
+
+func (m *MockCommentRepository) GetIssueCommentsWithContext(ctx context.Context, issueKey string) ([]domain.RawComment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.GetIssueComments(issueKey)
+}
+
+func (m *MockCommentRepository) GetIssueInfoWithContext(ctx context.Context, issueKey string) (*domain.IssueInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.GetIssueInfo(issueKey)
+}
+
+func (m *MockCommentRepository) GetIssueWithCommentsWithContext(ctx context.Context, issueKey string) (*domain.IssueInfo, []domain.RawComment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	return m.GetIssueWithComments(issueKey)
+}
+
+func (m *MockCommentRepository) SearchIssueKeysWithContext(ctx context.Context, jql string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.SearchIssueKeys(jql)
+}
+
+// stubParser разбирает тела вида "версия|результат|комментарий", созданные rawComments.
+// Остальные комментарии не считаются QA комментариями.
+type stubParser struct {
+	// version, если задана, подменяет версию - так тесты различают парсеры проектов
+	version string
+}
+
+func (p stubParser) IsQAComment(body string) bool {
+	return strings.Count(body, "|") == 2
+}
+
+func (p stubParser) ParseComment(comment domain.RawComment) (domain.QAComment, bool) {
+	if !p.IsQAComment(comment.Body) {
+		return domain.QAComment{}, false
+	}
+	parts := strings.Split(comment.Body, "|")
+	qaComment := domain.QAComment{
+		SoftwareVersion: parts[0],
+		TestResult:      domain.TestOutcome(parts[1]),
+		Comment:         parts[2],
+		Created:         comment.Created,
+		AuthorEmail:     comment.AuthorEmail,
+	}
+	if p.version != "" {
+		qaComment.SoftwareVersion = p.version
+	}
+	qaComment.Version, _ = domain.ParseVersion(qaComment.SoftwareVersion)
+	return qaComment, true
+}
+
+// strictStubParser отклоняет QA комментарии без результата
+type strictStubParser struct {
+	stubParser
+}
+
+func (p strictStubParser) CheckComment(comment domain.RawComment) (domain.QAComment, bool, string) {
+	qaComment, ok := p.stubParser.ParseComment(comment)
+	if ok && qaComment.TestResult == "" {
+		return domain.QAComment{}, false, "missing result"
+	}
+	return qaComment, ok, ""
+}
+
+// explainingStubParser объясняет разбор, возвращая тело комментария как нормализованный текст
+type explainingStubParser struct {
+	stubParser
+}
+
+func (p explainingStubParser) ExplainComment(comment domain.RawComment) domain.CommentExplanation {
+	return domain.CommentExplanation{
+		CommentID:      comment.ID,
+		NormalizedText: comment.Body,
+		IsQAComment:    p.IsQAComment(comment.Body),
+	}
+}
+
+// rawComments кодирует QA комментарии в тела, которые понимает stubParser
+func rawComments(comments []domain.QAComment) []domain.RawComment {
+	if comments == nil {
+		return nil
+	}
+	raw := make([]domain.RawComment, 0, len(comments))
+	for _, comment := range comments {
+		raw = append(raw, domain.RawComment{
+			Body:        comment.SoftwareVersion + "|" + string(comment.TestResult) + "|" + comment.Comment,
+			Created:     comment.Created,
+			AuthorEmail: comment.AuthorEmail,
+		})
+	}
+	return raw
+}
+
 func TestCommentService_ParseComments(t *testing.T) {
 	t.Parallel()
 
@@ -56,12 +189,14 @@ func TestCommentService_ParseComments(t *testing.T) {
 			issueKey: "TEST-123",
 			mockComments: []domain.QAComment{
 				{
-					SoftwareVersion: "v1.0.0",
+					SoftwareVersion: "v1.0.1",
+					Version:         mustVersion(t, "1.0.1"),
 					TestResult:      "Fixed",
 					Comment:         "Test passed successfully",
 				},
 				{
-					SoftwareVersion: "v1.0.1",
+					SoftwareVersion: "v1.0.0",
+					Version:         mustVersion(t, "1.0.0"),
 					TestResult:      "Not Fixed",
 					Comment:         "Issue still exists",
 				},
@@ -91,9 +226,9 @@ func TestCommentService_ParseComments(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockCommentRepository{
-				GetIssueCommentsFunc: func(issueKey string) ([]domain.QAComment, error) {
+				GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
 					assert.Equal(t, tt.issueKey, issueKey)
-					return tt.mockComments, tt.mockError
+					return rawComments(tt.mockComments), tt.mockError
 				},
 				GetIssueInfoFunc: func(issueKey string) (*domain.IssueInfo, error) {
 					assert.Equal(t, tt.issueKey, issueKey)
@@ -104,7 +239,7 @@ func TestCommentService_ParseComments(t *testing.T) {
 				},
 			}
 
-			service := NewCommentService(mockRepo)
+			service := NewCommentService(mockRepo, stubParser{})
 			result, err := service.ParseComments(tt.issueKey)
 
 			if tt.expectError {
@@ -119,17 +254,163 @@ func TestCommentService_ParseComments(t *testing.T) {
 
 				if tt.expectedCount > 0 {
 					assert.Equal(t, tt.mockComments, result.Comments)
+					// Последняя проверенная версия - наибольшая, а не из последнего комментария
+					assert.Equal(t, "1.0.1", result.LatestVersion.String())
 				}
 			}
 		})
 	}
 }
 
+func TestCommentService_ParseCommentsSingleFetch(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	mockRepo := &MockCommentRepository{
+		GetIssueWithCommentsFunc: func(issueKey string) (*domain.IssueInfo, []domain.RawComment, error) {
+			calls++
+			return &domain.IssueInfo{
+				Key:           issueKey,
+				Summary:       "Summary",
+				AssigneeEmail: "dev@example.com",
+				QaOwnerEmail:  "qa@example.com",
+			}, rawComments([]domain.QAComment{
+				{SoftwareVersion: "v1.0.0", TestResult: "Fixed"},
+			}), nil
+		},
+		GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
+			t.Fatalf("GetIssueComments should not be called")
+			return nil, nil
+		},
+		GetIssueInfoFunc: func(issueKey string) (*domain.IssueInfo, error) {
+			t.Fatalf("GetIssueInfo should not be called")
+			return nil, nil
+		},
+	}
+
+	service := NewCommentService(mockRepo, stubParser{})
+	result, err := service.ParseComments("TEST-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "Summary", result.Summary)
+	assert.Equal(t, "dev@example.com", result.AssigneeEmail)
+	assert.Equal(t, "qa@example.com", result.QaOwnerEmail)
+	assert.Len(t, result.Comments, 1)
+}
+
+func TestCommentService_ParseCommentsProjectParser(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockCommentRepository{
+		GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
+			return []domain.RawComment{
+				{Body: "v1.0.0|Fixed|", AuthorEmail: "qa@example.com"},
+				{Body: "Thanks!", AuthorEmail: "dev@example.com"},
+			}, nil
+		},
+	}
+
+	service := NewCommentService(mockRepo, stubParser{}, WithProjectParser("cloud", stubParser{version: "cloud"}))
+
+	result, err := service.ParseComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", result.Comments[0].SoftwareVersion)
+	// Поле QA владельца пустое - используется автор последнего QA комментария
+	assert.Equal(t, "qa@example.com", result.QaOwnerEmail)
+
+	result, err = service.ParseComments("CLOUD-7")
+	assert.NoError(t, err)
+	assert.Len(t, result.Comments, 1)
+	assert.Equal(t, "cloud", result.Comments[0].SoftwareVersion)
+
+	last, err := service.GetLastComment("CLOUD-7")
+	assert.NoError(t, err)
+	assert.Equal(t, "cloud", last.SoftwareVersion)
+}
+
+// resultFilter пропускает комментарии с заданным результатом
+type resultFilter domain.TestOutcome
+
+func (f resultFilter) Match(comment domain.QAComment) bool {
+	return comment.TestResult == domain.TestOutcome(f)
+}
+
+func TestCommentService_CommentFilter(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockCommentRepository{
+		GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
+			return rawComments([]domain.QAComment{
+				{SoftwareVersion: "v1.0.0", TestResult: "Fixed", AuthorEmail: "qa@example.com"},
+				{SoftwareVersion: "v1.1.0", TestResult: "Not Fixed", AuthorEmail: "qa@example.com"},
+				{SoftwareVersion: "v1.2.0", TestResult: "Not Fixed", AuthorEmail: "dev@example.com"},
+			}), nil
+		},
+	}
+
+	service := NewCommentService(mockRepo, stubParser{}, WithCommentFilter(resultFilter("Fixed")))
+
+	result, err := service.ParseComments("TEST-1")
+	assert.NoError(t, err)
+	assert.Len(t, result.Comments, 1)
+	assert.Equal(t, "v1.0.0", result.Comments[0].SoftwareVersion)
+	// Последняя версия считается по отфильтрованным комментариям, а QA владелец - по всем
+	assert.Equal(t, "1.0.0", result.LatestVersion.String())
+	assert.Equal(t, "dev@example.com", result.QaOwnerEmail)
+
+	// Последним считается последний из подходящих комментариев
+	last, err := service.GetLastComment("TEST-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.0", last.SoftwareVersion)
+
+	service = NewCommentService(mockRepo, stubParser{}, WithCommentFilter(resultFilter("Could not test")))
+	result, err = service.ParseComments("TEST-1")
+	assert.NoError(t, err)
+	assert.Empty(t, result.Comments)
+	last, err = service.GetLastComment("TEST-1")
+	assert.NoError(t, err)
+	assert.Nil(t, last)
+}
+
+func TestCommentService_ParseCommentsRejections(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC)
+	mockRepo := &MockCommentRepository{
+		GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
+			return []domain.RawComment{
+				{ID: "100", Body: "v1.0.0||pending deploy", Created: created, AuthorEmail: "qa@example.com"},
+				{ID: "101", Body: "Thanks!", AuthorEmail: "dev@example.com"},
+				{ID: "102", Body: "v1.0.1|Fixed|", AuthorEmail: "qa@example.com"},
+			}, nil
+		},
+	}
+
+	service := NewCommentService(mockRepo, strictStubParser{})
+
+	result, err := service.ParseComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Len(t, result.Comments, 1)
+	assert.Equal(t, "v1.0.1", result.Comments[0].SoftwareVersion)
+	// Обычные комментарии не считаются отклоненными
+	assert.Equal(t, []domain.CommentRejection{{
+		CommentID:   "100",
+		Created:     created,
+		AuthorEmail: "qa@example.com",
+		Reason:      "missing result",
+	}}, result.Rejections)
+
+	last, err := service.GetLastComment("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.0.1", last.SoftwareVersion)
+}
+
 func TestCommentService_GetLastComment(t *testing.T) {
 	t.Parallel()
 
 	lastComment := &domain.QAComment{
 		SoftwareVersion: "v1.0.0",
+		Version:         mustVersion(t, "1.0.0"),
 		TestResult:      "Fixed",
 		Comment:         "Latest test result",
 	}
@@ -171,9 +452,17 @@ func TestCommentService_GetLastComment(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockCommentRepository{
-				GetLastQACommentFunc: func(issueKey string) (*domain.QAComment, error) {
+				GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
 					assert.Equal(t, tt.issueKey, issueKey)
-					return tt.mockComment, tt.mockError
+					if tt.mockError != nil {
+						return nil, tt.mockError
+					}
+					// Последним идет не QA комментарий, перед ним - искомый QA комментарий
+					var comments []domain.RawComment
+					if tt.mockComment != nil {
+						comments = rawComments([]domain.QAComment{{SoftwareVersion: "v0.9.0", TestResult: "Not Fixed"}, *tt.mockComment})
+					}
+					return append(comments, domain.RawComment{Body: "Thanks!"}), nil
 				},
 				GetIssueInfoFunc: func(issueKey string) (*domain.IssueInfo, error) {
 					assert.Equal(t, tt.issueKey, issueKey)
@@ -184,7 +473,7 @@ func TestCommentService_GetLastComment(t *testing.T) {
 				},
 			}
 
-			service := NewCommentService(mockRepo)
+			service := NewCommentService(mockRepo, stubParser{})
 			result, err := service.GetLastComment(tt.issueKey)
 
 			if tt.expectError {
@@ -225,19 +514,20 @@ func TestCommentService_ParseMultipleTickets(t *testing.T) {
 	tests := []struct {
 		name             string
 		ticketKeys       []string
-		mockCommentsFunc func(issueKey string) ([]domain.QAComment, error)
+		mockCommentsFunc func(issueKey string) ([]domain.RawComment, error)
 		expectError      bool
 		expectedIssues   int
+		expectedFailures []string
 	}{
 		{
 			name:       "successful parsing multiple tickets",
 			ticketKeys: []string{"TEST-123", "TEST-456"},
-			mockCommentsFunc: func(issueKey string) ([]domain.QAComment, error) {
+			mockCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
 				switch issueKey {
 				case "TEST-123":
-					return firstIssueComments, nil
+					return rawComments(firstIssueComments), nil
 				case "TEST-456":
-					return secondIssueComments, nil
+					return rawComments(secondIssueComments), nil
 				default:
 					return nil, fmt.Errorf("unexpected issue key: %s", issueKey)
 				}
@@ -255,20 +545,21 @@ func TestCommentService_ParseMultipleTickets(t *testing.T) {
 		{
 			name:       "error on one ticket continues processing",
 			ticketKeys: []string{"TEST-123", "TEST-789", "TEST-456"},
-			mockCommentsFunc: func(issueKey string) ([]domain.QAComment, error) {
+			mockCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
 				switch issueKey {
 				case "TEST-123":
-					return firstIssueComments, nil
+					return rawComments(firstIssueComments), nil
 				case "TEST-789":
 					return nil, fmt.Errorf("error parsing issue")
 				case "TEST-456":
-					return secondIssueComments, nil
+					return rawComments(secondIssueComments), nil
 				default:
 					return nil, fmt.Errorf("unexpected issue key: %s", issueKey)
 				}
 			},
-			expectError:    false,
-			expectedIssues: 2, // Should return 2 successful issues
+			expectError:      false,
+			expectedIssues:   2, // Should return 2 successful issues
+			expectedFailures: []string{"TEST-789"},
 		},
 	}
 
@@ -276,9 +567,6 @@ func TestCommentService_ParseMultipleTickets(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &MockCommentRepository{
 				GetIssueCommentsFunc: tt.mockCommentsFunc,
-				GetLastQACommentFunc: func(issueKey string) (*domain.QAComment, error) {
-					return nil, nil
-				},
 				GetIssueInfoFunc: func(issueKey string) (*domain.IssueInfo, error) {
 					return &domain.IssueInfo{
 						Key:     issueKey,
@@ -287,7 +575,7 @@ func TestCommentService_ParseMultipleTickets(t *testing.T) {
 				},
 			}
 
-			service := NewCommentService(mockRepo)
+			service := NewCommentService(mockRepo, stubParser{})
 			result, err := service.ParseMultipleTickets(tt.ticketKeys)
 
 			if tt.expectError {
@@ -297,7 +585,220 @@ func TestCommentService_ParseMultipleTickets(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 				assert.Equal(t, tt.expectedIssues, len(result.Issues))
+
+				var failedKeys []string
+				for _, failure := range result.Failures {
+					failedKeys = append(failedKeys, failure.Key)
+					assert.NotEmpty(t, failure.Error)
+				}
+				assert.Equal(t, tt.expectedFailures, failedKeys)
+			}
+		})
+	}
+}
+
+func TestCommentService_SearchTickets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		jql          string
+		mockKeys     []string
+		mockError    error
+		expectError  bool
+		expectedKeys []string
+	}{
+		{
+			name:         "successful search",
+			jql:          "project = TOS AND fixVersion = 5.4",
+			mockKeys:     []string{"TOS-1", "TOS-2"},
+			expectedKeys: []string{"TOS-1", "TOS-2"},
+		},
+		{
+			name:        "empty query",
+			jql:         "  ",
+			expectError: true,
+		},
+		{
+			name:        "error from repository",
+			jql:         "filter = 123",
+			mockError:   errors.New("repository error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockCommentRepository{
+				SearchIssueKeysFunc: func(jql string) ([]string, error) {
+					assert.Equal(t, tt.jql, jql)
+					return tt.mockKeys, tt.mockError
+				},
+			}
+
+			service := NewCommentService(mockRepo, stubParser{})
+			keys, err := service.SearchTickets(tt.jql)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, keys)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedKeys, keys)
 			}
 		})
 	}
 }
+
+func TestCommentService_ParseMultipleTicketsConcurrent(t *testing.T) {
+	t.Parallel()
+
+	ticketKeys := make([]string, 20)
+	for i := range ticketKeys {
+		ticketKeys[i] = fmt.Sprintf("TEST-%d", i+1)
+	}
+
+	var inFlight, maxInFlight int32
+	mockRepo := &MockCommentRepository{
+		GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				observed := atomic.LoadInt32(&maxInFlight)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+
+			if issueKey == "TEST-7" {
+				return nil, errors.New("boom")
+			}
+			return rawComments([]domain.QAComment{{TestResult: "Fixed"}}), nil
+		},
+	}
+
+	service := NewCommentService(mockRepo, stubParser{}, WithConcurrency(4))
+	result, err := service.ParseMultipleTickets(ticketKeys)
+	assert.NoError(t, err)
+
+	// Порядок должен совпадать с порядком входных тикетов
+	assert.Len(t, result.Issues, 19)
+	expectedKey := 0
+	for _, issue := range result.Issues {
+		if ticketKeys[expectedKey] == "TEST-7" {
+			expectedKey++
+		}
+		assert.Equal(t, ticketKeys[expectedKey], issue.Key)
+		expectedKey++
+	}
+
+	assert.Len(t, result.Failures, 1)
+	assert.Equal(t, "TEST-7", result.Failures[0].Key)
+	assert.Contains(t, result.Failures[0].Error, "boom")
+	assert.LessOrEqual(t, int(maxInFlight), 4)
+	assert.Greater(t, int(maxInFlight), 1)
+}
+
+// revalidatingRepository запоминает вызовы RevalidateCacheWithContext
+type revalidatingRepository struct {
+	MockCommentRepository
+	revalidated [][]string
+	fetched     int32
+	// fetchedBefore - число тикетов, загруженных до проверки кэша
+	fetchedBefore int32
+}
+
+func (r *revalidatingRepository) RevalidateCacheWithContext(ctx context.Context, issueKeys []string) error {
+	r.revalidated = append(r.revalidated, append([]string{}, issueKeys...))
+	r.fetchedBefore = atomic.LoadInt32(&r.fetched)
+	return nil
+}
+
+func TestCommentService_ParseMultipleTicketsRevalidatesCacheOnce(t *testing.T) {
+	t.Parallel()
+
+	repo := &revalidatingRepository{}
+	repo.GetIssueCommentsFunc = func(issueKey string) ([]domain.RawComment, error) {
+		atomic.AddInt32(&repo.fetched, 1)
+		return rawComments([]domain.QAComment{{TestResult: "Fixed"}}), nil
+	}
+
+	service := NewCommentService(repo, stubParser{}, WithConcurrency(2))
+	result, err := service.ParseMultipleTickets([]string{"TEST-1", "TEST-2", "TEST-3"})
+	assert.NoError(t, err)
+	assert.Len(t, result.Issues, 3)
+	assert.Equal(t, [][]string{{"TEST-1", "TEST-2", "TEST-3"}}, repo.revalidated)
+	assert.Equal(t, int32(0), repo.fetchedBefore)
+	assert.Equal(t, int32(3), repo.fetched)
+}
+
+func TestCommentService_ParseMultipleTicketsCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockRepo := &MockCommentRepository{
+		GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
+			// Отменяем пакет после обработки второго тикета
+			if issueKey == "TEST-2" {
+				cancel()
+			}
+			return rawComments([]domain.QAComment{{TestResult: "Fixed"}}), nil
+		},
+	}
+
+	service := NewCommentService(mockRepo, stubParser{})
+	result, err := service.ParseMultipleTicketsWithContext(ctx, []string{"TEST-1", "TEST-2", "TEST-3", "TEST-4"})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	// Уже обработанные тикеты возвращаются, остальные попадают в Failures
+	assert.NotNil(t, result)
+	assert.Len(t, result.Issues, 2)
+	assert.Equal(t, "TEST-1", result.Issues[0].Key)
+	assert.Equal(t, "TEST-2", result.Issues[1].Key)
+	assert.Len(t, result.Failures, 2)
+	assert.Equal(t, "TEST-3", result.Failures[0].Key)
+	assert.Contains(t, result.Failures[0].Error, "context canceled")
+}
+
+func TestCommentService_ExplainComments(t *testing.T) {
+	t.Parallel()
+
+	mockRepo := &MockCommentRepository{
+		GetIssueCommentsFunc: func(issueKey string) ([]domain.RawComment, error) {
+			return []domain.RawComment{
+				{ID: "1", Body: "v1.0.0|Fixed|"},
+				{ID: "2", Body: "Thanks!"},
+			}, nil
+		},
+	}
+
+	service := NewCommentService(mockRepo, explainingStubParser{}, WithProjectParser("cloud", stubParser{}))
+
+	explanations, err := service.ExplainComments("TOS-1", "")
+	assert.NoError(t, err)
+	assert.Len(t, explanations, 2)
+	assert.True(t, explanations[0].IsQAComment)
+	assert.False(t, explanations[1].IsQAComment)
+
+	explanations, err = service.ExplainComments("TOS-1", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.CommentExplanation{{CommentID: "2", NormalizedText: "Thanks!"}}, explanations)
+
+	_, err = service.ExplainComments("TOS-1", "3")
+	assert.ErrorContains(t, err, "comment 3 not found in issue TOS-1")
+
+	// Парсер проекта CLOUD не умеет объяснять разбор
+	_, err = service.ExplainComments("CLOUD-1", "")
+	assert.ErrorContains(t, err, "cannot explain")
+
+	explanation, err := service.ExplainText("", "v2|Fixed|")
+	assert.NoError(t, err)
+	assert.True(t, explanation.IsQAComment)
+
+	// Ключ без номера тикета выбирает парсер проекта
+	_, err = service.ExplainText("cloud", "v2|Fixed|")
+	assert.Error(t, err)
+}
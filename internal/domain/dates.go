@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LastSprint - относительная дата: начало спринта, предшествующего текущему, по SprintCalendar
+const LastSprint = "last-sprint"
+
+// SprintCalendar описывает спринты для даты last-sprint: спринты длиной Days дней идут подряд,
+// один из них начинается в Start. Нулевой календарь означает DefaultSprintCalendar.
+type SprintCalendar struct {
+	Start time.Time
+	Days  int
+}
+
+// DefaultSprintCalendar - двухнедельные спринты с понедельника, первый из них начинается 2024-01-01 в loc
+func DefaultSprintCalendar(loc *time.Location) SprintCalendar {
+	if loc == nil {
+		loc = time.Local
+	}
+	return SprintCalendar{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, loc), Days: 14}
+}
+
+// NewSprintCalendar разбирает секцию sprint файла config.yaml: start - дата начала любого спринта
+// в формате YYYY-MM-DD, length - длительность в днях или неделях (14d, 2w). Пустые значения
+// берутся из DefaultSprintCalendar.
+func NewSprintCalendar(start, length string, loc *time.Location) (SprintCalendar, error) {
+	calendar := DefaultSprintCalendar(loc)
+	if start = strings.TrimSpace(start); start != "" {
+		t, err := time.ParseInLocation("2006-01-02", start, calendar.Start.Location())
+		if err != nil {
+			return SprintCalendar{}, fmt.Errorf("invalid sprint start %q, use YYYY-MM-DD", start)
+		}
+		calendar.Start = t
+	}
+	if length = strings.TrimSpace(length); length != "" {
+		days := 0
+		if n, err := strconv.Atoi(length[:len(length)-1]); err == nil && n > 0 {
+			switch length[len(length)-1] {
+			case 'd':
+				days = n
+			case 'w':
+				days = 7 * n
+			}
+		}
+		if days == 0 {
+			return SprintCalendar{}, fmt.Errorf("invalid sprint length %q, use days or weeks like 14d or 2w", length)
+		}
+		calendar.Days = days
+	}
+	return calendar, nil
+}
+
+// PreviousStart возвращает начало спринта, предшествующего спринту, в который попадает now.
+// Спринты отсчитываются в календарных днях пояса Start, поэтому переход на летнее время их не сдвигает.
+func (c SprintCalendar) PreviousStart(now time.Time) time.Time {
+	loc := c.Start.Location()
+	day := func(t time.Time) time.Time {
+		y, m, d := t.In(loc).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	elapsed := int(day(now).Sub(day(c.Start)).Hours() / 24)
+	sprints := elapsed / c.Days
+	if elapsed < 0 && elapsed%c.Days != 0 {
+		sprints--
+	}
+	return c.Start.AddDate(0, 0, (sprints-1)*c.Days)
+}
+
+// relativeUnits - единицы относительных дат, например 12h, 7d или 2w
+var relativeUnits = map[byte]time.Duration{
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// ParseDate разбирает границу фильтра по дате. Поддерживаются RFC3339 (2025-08-12T16:35:38+03:00),
+// дата YYYY-MM-DD (полночь в loc), относительные значения 12h, 7d, 2w (столько времени назад от now)
+// и last-sprint (начало предыдущего спринта по sprints). loc == nil означает локальный часовой пояс.
+func ParseDate(value string, now time.Time, loc *time.Location, sprints SprintCalendar) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if loc == nil {
+		loc = time.Local
+	}
+
+	if strings.EqualFold(value, LastSprint) {
+		if sprints.Days <= 0 {
+			sprints = DefaultSprintCalendar(loc)
+		}
+		return sprints.PreviousStart(now), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, nil
+	}
+	if len(value) > 1 {
+		if unit, ok := relativeUnits[value[len(value)-1]]; ok {
+			if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+				return now.Add(-time.Duration(n) * unit), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD, RFC3339, a relative value like 7d, 2w or 12h, or %s", value, LastSprint)
+}
+
+// DateRange - интервал дат фильтра; нулевая граница не ограничивает интервал
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseDateRange разбирает значения --date-from и --date-to; пустое значение оставляет границу открытой
+func ParseDateRange(from, to string, now time.Time, loc *time.Location, sprints SprintCalendar) (DateRange, error) {
+	var r DateRange
+	var err error
+	if from != "" {
+		if r.From, err = ParseDate(from, now, loc, sprints); err != nil {
+			return DateRange{}, fmt.Errorf("date-from: %w", err)
+		}
+	}
+	if to != "" {
+		if r.To, err = ParseDate(to, now, loc, sprints); err != nil {
+			return DateRange{}, fmt.Errorf("date-to: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// IsZero сообщает, что интервал ничего не ограничивает
+func (r DateRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Contains проверяет, что t попадает в интервал включительно. Неизвестная (нулевая) дата
+// не попадает ни в один ограниченный интервал.
+func (r DateRange) Contains(t time.Time) bool {
+	if r.IsZero() {
+		return true
+	}
+	if t.IsZero() {
+		return false
+	}
+	if !r.From.IsZero() && t.Before(r.From) {
+		return false
+	}
+	if !r.To.IsZero() && t.After(r.To) {
+		return false
+	}
+	return true
+}
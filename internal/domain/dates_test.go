@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	now := time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{value: "2025-08-12", expected: time.Date(2025, 8, 12, 0, 0, 0, 0, moscow)},
+		{value: "2025-08-12T16:35:38+03:00", expected: time.Date(2025, 8, 12, 13, 35, 38, 0, time.UTC)},
+		{value: "2025-08-12T13:35:38Z", expected: time.Date(2025, 8, 12, 13, 35, 38, 0, time.UTC)},
+		{value: "12h", expected: now.Add(-12 * time.Hour)},
+		{value: "7d", expected: now.AddDate(0, 0, -7)},
+		{value: "2w", expected: now.AddDate(0, 0, -14)},
+		{value: "0d", expected: now},
+		// Двухнедельные спринты с 2024-01-01: текущий начался 2025-08-11, предыдущий - 2025-07-28
+		{value: "last-sprint", expected: time.Date(2025, 7, 28, 0, 0, 0, 0, moscow)},
+		{value: " Last-Sprint ", expected: time.Date(2025, 7, 28, 0, 0, 0, 0, moscow)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDate(tt.value, now, moscow, SprintCalendar{})
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(got), "got %s", got)
+		})
+	}
+
+	for _, value := range []string{"", "yesterday", "7", "-3d", "7y", "12.08.2025"} {
+		_, err := ParseDate(value, now, moscow, SprintCalendar{})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestSprintCalendar(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	sprints, err := NewSprintCalendar("2025-01-06", "3w", berlin)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		now      time.Time
+		expected time.Time
+	}{
+		// Первый день спринта уже относится к нему
+		{now: time.Date(2025, 3, 31, 0, 0, 0, 0, berlin), expected: time.Date(2025, 3, 10, 0, 0, 0, 0, berlin)},
+		// Переход на летнее время 30 марта не сдвигает начало спринтов
+		{now: time.Date(2025, 4, 21, 0, 30, 0, 0, berlin), expected: time.Date(2025, 3, 31, 0, 0, 0, 0, berlin)},
+		// Спринты продолжаются и до start
+		{now: time.Date(2025, 1, 1, 12, 0, 0, 0, berlin), expected: time.Date(2024, 11, 25, 0, 0, 0, 0, berlin)},
+	}
+	for _, tt := range tests {
+		assert.True(t, tt.expected.Equal(sprints.PreviousStart(tt.now)), "now %s: got %s", tt.now, sprints.PreviousStart(tt.now))
+	}
+
+	got, err := ParseDate(LastSprint, time.Date(2025, 3, 31, 9, 0, 0, 0, berlin), time.UTC, sprints)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2025, 3, 10, 0, 0, 0, 0, berlin).Equal(got))
+
+	defaults, err := NewSprintCalendar("", "", time.UTC)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSprintCalendar(time.UTC), defaults)
+
+	_, err = NewSprintCalendar("06.01.2025", "", time.UTC)
+	assert.EqualError(t, err, `invalid sprint start "06.01.2025", use YYYY-MM-DD`)
+	for _, length := range []string{"2", "0w", "2m", "w"} {
+		_, err = NewSprintCalendar("", length, time.UTC)
+		assert.ErrorContains(t, err, "invalid sprint length", length)
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+	r, err := ParseDateRange("7d", "2025-08-19", now, time.UTC, SprintCalendar{})
+	assert.NoError(t, err)
+
+	assert.True(t, r.Contains(time.Date(2025, 8, 13, 12, 0, 0, 0, time.UTC)))
+	assert.True(t, r.Contains(time.Date(2025, 8, 19, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, r.Contains(time.Date(2025, 8, 13, 11, 59, 0, 0, time.UTC)))
+	assert.False(t, r.Contains(time.Date(2025, 8, 19, 0, 0, 1, 0, time.UTC)))
+	// Неизвестная дата не попадает в ограниченный интервал
+	assert.False(t, r.Contains(time.Time{}))
+
+	open, err := ParseDateRange("", "", now, nil, SprintCalendar{})
+	assert.NoError(t, err)
+	assert.True(t, open.IsZero())
+	assert.True(t, open.Contains(time.Time{}))
+
+	_, err = ParseDateRange("", "soon", now, nil, SprintCalendar{})
+	assert.EqualError(t, err, `date-to: invalid date "soon", use YYYY-MM-DD, RFC3339, a relative value like 7d, 2w or 12h, or last-sprint`)
+}
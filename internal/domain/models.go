@@ -1,12 +1,35 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
+// SchemaVersion - версия формата тикетов в JSON экспорте и выводе --output json, jsonl и yaml (JSON Schema
+// выводит команда schema). Имена полей задаются тегами json и yaml ниже; при переименовании или удалении
+// поля версия увеличивается.
+const SchemaVersion = 1
+
 // QAComment представляет структурированный комментарий QA
 type QAComment struct {
-	SoftwareVersion string
-	TestResult      string // "Fixed", "Not Fixed", "Partially Fixed", "Could not test"
-	Comment         string
-	Created         string // Дата создания комментария в формате RFC339
-	AuthorEmail     string // Email автора комментария
+	SoftwareVersion string          `json:"software_version" yaml:"software_version"` // Версия в том виде, в котором ее написал QA
+	Version         Version         `json:"version" yaml:"version"`                   // Разобранная SoftwareVersion; пустая, если версия не распознана
+	TestResult      TestOutcome     `json:"test_result" yaml:"test_result"`           // "Fixed", "Not Fixed", "Partially Fixed", "Could not test"
+	Category        OutcomeCategory `json:"category" yaml:"category"`                 // Категория TestResult: pass, fail, partial, blocked или unknown
+	Comment         string          `json:"comment" yaml:"comment"`
+	Created         time.Time       `json:"created,omitzero" yaml:"created,omitempty"` // Дата создания комментария; нулевая, если JIRA вернула неизвестный формат
+	Updated         time.Time       `json:"updated,omitzero" yaml:"updated,omitempty"` // Дата последнего изменения комментария
+	AuthorEmail     string          `json:"author_email" yaml:"author_email"`          // Email автора комментария
+	// Results - результаты отдельных сценариев, если комментарий описывает несколько проверок
+	Results []TestCaseResult `json:"results,omitempty" yaml:"results,omitempty"`
+}
+
+// TestCaseResult представляет результат одного сценария внутри QA комментария
+type TestCaseResult struct {
+	Scenario string          `json:"scenario" yaml:"scenario"`             // Название сценария, например "Login"
+	Result   TestOutcome     `json:"result" yaml:"result"`                 // Результат после нормализации, например "Fixed"
+	Category OutcomeCategory `json:"category" yaml:"category"`             // Категория результата
+	Note     string          `json:"note,omitempty" yaml:"note,omitempty"` // Необязательное пояснение
 }
 
 // IssueInfo содержит основную информацию о JIRA тикете
@@ -14,21 +37,44 @@ type IssueInfo struct {
 	Key           string
 	Summary       string
 	AssigneeEmail string // Email назначенного
-	QaOwnerEmail  string // Email QA владельца (пользователя, оставляющего QA комментарии)
+	QaOwnerEmail  string // Email QA владельца из поля тикета; пусто, если поле не заполнено
+	// CommentsIncomplete означает, что не все комментарии тикета удалось загрузить
+	CommentsIncomplete bool
 }
 
 // Issue представляет JIRA тикет с комментариями
 type Issue struct {
-	Key           string
-	Summary       string
-	AssigneeEmail string // Email назначенного
-	QaOwnerEmail  string // Email QA владельца (пользователя, оставляющего QA комментарии)
-	Comments      []QAComment
+	Key           string      `json:"key" yaml:"key"`
+	Summary       string      `json:"summary" yaml:"summary"`
+	AssigneeEmail string      `json:"assignee_email" yaml:"assignee_email"` // Email назначенного
+	QaOwnerEmail  string      `json:"qa_owner_email" yaml:"qa_owner_email"` // Email QA владельца (пользователя, оставляющего QA комментарии)
+	Comments      []QAComment `json:"comments" yaml:"comments"`
+	// LatestVersion - наибольшая версия, на которой тикет проверялся в QA комментариях
+	LatestVersion Version `json:"latest_version" yaml:"latest_version"`
+	// CommentsIncomplete означает, что часть комментариев не была загружена и QA комментарии могут быть неполными
+	CommentsIncomplete bool `json:"comments_incomplete" yaml:"comments_incomplete"`
+	// Rejections - QA комментарии, не прошедшие строгую проверку по шаблону
+	Rejections []CommentRejection `json:"rejections,omitempty" yaml:"rejections,omitempty"`
+}
+
+// CommentRejection описывает QA комментарий, отклоненный строгим парсером
+type CommentRejection struct {
+	CommentID   string    `json:"comment_id" yaml:"comment_id"`
+	Created     time.Time `json:"created,omitzero" yaml:"created,omitempty"`
+	AuthorEmail string    `json:"author_email" yaml:"author_email"`
+	Reason      string    `json:"reason" yaml:"reason"` // Почему комментарий не соответствует шаблону
+}
+
+// TicketFailure описывает тикет, который не удалось обработать
+type TicketFailure struct {
+	Key   string `json:"key" yaml:"key"`
+	Error string `json:"error" yaml:"error"`
 }
 
 // IssuesList представляет список JIRA тикетов с комментариями
 type IssuesList struct {
-	Issues []Issue
+	Issues   []Issue         `json:"issues" yaml:"issues"`
+	Failures []TicketFailure `json:"failures" yaml:"failures"` // Тикеты, обработка которых завершилась ошибкой
 }
 
 // ParsingConfig содержит настройки для парсинга комментариев
@@ -38,18 +84,132 @@ type ParsingConfig struct {
 	CommentPatterns     []string          `mapstructure:"comment_patterns"`
 	QAIndicators        []string          `mapstructure:"qa_indicators"`
 	ResultNormalization map[string]string `mapstructure:"result_normalization"`
+	// ResultCategories задает категории результатов (pass, fail, partial, blocked, unknown)
+	// поверх DefaultOutcomeCategories, например "Re-Test": blocked
+	ResultCategories map[string]string `mapstructure:"result_categories"`
+	// Template задает структуру QA комментария для строгого разбора; nil - шаблон по умолчанию
+	Template *CommentTemplate `mapstructure:"template"`
 }
 
-// CommentRepository интерфейс для работы с комментариями
+// CommentTemplate описывает ожидаемую структуру QA комментария
+type CommentTemplate struct {
+	// Fields перечислены в том порядке, в котором они должны идти в комментарии
+	Fields []TemplateField `mapstructure:"fields"`
+}
+
+// TemplateField - поле шаблона QA комментария
+type TemplateField struct {
+	Name     string   `mapstructure:"name"`     // version, result или comment
+	Labels   []string `mapstructure:"labels"`   // Подписи поля, например "Tested on" или "Result"
+	Values   []string `mapstructure:"values"`   // Допустимые значения после нормализации; пусто - любые
+	Pattern  string   `mapstructure:"pattern"`  // Регулярное выражение для значения; первая группа становится значением
+	Required bool     `mapstructure:"required"` // Комментарий без этого поля отклоняется
+}
+
+// RawComment представляет комментарий JIRA до разбора
+type RawComment struct {
+	ID          string
+	Body        string    // Текст комментария в wiki-разметке (ADF заранее переводится в текст)
+	Created     time.Time // Дата создания комментария, разобранная репозиторием
+	Updated     time.Time // Дата последнего изменения комментария
+	AuthorEmail string    // Email автора комментария
+}
+
+// CommentParser распознает QA комментарии и извлекает из них структурированные данные
+type CommentParser interface {
+	// IsQAComment сообщает, является ли текст комментария QA комментарием
+	IsQAComment(body string) bool
+	// ParseComment разбирает комментарий; ok == false, если это не QA комментарий
+	// или в нем нет значимых данных
+	ParseComment(comment RawComment) (qaComment QAComment, ok bool)
+}
+
+// CommentExplainer - парсер, который умеет пошагово объяснить разбор комментария
+type CommentExplainer interface {
+	ExplainComment(comment RawComment) CommentExplanation
+}
+
+// CommentExplanation описывает, как парсер разобрал комментарий
+type CommentExplanation struct {
+	CommentID      string
+	NormalizedText string             // Текст после удаления JIRA-разметки
+	Indicators     []IndicatorCheck   // Результат проверки каждого QA индикатора
+	IsQAComment    bool               // Сработал ли хотя бы один индикатор
+	Fields         []FieldExplanation // Разбор версии, результата и комментария
+	Comment        *QAComment         // Итог разбора; nil, если комментарий не принят
+	Rejection      string             // Причина отказа строгого парсера
+}
+
+// IndicatorCheck - проверка одного QA индикатора
+type IndicatorCheck struct {
+	Indicator string
+	Match     string // Как сработал индикатор (substring, regex); пусто - не сработал
+}
+
+// FieldExplanation описывает поиск одного поля QA комментария
+type FieldExplanation struct {
+	Name          string // version, result или comment
+	Attempts      []PatternAttempt
+	Value         string
+	Source        string // Откуда взято значение: шаблон, эвристика и т.п.
+	Normalization string // Примененная запись result_normalization, например "failed -> Not Fixed"
+}
+
+// PatternAttempt - применение одного регулярного выражения к тексту комментария
+type PatternAttempt struct {
+	Pattern string
+	Value   string // Захваченное значение, если выражение сработало
+	Failure string // Почему выражение не сработало; пусто - сработало
+}
+
+// StrictCommentParser - парсер, который сообщает, почему QA комментарий не принят
+type StrictCommentParser interface {
+	CommentParser
+	// CheckComment разбирает комментарий как ParseComment; для QA комментария,
+	// не соответствующего ожидаемой структуре, reason содержит причину отказа
+	CheckComment(comment RawComment) (qaComment QAComment, ok bool, reason string)
+}
+
+// CommentFilter отбирает разобранные QA комментарии, например скомпилированное выражение --filter
+type CommentFilter interface {
+	Match(comment QAComment) bool
+}
+
+// CommentRepository интерфейс для загрузки тикетов и их комментариев без разбора.
+// Варианты с суффиксом WithContext прерывают запросы при отмене ctx.
 type CommentRepository interface {
-	GetIssueComments(issueKey string) ([]QAComment, error)
-	GetLastQAComment(issueKey string) (*QAComment, error)
+	GetIssueComments(issueKey string) ([]RawComment, error)
+	GetIssueCommentsWithContext(ctx context.Context, issueKey string) ([]RawComment, error)
 	GetIssueInfo(issueKey string) (*IssueInfo, error)
+	GetIssueInfoWithContext(ctx context.Context, issueKey string) (*IssueInfo, error)
+	// GetIssueWithComments загружает тикет одним запросом и возвращает информацию о нем и все комментарии
+	GetIssueWithComments(issueKey string) (*IssueInfo, []RawComment, error)
+	GetIssueWithCommentsWithContext(ctx context.Context, issueKey string) (*IssueInfo, []RawComment, error)
+	SearchIssueKeys(jql string) ([]string, error)
+	SearchIssueKeysWithContext(ctx context.Context, jql string) ([]string, error)
+}
+
+// CacheRevalidator - репозиторий с кэшем, который проверяет актуальность нескольких
+// тикетов одним запросом перед их загрузкой
+type CacheRevalidator interface {
+	RevalidateCacheWithContext(ctx context.Context, issueKeys []string) error
 }
 
-// CommentService интерфейс для бизнес-логики
+// CommentService интерфейс для бизнес-логики.
+// Варианты с суффиксом WithContext прерывают обработку при отмене ctx.
 type CommentService interface {
 	ParseComments(issueKey string) (*Issue, error)
+	ParseCommentsWithContext(ctx context.Context, issueKey string) (*Issue, error)
 	GetLastComment(issueKey string) (*QAComment, error)
+	GetLastCommentWithContext(ctx context.Context, issueKey string) (*QAComment, error)
 	ParseMultipleTickets(ticketKeys []string) (*IssuesList, error)
+	// ParseMultipleTicketsWithContext при отмене ctx возвращает уже обработанные тикеты вместе с ошибкой ctx
+	ParseMultipleTicketsWithContext(ctx context.Context, ticketKeys []string) (*IssuesList, error)
+	SearchTickets(jql string) ([]string, error)
+	SearchTicketsWithContext(ctx context.Context, jql string) ([]string, error)
+	// ExplainComments объясняет разбор комментариев тикета; непустой commentID оставляет один комментарий
+	ExplainComments(issueKey, commentID string) ([]CommentExplanation, error)
+	ExplainCommentsWithContext(ctx context.Context, issueKey, commentID string) ([]CommentExplanation, error)
+	// ExplainText объясняет разбор произвольного текста парсером проекта тикета issueKey
+	ExplainText(issueKey, body string) (CommentExplanation, error)
 }
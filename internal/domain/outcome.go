@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TestOutcome - результат тестирования в каноническом написании, например "Fixed"
+type TestOutcome string
+
+// Канонические результаты, которые распознаются без настройки
+const (
+	OutcomeFixed          TestOutcome = "Fixed"
+	OutcomeNotFixed       TestOutcome = "Not Fixed"
+	OutcomePartiallyFixed TestOutcome = "Partially Fixed"
+	OutcomeCouldNotTest   TestOutcome = "Could not test"
+	OutcomeNotApplicable  TestOutcome = "N/A"
+)
+
+// OutcomeCategory группирует результаты по смыслу; по категории выбираются цвета и фильтры
+type OutcomeCategory string
+
+const (
+	CategoryPass    OutcomeCategory = "pass"
+	CategoryFail    OutcomeCategory = "fail"
+	CategoryPartial OutcomeCategory = "partial"
+	CategoryBlocked OutcomeCategory = "blocked"
+	CategoryUnknown OutcomeCategory = "unknown"
+)
+
+// OutcomeCategories перечисляет все категории в порядке от успешной к неизвестной
+var OutcomeCategories = []OutcomeCategory{CategoryPass, CategoryFail, CategoryPartial, CategoryBlocked, CategoryUnknown}
+
+// ParseOutcomeCategory проверяет имя категории без учета регистра
+func ParseOutcomeCategory(value string) (OutcomeCategory, error) {
+	for _, category := range OutcomeCategories {
+		if strings.EqualFold(value, string(category)) {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("unknown result category %q, use pass, fail, partial, blocked or unknown", value)
+}
+
+// DefaultOutcomeCategories возвращает категории результатов, известных без настройки
+func DefaultOutcomeCategories() map[TestOutcome]OutcomeCategory {
+	return map[TestOutcome]OutcomeCategory{
+		OutcomeFixed:          CategoryPass,
+		"Passed":              CategoryPass,
+		"Verified":            CategoryPass,
+		"Resolved":            CategoryPass,
+		"OK":                  CategoryPass,
+		OutcomeNotFixed:       CategoryFail,
+		"Failed":              CategoryFail,
+		"NOK":                 CategoryFail,
+		OutcomePartiallyFixed: CategoryPartial,
+		"Partially OK":        CategoryPartial,
+		OutcomeCouldNotTest:   CategoryBlocked,
+		"Blocked":             CategoryBlocked,
+		"Pending":             CategoryBlocked,
+		"In Progress":         CategoryBlocked,
+		OutcomeNotApplicable:  CategoryUnknown,
+	}
+}
+
+// OutcomeClassifier приводит найденные в комментариях результаты к каноническому виду
+// и определяет их категорию. Единственный источник правил - ResultNormalization и ResultCategories.
+type OutcomeClassifier struct {
+	normalization map[string]string
+	// canonical сопоставляет результат в нижнем регистре его каноническому написанию
+	canonical map[string]TestOutcome
+	// categories хранит категории результатов по ключу в нижнем регистре
+	categories map[string]OutcomeCategory
+}
+
+// NewOutcomeClassifier строит классификатор из настроек разбора. Категории из
+// config.ResultCategories дополняют и переопределяют DefaultOutcomeCategories без учета регистра;
+// нераспознанные имена категорий считаются unknown (их проверяет загрузка конфигурации).
+func NewOutcomeClassifier(config ParsingConfig) *OutcomeClassifier {
+	c := &OutcomeClassifier{
+		normalization: make(map[string]string, len(config.ResultNormalization)),
+		canonical:     make(map[string]TestOutcome),
+		categories:    make(map[string]OutcomeCategory),
+	}
+
+	for outcome, category := range DefaultOutcomeCategories() {
+		key := strings.ToLower(string(outcome))
+		c.canonical[key] = outcome
+		c.categories[key] = category
+	}
+	// Ключи из конфигурации могут прийти в нижнем регистре (viper), поэтому их написание
+	// не становится каноническим: результат остается таким, как его написал QA
+	for outcome, category := range config.ResultCategories {
+		parsed, err := ParseOutcomeCategory(category)
+		if err != nil {
+			parsed = CategoryUnknown
+		}
+		c.categories[strings.ToLower(strings.TrimSpace(outcome))] = parsed
+	}
+	for from, to := range config.ResultNormalization {
+		c.normalization[strings.ToLower(from)] = to
+		// Цели нормализации тоже канонические, даже если для них не задана категория
+		if _, ok := c.canonical[strings.ToLower(to)]; !ok {
+			c.canonical[strings.ToLower(to)] = TestOutcome(to)
+		}
+	}
+	return c
+}
+
+// Normalize применяет ResultNormalization и приводит результат к каноническому написанию:
+// "fixed", "FIXED" и "passed" (при passed: Fixed) дают "Fixed". Неизвестный результат
+// возвращается как есть, без пробелов по краям.
+func (c *OutcomeClassifier) Normalize(raw string) TestOutcome {
+	result := strings.TrimSpace(raw)
+	if result == "" {
+		return ""
+	}
+	if normalized, ok := c.normalization[strings.ToLower(result)]; ok {
+		result = normalized
+	}
+	if canonical, ok := c.canonical[strings.ToLower(result)]; ok {
+		return canonical
+	}
+	return TestOutcome(result)
+}
+
+// Category возвращает категорию результата; для неизвестных результатов - CategoryUnknown
+func (c *OutcomeClassifier) Category(outcome TestOutcome) OutcomeCategory {
+	if category, ok := c.categories[strings.ToLower(string(c.Normalize(string(outcome))))]; ok {
+		return category
+	}
+	return CategoryUnknown
+}
+
+// Outcomes возвращает все канонические результаты в алфавитном порядке
+func (c *OutcomeClassifier) Outcomes() []TestOutcome {
+	seen := make(map[TestOutcome]bool)
+	for _, outcome := range c.canonical {
+		seen[outcome] = true
+	}
+	outcomes := make([]TestOutcome, 0, len(seen))
+	for outcome := range seen {
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Slice(outcomes, func(i, j int) bool { return outcomes[i] < outcomes[j] })
+	return outcomes
+}
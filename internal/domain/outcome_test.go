@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutcomeClassifier(t *testing.T) {
+	t.Parallel()
+
+	c := NewOutcomeClassifier(ParsingConfig{
+		ResultNormalization: map[string]string{
+			"passed":  "Fixed",
+			"retest":  "Needs Retest",
+			"blocked": "Not Fixed",
+		},
+		ResultCategories: map[string]string{
+			"needs retest": "blocked",
+			"partially ok": "fail",
+			"flaky":        "bogus",
+		},
+	})
+
+	tests := []struct {
+		raw      string
+		outcome  TestOutcome
+		category OutcomeCategory
+	}{
+		{raw: "Fixed", outcome: OutcomeFixed, category: CategoryPass},
+		{raw: " fixed ", outcome: OutcomeFixed, category: CategoryPass},
+		{raw: "PASSED", outcome: OutcomeFixed, category: CategoryPass},
+		{raw: "not fixed", outcome: OutcomeNotFixed, category: CategoryFail},
+		{raw: "Blocked", outcome: OutcomeNotFixed, category: CategoryFail},
+		{raw: "could not test", outcome: OutcomeCouldNotTest, category: CategoryBlocked},
+		{raw: "Partially Fixed", outcome: OutcomePartiallyFixed, category: CategoryPartial},
+		// Настройка переопределяет встроенную категорию без учета регистра
+		{raw: "Partially OK", outcome: "Partially OK", category: CategoryFail},
+		{raw: "retest", outcome: "Needs Retest", category: CategoryBlocked},
+		{raw: "Flaky", outcome: "Flaky", category: CategoryUnknown},
+		{raw: "Works for me", outcome: "Works for me", category: CategoryUnknown},
+		{raw: "", outcome: "", category: CategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			outcome := c.Normalize(tt.raw)
+			assert.Equal(t, tt.outcome, outcome)
+			assert.Equal(t, tt.category, c.Category(outcome))
+		})
+	}
+}
+
+func TestParseOutcomeCategory(t *testing.T) {
+	t.Parallel()
+
+	category, err := ParseOutcomeCategory("Partial")
+	assert.NoError(t, err)
+	assert.Equal(t, CategoryPartial, category)
+
+	_, err = ParseOutcomeCategory("green")
+	assert.EqualError(t, err, `unknown result category "green", use pass, fail, partial, blocked or unknown`)
+}
@@ -0,0 +1,261 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version - версия ПО из QA комментария, сравнимая по правилам semver.
+// Поддерживаются префиксы (v1.2.3, SW 1.2.3), любое число числовых сегментов (5.4 == 5.4.0),
+// pre-release (1.2.3-rc1 < 1.2.3), метаданные сборки (+build.5, не влияют на сравнение)
+// и суффиксы -hotfix.N, которые идут после релиза: 1.2.3 < 1.2.3-hotfix.1 < 1.2.3-hotfix.2 < 1.2.4.
+// Нулевое значение означает отсутствие версии.
+type Version struct {
+	segments   []int
+	preRelease string
+	hotfix     int
+	isHotfix   bool
+	build      string
+}
+
+// versionRe: необязательный префикс без цифр, числовые сегменты, суффикс после "-" и метаданные после "+"
+var versionRe = regexp.MustCompile(`^[^\d]*?(\d+(?:\.\d+)*)(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?$`)
+
+// hotfixRe распознает суффикс hotfix, hotfix.2, hotfix-2 или hotfix2
+var hotfixRe = regexp.MustCompile(`(?i)^hotfix(?:[.-]?(\d+))?$`)
+
+// ParseVersion разбирает версию, например "v5.4.1", "5.4.1-rc2", "5.4.1-hotfix.3" или "5.4+build.17"
+func ParseVersion(value string) (Version, error) {
+	matches := versionRe.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return Version{}, fmt.Errorf("invalid version %q", value)
+	}
+
+	var v Version
+	for _, segment := range strings.Split(matches[1], ".") {
+		n, err := strconv.Atoi(segment)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", value, err)
+		}
+		v.segments = append(v.segments, n)
+	}
+
+	if suffix := matches[2]; suffix != "" {
+		if hotfix := hotfixRe.FindStringSubmatch(suffix); hotfix != nil {
+			v.isHotfix = true
+			if hotfix[1] != "" {
+				v.hotfix, _ = strconv.Atoi(hotfix[1])
+			}
+		} else {
+			v.preRelease = suffix
+		}
+	}
+	v.build = matches[3]
+	return v, nil
+}
+
+// IsZero сообщает, что версия не задана или не распознана
+func (v Version) IsZero() bool {
+	return len(v.segments) == 0
+}
+
+// String возвращает нормализованную версию без префикса: "v1.2.3" и "1.2.3" дают "1.2.3"
+func (v Version) String() string {
+	if v.IsZero() {
+		return ""
+	}
+	parts := make([]string, len(v.segments))
+	for i, segment := range v.segments {
+		parts[i] = strconv.Itoa(segment)
+	}
+	s := strings.Join(parts, ".")
+	switch {
+	case v.isHotfix && v.hotfix > 0:
+		s += "-hotfix." + strconv.Itoa(v.hotfix)
+	case v.isHotfix:
+		s += "-hotfix"
+	case v.preRelease != "":
+		s += "-" + v.preRelease
+	}
+	if v.build != "" {
+		s += "+" + v.build
+	}
+	return s
+}
+
+// MarshalText записывает версию в JSON и другие текстовые форматы в нормализованном виде
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText читает версию; пустая строка дает нулевую версию
+func (v *Version) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*v = Version{}
+		return nil
+	}
+	parsed, err := ParseVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// Compare возвращает -1, 0 или 1, если v меньше, равна или больше other.
+// Нулевая версия меньше любой заданной; метаданные сборки не учитываются.
+func (v Version) Compare(other Version) int {
+	if v.IsZero() || other.IsZero() {
+		return compareInts(boolInt(!v.IsZero()), boolInt(!other.IsZero()))
+	}
+
+	for i := 0; i < len(v.segments) || i < len(other.segments); i++ {
+		if c := compareInts(segmentAt(v.segments, i), segmentAt(other.segments, i)); c != 0 {
+			return c
+		}
+	}
+
+	// Pre-release меньше релиза, релиз меньше его hotfix
+	if c := compareInts(v.rank(), other.rank()); c != 0 {
+		return c
+	}
+	if v.isHotfix {
+		return compareInts(v.hotfix, other.hotfix)
+	}
+	return comparePreRelease(v.preRelease, other.preRelease)
+}
+
+// rank упорядочивает pre-release, релиз и hotfix одной версии
+func (v Version) rank() int {
+	switch {
+	case v.preRelease != "":
+		return 0
+	case v.isHotfix:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// LatestVersion возвращает наибольшую распознанную версию среди комментариев
+func LatestVersion(comments []QAComment) Version {
+	var latest Version
+	for _, comment := range comments {
+		if comment.Version.Compare(latest) > 0 {
+			latest = comment.Version
+		}
+	}
+	return latest
+}
+
+// VersionRange - интервал версий фильтра; нулевая граница не ограничивает интервал
+type VersionRange struct {
+	Min Version
+	Max Version
+}
+
+// ParseVersionRange разбирает значения --min-version и --max-version; пустое значение оставляет границу открытой
+func ParseVersionRange(min, max string) (VersionRange, error) {
+	var r VersionRange
+	var err error
+	if min != "" {
+		if r.Min, err = ParseVersion(min); err != nil {
+			return VersionRange{}, fmt.Errorf("min-version: %w", err)
+		}
+	}
+	if max != "" {
+		if r.Max, err = ParseVersion(max); err != nil {
+			return VersionRange{}, fmt.Errorf("max-version: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// IsZero сообщает, что интервал ничего не ограничивает
+func (r VersionRange) IsZero() bool {
+	return r.Min.IsZero() && r.Max.IsZero()
+}
+
+// Contains проверяет, что версия попадает в интервал включительно.
+// Комментарий без распознанной версии не попадает ни в один ограниченный интервал.
+func (r VersionRange) Contains(v Version) bool {
+	if r.IsZero() {
+		return true
+	}
+	if v.IsZero() {
+		return false
+	}
+	if !r.Min.IsZero() && v.Compare(r.Min) < 0 {
+		return false
+	}
+	if !r.Max.IsZero() && v.Compare(r.Max) > 0 {
+		return false
+	}
+	return true
+}
+
+// comparePreRelease сравнивает pre-release по идентификаторам через точку; числа внутри
+// идентификаторов сравниваются как числа, поэтому rc2 < rc10
+func comparePreRelease(a, b string) int {
+	left, right := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(left) && i < len(right); i++ {
+		if c := compareNatural(strings.ToLower(left[i]), strings.ToLower(right[i])); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(left), len(right))
+}
+
+// naturalRe делит идентификатор на числовые и нечисловые части
+var naturalRe = regexp.MustCompile(`\d+|\D+`)
+
+func compareNatural(a, b string) int {
+	left, right := naturalRe.FindAllString(a, -1), naturalRe.FindAllString(b, -1)
+	for i := 0; i < len(left) && i < len(right); i++ {
+		x, errX := strconv.Atoi(left[i])
+		y, errY := strconv.Atoi(right[i])
+		switch {
+		case errX == nil && errY == nil:
+			if c := compareInts(x, y); c != 0 {
+				return c
+			}
+		case errX == nil:
+			// По semver числовые идентификаторы меньше буквенных
+			return -1
+		case errY == nil:
+			return 1
+		default:
+			if c := strings.Compare(left[i], right[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(left), len(right))
+}
+
+func segmentAt(segments []int, i int) int {
+	if i < len(segments) {
+		return segments[i]
+	}
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
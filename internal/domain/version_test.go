@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mustVersion разбирает заведомо корректную версию из тестовых данных
+func mustVersion(t *testing.T, value string) Version {
+	t.Helper()
+
+	v, err := ParseVersion(value)
+	assert.NoError(t, err)
+	return v
+}
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value      string
+		normalized string
+	}{
+		{value: "1.2.3", normalized: "1.2.3"},
+		{value: "v1.2.3", normalized: "1.2.3"},
+		{value: "V5.4", normalized: "5.4"},
+		{value: "SW 5.4.1", normalized: "5.4.1"},
+		{value: "release-5.4.1", normalized: "5.4.1"},
+		{value: "1.2.3-rc1", normalized: "1.2.3-rc1"},
+		{value: "1.2.3-beta.2+build.17", normalized: "1.2.3-beta.2+build.17"},
+		{value: "1.2.3+20250812", normalized: "1.2.3+20250812"},
+		{value: "5.4.0-hotfix.3", normalized: "5.4.0-hotfix.3"},
+		{value: "5.4.0-HOTFIX-3", normalized: "5.4.0-hotfix.3"},
+		{value: "5.4.0-hotfix", normalized: "5.4.0-hotfix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			v, err := ParseVersion(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.normalized, v.String())
+		})
+	}
+
+	for _, value := range []string{"", "nightly", "1.2.3_b4", "1..2"} {
+		_, err := ParseVersion(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	t.Parallel()
+
+	// Версии в порядке возрастания
+	ordered := []string{
+		"1.2",
+		"1.2.3-alpha",
+		"1.2.3-alpha.1",
+		"1.2.3-beta",
+		"1.2.3-rc2",
+		"1.2.3-rc10",
+		"1.2.3",
+		"1.2.3-hotfix",
+		"1.2.3-hotfix.1",
+		"1.2.3-hotfix.2",
+		"1.2.3-hotfix.10",
+		"1.2.4",
+		"1.10.0",
+		"2",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, b := mustVersion(t, ordered[i]), mustVersion(t, ordered[i+1])
+		assert.Equal(t, -1, a.Compare(b), "%s < %s", ordered[i], ordered[i+1])
+		assert.Equal(t, 1, b.Compare(a), "%s > %s", ordered[i+1], ordered[i])
+	}
+
+	// Префиксы, недостающие нули и метаданные сборки не влияют на сравнение
+	assert.Equal(t, 0, mustVersion(t, "v5.4").Compare(mustVersion(t, "5.4.0")))
+	assert.Equal(t, 0, mustVersion(t, "5.4.0+build.1").Compare(mustVersion(t, "5.4.0+build.2")))
+	// Нулевая версия меньше любой заданной
+	assert.Equal(t, -1, Version{}.Compare(mustVersion(t, "0.0.1")))
+	assert.Equal(t, 0, Version{}.Compare(Version{}))
+}
+
+func TestVersionJSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(struct {
+		Latest Version
+		Empty  Version
+	}{Latest: mustVersion(t, "v5.4.0-hotfix.1")})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Latest": "5.4.0-hotfix.1", "Empty": ""}`, string(data))
+
+	var decoded struct{ Latest Version }
+	assert.NoError(t, json.Unmarshal([]byte(`{"Latest": "v5.4.1"}`), &decoded))
+	assert.Equal(t, "5.4.1", decoded.Latest.String())
+}
+
+func TestLatestVersion(t *testing.T) {
+	t.Parallel()
+
+	latest := LatestVersion([]QAComment{
+		{Version: mustVersion(t, "5.4.0-hotfix.2")},
+		{},
+		{Version: mustVersion(t, "5.4.0")},
+		{Version: mustVersion(t, "5.4.0-hotfix.10")},
+	})
+	assert.Equal(t, "5.4.0-hotfix.10", latest.String())
+	assert.True(t, LatestVersion(nil).IsZero())
+}
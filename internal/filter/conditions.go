@@ -0,0 +1,262 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// Поля комментария, доступные в выражениях
+const (
+	fieldResult   = "result"
+	fieldCategory = "category"
+	fieldAuthor   = "author"
+	fieldComment  = "comment"
+	fieldVersion  = "version"
+	fieldCreated  = "created"
+	fieldUpdated  = "updated"
+)
+
+// Fields - поля в порядке, в котором они перечисляются в подсказках
+var Fields = []string{fieldResult, fieldCategory, fieldAuthor, fieldComment, fieldVersion, fieldCreated, fieldUpdated}
+
+// fieldAliases - синонимы полей
+var fieldAliases = map[string]string{
+	"date": fieldCreated,
+}
+
+// negations - операторы, которые проверяются как отрицание парного оператора.
+// Поэтому комментарии без версии или даты проходят !=, !~ и not in.
+var negations = map[string]string{
+	"!=":     "=",
+	"!~":     "~",
+	"not in": "in",
+}
+
+// condition - сравнение поля комментария со значениями
+type condition struct {
+	field  string
+	op     string
+	values []string
+	test   func(comment domain.QAComment) bool
+}
+
+func (c condition) match(comment domain.QAComment) bool {
+	return c.test(comment)
+}
+
+func (c condition) String() string {
+	values := make([]string, len(c.values))
+	for i, value := range c.values {
+		values[i] = formatValue(value)
+	}
+	if c.op == "in" || c.op == "not in" {
+		return fmt.Sprintf("%s %s (%s)", c.field, c.op, strings.Join(values, ", "))
+	}
+	return fmt.Sprintf("%s %s %s", c.field, c.op, values[0])
+}
+
+// formatValue оставляет значение без кавычек, если его можно так же прочитать обратно
+func formatValue(value string) string {
+	if value == "" {
+		return Quote(value)
+	}
+	for _, keyword := range []string{"and", "or", "not", "in"} {
+		if strings.EqualFold(value, keyword) {
+			return Quote(value)
+		}
+	}
+	for i := 0; i < len(value); i++ {
+		if !isWordChar(value[i]) {
+			return Quote(value)
+		}
+	}
+	return value
+}
+
+// condition строит условие из разобранных лексем; значения проверяются сразу,
+// чтобы ошибка в версии или дате обнаруживалась при компиляции, а не при проверке комментариев
+func (p *parser) condition(fieldTok token, op string, valueToks []token) (node, error) {
+	field := strings.ToLower(fieldTok.value)
+	if alias, ok := fieldAliases[field]; ok {
+		field = alias
+	}
+	if op == "==" {
+		op = "="
+	}
+	base := op
+	if positive, ok := negations[op]; ok {
+		base = positive
+	}
+	compare := base
+	if base == "in" {
+		compare = "="
+	}
+
+	if !slices.Contains(Fields, field) {
+		return nil, p.errorAt(fieldTok, fmt.Sprintf("unknown field %q, use one of: %s", fieldTok.value, strings.Join(Fields, ", ")))
+	}
+
+	tests := make([]func(domain.QAComment) bool, len(valueToks))
+	values := make([]string, len(valueToks))
+	for i, tok := range valueToks {
+		test, err := p.valueTest(field, compare, tok.value)
+		if err != nil {
+			return nil, p.errorAt(tok, err.Error())
+		}
+		tests[i] = test
+		values[i] = tok.value
+	}
+
+	test := tests[0]
+	if len(tests) > 1 {
+		test = func(comment domain.QAComment) bool {
+			for _, t := range tests {
+				if t(comment) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	if base != op {
+		positive := test
+		test = func(comment domain.QAComment) bool { return !positive(comment) }
+	}
+	return condition{field: field, op: op, values: values, test: test}, nil
+}
+
+// valueTest возвращает проверку поля для одного значения
+func (p *parser) valueTest(field, op, value string) (func(domain.QAComment) bool, error) {
+	switch field {
+	case fieldResult:
+		if err := checkOperator(field, op, "=", "~"); err != nil {
+			return nil, err
+		}
+		return resultCondition(op, value).test, nil
+	case fieldCategory:
+		if err := checkOperator(field, op, "="); err != nil {
+			return nil, err
+		}
+		category, err := domain.ParseOutcomeCategory(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(comment domain.QAComment) bool { return comment.Category == category }, nil
+	case fieldAuthor:
+		if err := checkOperator(field, op, "=", "~"); err != nil {
+			return nil, err
+		}
+		return textTest(op, value, func(comment domain.QAComment) string { return comment.AuthorEmail }), nil
+	case fieldComment:
+		if err := checkOperator(field, op, "=", "~"); err != nil {
+			return nil, err
+		}
+		return textTest(op, value, func(comment domain.QAComment) string { return comment.Comment }), nil
+	case fieldVersion:
+		if op == "~" {
+			// ~ ищет подстроку в версии в том виде, в котором ее написал QA, например version ~ rc
+			return textTest(op, value, func(comment domain.QAComment) string { return comment.SoftwareVersion }), nil
+		}
+		version, err := domain.ParseVersion(value)
+		if err != nil {
+			return nil, err
+		}
+		return versionCondition(op, version).test, nil
+	case fieldCreated, fieldUpdated:
+		if err := checkOperator(field, op, "<", "<=", ">", ">="); err != nil {
+			return nil, err
+		}
+		t, err := domain.ParseDate(value, p.now, p.loc, p.sprints)
+		if err != nil {
+			return nil, err
+		}
+		return dateCondition(field, op, t).test, nil
+	}
+	return nil, fmt.Errorf("unknown field %q", field)
+}
+
+// checkOperator сообщает об операторе, который поле не поддерживает
+func checkOperator(field, op string, supported ...string) error {
+	for _, s := range supported {
+		if op == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("operator %s is not supported for %s", op, field)
+}
+
+// resultCondition сравнивает результат без учета регистра; "=" также принимает имя категории,
+// поэтому result = fail находит и Not Fixed, и Failed
+func resultCondition(op, value string) condition {
+	test := func(comment domain.QAComment) bool {
+		return strings.EqualFold(string(comment.TestResult), value) ||
+			strings.EqualFold(string(comment.Category), value)
+	}
+	if op == "~" {
+		test = textTest(op, value, func(comment domain.QAComment) string { return string(comment.TestResult) })
+	}
+	return condition{field: fieldResult, op: op, values: []string{value}, test: test}
+}
+
+// textTest сравнивает строку без учета регистра: "=" - целиком, "~" - как подстроку
+func textTest(op, value string, get func(domain.QAComment) string) func(domain.QAComment) bool {
+	if op == "~" {
+		lower := strings.ToLower(value)
+		return func(comment domain.QAComment) bool {
+			return strings.Contains(strings.ToLower(get(comment)), lower)
+		}
+	}
+	return func(comment domain.QAComment) bool {
+		return strings.EqualFold(get(comment), value)
+	}
+}
+
+// versionCondition сравнивает разобранную версию; комментарий без распознанной версии не проходит
+func versionCondition(op string, version domain.Version) condition {
+	return condition{
+		field:  fieldVersion,
+		op:     op,
+		values: []string{version.String()},
+		test: func(comment domain.QAComment) bool {
+			return !comment.Version.IsZero() && compareMatches(op, comment.Version.Compare(version))
+		},
+	}
+}
+
+// dateCondition сравнивает дату создания или изменения; комментарий с неизвестной датой не проходит
+func dateCondition(field, op string, t time.Time) condition {
+	get := func(comment domain.QAComment) time.Time { return comment.Created }
+	if field == fieldUpdated {
+		get = func(comment domain.QAComment) time.Time { return comment.Updated }
+	}
+	return condition{
+		field:  field,
+		op:     op,
+		values: []string{t.Format(time.RFC3339)},
+		test: func(comment domain.QAComment) bool {
+			value := get(comment)
+			return !value.IsZero() && compareMatches(op, value.Compare(t))
+		},
+	}
+}
+
+// compareMatches проверяет результат сравнения (-1, 0, 1) оператором
+func compareMatches(op string, c int) bool {
+	switch op {
+	case "=":
+		return c == 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+// Package filter реализует язык выражений для отбора QA комментариев, например
+//
+//	result in (Fixed, "Partially Fixed") and author ~ "@qa.example.com" and version >= 5.2
+//
+// Выражение компилируется один раз (Compile) и затем проверяется для каждого комментария (Match).
+// Флаги --result, --date-from/--date-to и --min-version/--max-version строятся теми же условиями
+// (Result, Dates, Versions) и объединяются с выражением через And.
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// Filter - скомпилированное выражение фильтра. nil *Filter пропускает все комментарии.
+type Filter struct {
+	root node
+}
+
+// node - узел дерева выражения
+type node interface {
+	match(comment domain.QAComment) bool
+	String() string
+}
+
+// SyntaxError описывает ошибку в выражении фильтра; Pos - смещение в байтах от начала выражения
+type SyntaxError struct {
+	Expr string
+	Pos  int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid filter %q: %s at position %d", e.Expr, e.Msg, e.Pos+1)
+}
+
+func syntaxError(expr string, pos int, msg string) error {
+	return &SyntaxError{Expr: expr, Pos: pos, Msg: msg}
+}
+
+// Compile разбирает выражение фильтра. Даты без часового пояса берутся в loc (nil - локальный пояс),
+// относительные даты (7d, last-sprint) отсчитываются от now, last-sprint - по календарю sprints.
+// Пустое выражение дает nil фильтр.
+func Compile(expr string, now time.Time, loc *time.Location, sprints domain.SprintCalendar) (*Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{expr: expr, tokens: tokens, now: now, loc: loc, sprints: sprints}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf(`expected "and", "or" or end of filter, found %s`, tok))
+	}
+	return &Filter{root: root}, nil
+}
+
+// Match сообщает, проходит ли комментарий фильтр
+func (f *Filter) Match(comment domain.QAComment) bool {
+	return f == nil || f.root.match(comment)
+}
+
+// Apply оставляет комментарии, прошедшие фильтр, сохраняя их порядок
+func (f *Filter) Apply(comments []domain.QAComment) []domain.QAComment {
+	if f == nil {
+		return comments
+	}
+	filtered := []domain.QAComment{}
+	for _, comment := range comments {
+		if f.Match(comment) {
+			filtered = append(filtered, comment)
+		}
+	}
+	return filtered
+}
+
+// String возвращает выражение в каноническом виде; его можно снова передать в Compile
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.root.String()
+}
+
+// And объединяет фильтры; nil фильтры пропускаются, без фильтров результат - nil
+func And(filters ...*Filter) *Filter {
+	var root node
+	for _, f := range filters {
+		if f == nil {
+			continue
+		}
+		if root == nil {
+			root = f.root
+			continue
+		}
+		root = andNode{left: root, right: f.root}
+	}
+	if root == nil {
+		return nil
+	}
+	return &Filter{root: root}
+}
+
+// Result - условие флага --result: результат или его категория без учета регистра
+func Result(value string) *Filter {
+	if value == "" {
+		return nil
+	}
+	return &Filter{root: resultCondition("=", value)}
+}
+
+// Dates - условия флагов --date-from и --date-to по дате создания комментария
+func Dates(r domain.DateRange) *Filter {
+	var filters []*Filter
+	if !r.From.IsZero() {
+		filters = append(filters, &Filter{root: dateCondition(fieldCreated, ">=", r.From)})
+	}
+	if !r.To.IsZero() {
+		filters = append(filters, &Filter{root: dateCondition(fieldCreated, "<=", r.To)})
+	}
+	return And(filters...)
+}
+
+// Versions - условия флагов --min-version и --max-version
+func Versions(r domain.VersionRange) *Filter {
+	var filters []*Filter
+	if !r.Min.IsZero() {
+		filters = append(filters, &Filter{root: versionCondition(">=", r.Min)})
+	}
+	if !r.Max.IsZero() {
+		filters = append(filters, &Filter{root: versionCondition("<=", r.Max)})
+	}
+	return And(filters...)
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(comment domain.QAComment) bool {
+	return n.left.match(comment) && n.right.match(comment)
+}
+
+func (n andNode) String() string {
+	return group(n.left) + " and " + group(n.right)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(comment domain.QAComment) bool {
+	return n.left.match(comment) || n.right.match(comment)
+}
+
+func (n orNode) String() string {
+	return n.left.String() + " or " + n.right.String()
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) match(comment domain.QAComment) bool {
+	return !n.operand.match(comment)
+}
+
+func (n notNode) String() string {
+	if _, ok := n.operand.(condition); ok {
+		return "not " + n.operand.String()
+	}
+	return "not (" + n.operand.String() + ")"
+}
+
+// group берет "or" в скобки внутри "and", чтобы String сохранял порядок вычисления
+func group(n node) string {
+	if _, ok := n.(orNode); ok {
+		return "(" + n.String() + ")"
+	}
+	return n.String()
+}
@@ -0,0 +1,208 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+
+// mustVersion разбирает заведомо корректную версию из тестовых данных
+func mustVersion(t *testing.T, value string) domain.Version {
+	t.Helper()
+
+	v, err := domain.ParseVersion(value)
+	assert.NoError(t, err)
+	return v
+}
+
+func testComments(t *testing.T) []domain.QAComment {
+	return []domain.QAComment{
+		{
+			SoftwareVersion: "v5.1.3", Version: mustVersion(t, "5.1.3"),
+			TestResult: domain.OutcomeFixed, Category: domain.CategoryPass,
+			AuthorEmail: "anna@qa.example.com", Comment: "Works on staging",
+			Created: time.Date(2025, 8, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			SoftwareVersion: "5.2.0-rc1", Version: mustVersion(t, "5.2.0-rc1"),
+			TestResult: domain.OutcomeNotFixed, Category: domain.CategoryFail,
+			AuthorEmail: "boris@qa.example.com",
+			Created:     time.Date(2025, 8, 10, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			SoftwareVersion: "5.2", Version: mustVersion(t, "5.2"),
+			TestResult: domain.OutcomePartiallyFixed, Category: domain.CategoryPartial,
+			AuthorEmail: "dev@example.com",
+			Created:     time.Date(2025, 8, 15, 10, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2025, 8, 19, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			SoftwareVersion: "5.3.0-hotfix.1", Version: mustVersion(t, "5.3.0-hotfix.1"),
+			TestResult: domain.OutcomePartiallyFixed, Category: domain.CategoryPartial,
+			AuthorEmail: "anna@qa.example.com",
+			Created:     time.Date(2025, 8, 18, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			SoftwareVersion: "nightly",
+			TestResult:      domain.OutcomeCouldNotTest, Category: domain.CategoryBlocked,
+			AuthorEmail: "anna@qa.example.com",
+		},
+	}
+}
+
+// versions возвращает версии комментариев, прошедших фильтр
+func versions(t *testing.T, expr string) []string {
+	t.Helper()
+	f, err := Compile(expr, testNow, time.UTC, domain.SprintCalendar{})
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	result := []string{}
+	for _, comment := range f.Apply(testComments(t)) {
+		result = append(result, comment.SoftwareVersion)
+	}
+	return result
+}
+
+func TestCompileAndMatch(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{
+			expr:     `result in (Fixed, "Partially Fixed") and author ~ "@qa.example.com" and version >= 5.2`,
+			expected: []string{"5.3.0-hotfix.1"},
+		},
+		{expr: `result = fixed`, expected: []string{"v5.1.3"}},
+		{expr: `result == "Not Fixed"`, expected: []string{"5.2.0-rc1"}},
+		{expr: `result = fail`, expected: []string{"5.2.0-rc1"}},
+		{expr: `result ~ fixed`, expected: []string{"v5.1.3", "5.2.0-rc1", "5.2", "5.3.0-hotfix.1"}},
+		{expr: `result not in (Fixed, partial)`, expected: []string{"5.2.0-rc1", "nightly"}},
+		{expr: `category = blocked`, expected: []string{"nightly"}},
+		{expr: `category in (pass, fail)`, expected: []string{"v5.1.3", "5.2.0-rc1"}},
+		{expr: `author = ANNA@qa.example.com and comment ~ staging`, expected: []string{"v5.1.3"}},
+		{expr: `author !~ "@qa.example.com"`, expected: []string{"5.2"}},
+		// Версии сравниваются по semver, а не как строки
+		{expr: `version >= 5.2`, expected: []string{"5.2", "5.3.0-hotfix.1"}},
+		{expr: `version < 5.2`, expected: []string{"v5.1.3", "5.2.0-rc1"}},
+		{expr: `version = 5.2.0`, expected: []string{"5.2"}},
+		{expr: `version in (v5.1.3, 5.2)`, expected: []string{"v5.1.3", "5.2"}},
+		{expr: `version ~ rc`, expected: []string{"5.2.0-rc1"}},
+		// Комментарий без распознанной версии проходит только отрицания
+		{expr: `version != 5.2`, expected: []string{"v5.1.3", "5.2.0-rc1", "5.3.0-hotfix.1", "nightly"}},
+		{expr: `created >= 2025-08-10 and created < 2025-08-18`, expected: []string{"5.2.0-rc1", "5.2"}},
+		{expr: `date > 7d`, expected: []string{"5.2", "5.3.0-hotfix.1"}},
+		// Предыдущий спринт по календарю по умолчанию начался 2025-07-28
+		{expr: `updated >= last-sprint`, expected: []string{"5.2"}},
+		{expr: `created <= "2025-08-01T13:00:00+03:00"`, expected: []string{"v5.1.3"}},
+		// and связывает сильнее or
+		{expr: `category = pass or category = fail and author ~ boris`, expected: []string{"v5.1.3", "5.2.0-rc1"}},
+		{expr: `(category = pass or category = fail) and author ~ anna`, expected: []string{"v5.1.3"}},
+		{expr: `not (result = partial or version < 5.2) AND NOT category = blocked`, expected: []string{}},
+		{expr: `not result = partial and not version < 5.2`, expected: []string{"nightly"}},
+		{expr: `comment = 'Works on staging'`, expected: []string{"v5.1.3"}},
+		{expr: ``, expected: []string{"v5.1.3", "5.2.0-rc1", "5.2", "5.3.0-hotfix.1", "nightly"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			assert.Equal(t, tt.expected, versions(t, tt.expr))
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: `result = Not Fixed`, expected: `invalid filter "result = Not Fixed": expected "and", "or" or end of filter, found "Fixed" at position 14`},
+		{expr: `status = Fixed`, expected: `unknown field "status", use one of: result, category, author, comment, version, created, updated at position 1`},
+		{expr: `result`, expected: `expected operator after "result", found end of filter at position 7`},
+		{expr: `result =`, expected: `expected value, found end of filter at position 9`},
+		{expr: `result in Fixed`, expected: `expected "(" after "in", found "Fixed" at position 11`},
+		{expr: `result in (Fixed Passed)`, expected: `expected "," or ")", found "Passed" at position 18`},
+		{expr: `(result = Fixed`, expected: `expected ")", found end of filter at position 16`},
+		{expr: `result = "Fixed`, expected: `unterminated string at position 10`},
+		{expr: `result & Fixed`, expected: `unexpected character '&' at position 8`},
+		{expr: `and = 1`, expected: `expected field name, found "and" at position 1`},
+		{expr: `result < Fixed`, expected: `operator < is not supported for result at position 10`},
+		{expr: `created = 2025-08-12`, expected: `operator = is not supported for created at position 11`},
+		{expr: `category = green`, expected: `at position 12`},
+		{expr: `version >= latest`, expected: `invalid version "latest" at position 12`},
+		{expr: `created > yesterday`, expected: `invalid date "yesterday"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Compile(tt.expr, testNow, time.UTC, domain.SprintCalendar{})
+			assert.ErrorContains(t, err, tt.expected)
+			var syntaxErr *SyntaxError
+			assert.ErrorAs(t, err, &syntaxErr)
+		})
+	}
+}
+
+func TestFilterString(t *testing.T) {
+	tests := []struct {
+		expr      string
+		canonical string
+	}{
+		{expr: `RESULT IN (Fixed,"Partially Fixed")`, canonical: `result in (Fixed, "Partially Fixed")`},
+		{expr: `result == 'and' or not author ~ x`, canonical: `result = "and" or not author ~ x`},
+		{expr: `(version >= 5.2 or date > 2025-08-01) and result != fail`, canonical: `(version >= 5.2 or created > 2025-08-01) and result != fail`},
+		{expr: `not (category = pass and comment ~ "say \"hi\"")`, canonical: `not (category = pass and comment ~ "say \"hi\"")`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := Compile(tt.expr, testNow, time.UTC, domain.SprintCalendar{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.canonical, f.String())
+
+			// Каноническое выражение компилируется в тот же фильтр
+			again, err := Compile(f.String(), testNow, time.UTC, domain.SprintCalendar{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.canonical, again.String())
+		})
+	}
+}
+
+func TestFlagFilters(t *testing.T) {
+	comment := domain.QAComment{TestResult: domain.OutcomeNotFixed, Category: domain.CategoryFail}
+
+	assert.True(t, Result("Not Fixed").Match(comment))
+	assert.True(t, Result("not fixed").Match(comment))
+	assert.True(t, Result("fail").Match(comment))
+	assert.False(t, Result("Fixed").Match(comment))
+	assert.False(t, Result("pass").Match(comment))
+	assert.Nil(t, Result(""))
+
+	from := time.Date(2025, 8, 10, 0, 0, 0, 0, time.UTC)
+	dates := Dates(domain.DateRange{From: from})
+	assert.Equal(t, "created >= 2025-08-10T00:00:00Z", dates.String())
+	assert.Nil(t, Dates(domain.DateRange{}))
+
+	versionRange, err := domain.ParseVersionRange("v5.2", "5.3.0-hotfix.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "version >= 5.2 and version <= 5.3.0-hotfix.1", Versions(versionRange).String())
+
+	combined := And(Result("partial"), nil, dates, Versions(versionRange))
+	assert.Equal(t, "result = partial and created >= 2025-08-10T00:00:00Z and version >= 5.2 and version <= 5.3.0-hotfix.1", combined.String())
+	var kept []string
+	for _, c := range combined.Apply(testComments(t)) {
+		kept = append(kept, c.SoftwareVersion)
+	}
+	assert.Equal(t, []string{"5.2", "5.3.0-hotfix.1"}, kept)
+
+	// nil фильтр пропускает все комментарии
+	var none *Filter
+	assert.Nil(t, And())
+	assert.True(t, none.Match(comment))
+	assert.Len(t, none.Apply(testComments(t)), 5)
+	assert.Equal(t, "", none.String())
+}
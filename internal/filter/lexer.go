@@ -0,0 +1,124 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token - лексема выражения; pos - смещение в байтах от начала выражения
+type token struct {
+	kind  tokenKind
+	text  string
+	pos   int
+	value string // значение слова или строки без кавычек
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of filter"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// operators - операторы сравнения; двухсимвольные проверяются раньше односимвольных
+var operators = []string{"==", "!=", "!~", "<=", ">=", "=", "~", "<", ">"}
+
+// isWordChar сообщает, может ли символ входить в слово без кавычек: имена полей,
+// результаты (Fixed), версии (v5.4.0-rc1+build.2), даты (2025-08-12T10:00:00+03:00, 7d) и email
+func isWordChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	case c >= 0x80:
+		// Байты UTF-8: кириллица и другие буквы в значениях без кавычек
+		return true
+	}
+	return strings.IndexByte("._-@+:/", c) >= 0
+}
+
+// tokenize разбивает выражение на лексемы
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case c == '"' || c == '\'':
+			tok, next, err := readString(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
+		case isWordChar(c):
+			start := i
+			for i < len(expr) && isWordChar(expr[i]) {
+				i++
+			}
+			word := expr[start:i]
+			tokens = append(tokens, token{kind: tokenWord, text: word, pos: start, value: word})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, syntaxError(expr, i, fmt.Sprintf("unexpected character %q", c))
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+// readString читает строку в двойных или одинарных кавычках; обратная косая черта
+// экранирует кавычку и саму себя
+func readString(expr string, start int) (token, int, error) {
+	quote := expr[start]
+	var value strings.Builder
+	for i := start + 1; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case c == '\\' && i+1 < len(expr) && (expr[i+1] == quote || expr[i+1] == '\\'):
+			value.WriteByte(expr[i+1])
+			i++
+		case c == quote:
+			return token{kind: tokenString, text: expr[start : i+1], pos: start, value: value.String()}, i + 1, nil
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return token{}, 0, syntaxError(expr, start, "unterminated string")
+}
+
+// Quote записывает значение в виде строки выражения, например для значений флагов
+func Quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
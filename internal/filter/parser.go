@@ -0,0 +1,164 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// parser - рекурсивный спуск по грамматике
+//
+//	or        = and { "or" and }
+//	and       = unary { "and" unary }
+//	unary     = "not" unary | "(" or ")" | condition
+//	condition = field operator value | field [ "not" ] "in" "(" value { "," value } ")"
+type parser struct {
+	expr    string
+	tokens  []token
+	pos     int
+	now     time.Time
+	loc     *time.Location
+	sprints domain.SprintCalendar
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorAt(tok token, msg string) error {
+	return syntaxError(p.expr, tok.pos, msg)
+}
+
+// isKeyword сообщает, что лексема - ключевое слово; слова в кавычках ключевыми не бывают
+func isKeyword(tok token, keyword string) bool {
+	return tok.kind == tokenWord && strings.EqualFold(tok.value, keyword)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	tok := p.peek()
+	switch {
+	case isKeyword(tok, "not"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	case tok.kind == tokenLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, fmt.Sprintf(`expected ")", found %s`, closing))
+		}
+		return inner, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (node, error) {
+	field := p.next()
+	if field.kind != tokenWord || isKeyword(field, "and") || isKeyword(field, "or") || isKeyword(field, "in") {
+		return nil, p.errorAt(field, fmt.Sprintf("expected field name, found %s", field))
+	}
+
+	tok := p.next()
+	switch {
+	case tok.kind == tokenOperator:
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return p.condition(field, tok.text, []token{value})
+	case isKeyword(tok, "in"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return p.condition(field, "in", values)
+	case isKeyword(tok, "not") && isKeyword(p.peek(), "in"):
+		p.next()
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return p.condition(field, "not in", values)
+	}
+	return nil, p.errorAt(tok, fmt.Sprintf("expected operator after %q, found %s", field.value, tok))
+}
+
+func (p *parser) parseValue() (token, error) {
+	tok := p.next()
+	if tok.kind != tokenWord && tok.kind != tokenString {
+		return token{}, p.errorAt(tok, fmt.Sprintf("expected value, found %s", tok))
+	}
+	return tok, nil
+}
+
+// parseList разбирает список значений оператора in: (Fixed, "Partially Fixed")
+func (p *parser) parseList() ([]token, error) {
+	if open := p.next(); open.kind != tokenLParen {
+		return nil, p.errorAt(open, fmt.Sprintf(`expected "(" after "in", found %s`, open))
+	}
+	var values []token
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		switch tok := p.next(); tok.kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return values, nil
+		default:
+			return nil, p.errorAt(tok, fmt.Sprintf(`expected "," or ")", found %s`, tok))
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// Package adf converts Atlassian Document Format (ADF) documents, used for rich text
+// by JIRA Cloud REST API v3, into plain text suitable for QA comment parsing.
+package adf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Node is a single ADF node. Documents, blocks and inline content share the same shape.
+type Node struct {
+	Type    string                 `json:"type"`
+	Text    string                 `json:"text,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []Node                 `json:"content,omitempty"`
+}
+
+// IsDocument reports whether raw holds an ADF document rather than a wiki markup string
+func IsDocument(raw json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{"))
+}
+
+// Parse decodes an ADF document
+func Parse(raw json.RawMessage) (*Node, error) {
+	var doc Node
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("invalid ADF document: %w", err)
+	}
+	if doc.Type != "doc" {
+		return nil, fmt.Errorf("invalid ADF document: root node is %q, expected \"doc\"", doc.Type)
+	}
+	return &doc, nil
+}
+
+// ToText renders a document as plain text, one block per line.
+//
+// Mentions and media are dropped, as they are when wiki markup is cleaned up.
+// Tables become structured fields: a two-column table without a header row
+// is rendered as "Label: Value" lines, any other table keeps its rows in
+// wiki table notation (||header|| and |cell|).
+func ToText(doc *Node) string {
+	return strings.TrimSpace(strings.Join(blocks(doc.Content, ""), "\n"))
+}
+
+// blocks renders block nodes, prefixing every line with indent
+func blocks(nodes []Node, indent string) []string {
+	var lines []string
+	for _, node := range nodes {
+		lines = append(lines, block(node, indent)...)
+	}
+	return lines
+}
+
+func block(node Node, indent string) []string {
+	switch node.Type {
+	case "paragraph", "heading", "codeBlock":
+		return indentLines(inline(node.Content), indent)
+	case "bulletList", "orderedList", "taskList":
+		return list(node, indent)
+	case "table":
+		return table(node, indent)
+	case "rule", "mediaSingle", "mediaGroup", "media":
+		return nil
+	}
+
+	// panel, blockquote, expand и прочие контейнеры выводят свое содержимое
+	if len(node.Content) > 0 {
+		return blocks(node.Content, indent)
+	}
+	return indentLines(inlineNode(node), indent)
+}
+
+// list renders list items with "- ", "N. " or task state markers;
+// nested lists are indented by two spaces
+func list(node Node, indent string) []string {
+	number := 1
+	if order, ok := node.Attrs["order"].(float64); ok {
+		number = int(order)
+	}
+
+	var lines []string
+	for _, item := range node.Content {
+		var marker string
+		switch node.Type {
+		case "orderedList":
+			marker = strconv.Itoa(number) + ". "
+			number++
+		case "taskList":
+			marker = "[ ] "
+			if state, _ := item.Attrs["state"].(string); state == "DONE" {
+				marker = "[x] "
+			}
+		default:
+			marker = "- "
+		}
+
+		// taskItem содержит inline-узлы, listItem - блоки
+		var itemLines []string
+		if item.Type == "taskItem" {
+			itemLines = indentLines(inline(item.Content), "")
+		} else {
+			itemLines = blocks(item.Content, "")
+		}
+		for i, line := range itemLines {
+			if i == 0 {
+				lines = append(lines, indent+marker+line)
+			} else {
+				lines = append(lines, indent+"  "+line)
+			}
+		}
+	}
+	return lines
+}
+
+// table renders a table as "Label: Value" fields or as wiki table rows
+func table(node Node, indent string) []string {
+	var rows [][]string
+	headerRow := false
+	for i, row := range node.Content {
+		var cells []string
+		allHeaders := len(row.Content) > 0
+		for _, cell := range row.Content {
+			text := strings.Join(blocks(cell.Content, ""), " ")
+			cells = append(cells, strings.TrimSpace(text))
+			if cell.Type != "tableHeader" {
+				allHeaders = false
+			}
+		}
+		if i == 0 {
+			headerRow = allHeaders
+		}
+		rows = append(rows, cells)
+	}
+
+	var lines []string
+	if !headerRow && columns(rows) == 2 {
+		for _, row := range rows {
+			if row[0] == "" && row[1] == "" {
+				continue
+			}
+			lines = append(lines, indent+row[0]+": "+row[1])
+		}
+		return lines
+	}
+
+	for i, row := range rows {
+		separator := "|"
+		if i == 0 && headerRow {
+			separator = "||"
+		}
+		lines = append(lines, indent+separator+strings.Join(row, separator)+separator)
+	}
+	return lines
+}
+
+// columns returns the column count shared by all rows, or -1 if rows differ
+func columns(rows [][]string) int {
+	if len(rows) == 0 {
+		return 0
+	}
+	count := len(rows[0])
+	for _, row := range rows[1:] {
+		if len(row) != count {
+			return -1
+		}
+	}
+	return count
+}
+
+// inline concatenates inline nodes into text, hard breaks become newlines
+func inline(nodes []Node) string {
+	var sb strings.Builder
+	for _, node := range nodes {
+		sb.WriteString(inlineNode(node))
+	}
+	return sb.String()
+}
+
+func inlineNode(node Node) string {
+	switch node.Type {
+	case "text":
+		return node.Text
+	case "hardBreak":
+		return "\n"
+	case "mention":
+		return ""
+	case "emoji":
+		if text := attr(node, "text"); text != "" {
+			return text
+		}
+		return attr(node, "shortName")
+	case "inlineCard", "blockCard", "embedCard":
+		return attr(node, "url")
+	case "status":
+		return attr(node, "text")
+	case "date":
+		// timestamp - миллисекунды Unix в виде строки
+		if ms, err := strconv.ParseInt(attr(node, "timestamp"), 10, 64); err == nil {
+			return time.UnixMilli(ms).UTC().Format("2006-01-02")
+		}
+		return ""
+	}
+	if node.Text != "" {
+		return node.Text
+	}
+	return inline(node.Content)
+}
+
+func attr(node Node, name string) string {
+	value, _ := node.Attrs[name].(string)
+	return value
+}
+
+func indentLines(text, indent string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = indent + strings.TrimRight(line, " ")
+	}
+	return lines
+}
@@ -0,0 +1,114 @@
+package adf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDocument(t *testing.T) {
+	assert.True(t, IsDocument(json.RawMessage(` {"type":"doc"}`)))
+	assert.False(t, IsDocument(json.RawMessage(`"Tested on v1.0"`)))
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse(json.RawMessage(`{"type":"paragraph"}`))
+	assert.ErrorContains(t, err, "expected \"doc\"")
+
+	_, err = Parse(json.RawMessage(`{"type":`))
+	assert.Error(t, err)
+}
+
+func TestToText(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		expected string
+	}{
+		{
+			name: "paragraphs with marks, hard breaks and mentions",
+			doc: `{"type":"doc","version":1,"content":[
+				{"type":"paragraph","content":[
+					{"type":"mention","attrs":{"id":"42","text":"@Jane"}},
+					{"type":"text","text":" Tested on "},
+					{"type":"text","text":"SW v2.3.1","marks":[{"type":"strong"}]},
+					{"type":"hardBreak"},
+					{"type":"text","text":"Result: Fixed "},
+					{"type":"emoji","attrs":{"shortName":":check_mark:","text":"✔"}}
+				]}
+			]}`,
+			expected: "Tested on SW v2.3.1\nResult: Fixed ✔",
+		},
+		{
+			name: "panels, code blocks and media",
+			doc: `{"type":"doc","version":1,"content":[
+				{"type":"panel","attrs":{"panelType":"info"},"content":[
+					{"type":"paragraph","content":[{"type":"text","text":"QA comment"}]}
+				]},
+				{"type":"codeBlock","attrs":{"language":"text"},"content":[{"type":"text","text":"line 1\nline 2"}]},
+				{"type":"mediaSingle","content":[{"type":"media","attrs":{"id":"1","type":"file"}}]},
+				{"type":"rule"},
+				{"type":"paragraph","content":[{"type":"status","attrs":{"text":"DONE"}}, {"type":"text","text":" on "}, {"type":"date","attrs":{"timestamp":"1754956800000"}}]}
+			]}`,
+			expected: "QA comment\nline 1\nline 2\nDONE on 2025-08-12",
+		},
+		{
+			name: "nested lists",
+			doc: `{"type":"doc","version":1,"content":[
+				{"type":"bulletList","content":[
+					{"type":"listItem","content":[
+						{"type":"paragraph","content":[{"type":"text","text":"login"}]},
+						{"type":"orderedList","attrs":{"order":3},"content":[
+							{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"step"}]}]}
+						]}
+					]}
+				]},
+				{"type":"taskList","content":[
+					{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"regression"}]}
+				]}
+			]}`,
+			expected: "- login\n  3. step\n[x] regression",
+		},
+		{
+			name: "two-column table becomes fields",
+			doc: `{"type":"doc","version":1,"content":[
+				{"type":"table","content":[
+					{"type":"tableRow","content":[
+						{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"Tested on"}]}]},
+						{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"v5.2.0"}]}]}
+					]},
+					{"type":"tableRow","content":[
+						{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Result"}]}]},
+						{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"Not Fixed"}]}]}
+					]}
+				]}
+			]}`,
+			expected: "Tested on: v5.2.0\nResult: Not Fixed",
+		},
+		{
+			name: "table with header row keeps wiki rows",
+			doc: `{"type":"doc","version":1,"content":[
+				{"type":"table","content":[
+					{"type":"tableRow","content":[
+						{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Scenario"}]}]},
+						{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"Result"}]}]}
+					]},
+					{"type":"tableRow","content":[
+						{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"Login"}]}]},
+						{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"Fixed"}]}]}
+					]}
+				]}
+			]}`,
+			expected: "||Scenario||Result||\n|Login|Fixed|",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(json.RawMessage(tt.doc))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ToText(doc))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 // TokenValidator интерфейс для проверки валидности токенов аутентификации
 type TokenValidator interface {
 	ValidateToken(baseURL, username, token string) error
+	ValidateTokenWithContext(ctx context.Context, baseURL, username, token string) error
 }
 
 // JiraTokenValidator реализация валидатора токенов для JIRA
@@ -22,6 +24,11 @@ func NewJiraTokenValidator() *JiraTokenValidator {
 
 // ValidateToken проверяет валидность токена аутентификации
 func (v *JiraTokenValidator) ValidateToken(baseURL, username, token string) error {
+	return v.ValidateTokenWithContext(context.Background(), baseURL, username, token)
+}
+
+// ValidateTokenWithContext проверяет валидность токена с учетом отмены и таймаута ctx
+func (v *JiraTokenValidator) ValidateTokenWithContext(ctx context.Context, baseURL, username, token string) error {
 	var client *jira.Client
 	var err error
 
@@ -53,7 +60,7 @@ func (v *JiraTokenValidator) ValidateToken(baseURL, username, token string) erro
 	}
 
 	// Выполняем проверку валидности токена через запрос к API
-	_, _, err = client.User.GetSelf()
+	_, _, err = client.User.GetSelfWithContext(ctx)
 	if err != nil {
 		return fmt.Errorf("invalid token: %w", err)
 	}
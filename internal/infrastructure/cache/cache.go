@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Entry хранит сырой ответ JIRA по одному тикету
+type Entry struct {
+	Key string `json:"key"`
+	// Updated - значение поля updated тикета на момент загрузки, используется для ревалидации
+	Updated   string          `json:"updated"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Issue     json.RawMessage `json:"issue"`
+	Comments  json.RawMessage `json:"comments"`
+}
+
+// FileCache - кэш тикетов на диске, по одному JSON-файлу на ключ тикета
+type FileCache struct {
+	dir string
+}
+
+// unsafeChars заменяются в ключах и именах хостов при построении путей
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// New создает кэш в каталоге dir
+func New(dir string) *FileCache {
+	return &FileCache{dir: dir}
+}
+
+// ForInstance создает кэш в подкаталоге root для конкретного экземпляра JIRA,
+// чтобы одинаковые ключи разных серверов не пересекались
+func ForInstance(root, baseURL string) *FileCache {
+	host := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return New(filepath.Join(root, unsafeChars.ReplaceAllString(host, "_")))
+}
+
+// DefaultDir возвращает каталог кэша по умолчанию в пользовательском каталоге кэша ОС
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(base, "jira-parser"), nil
+}
+
+// Dir возвращает каталог кэша
+func (c *FileCache) Dir() string {
+	return c.dir
+}
+
+func (c *FileCache) path(key string) string {
+	return filepath.Join(c.dir, unsafeChars.ReplaceAllString(strings.ToUpper(key), "_")+".json")
+}
+
+// Get возвращает запись для тикета или nil, если ее нет
+func (c *FileCache) Get(key string) (*Entry, error) {
+	data, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry for %s: %w", key, err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to decode cache entry for %s: %w", key, err)
+	}
+	return &entry, nil
+}
+
+// Put сохраняет запись, заменяя файл атомарно
+func (c *FileCache) Put(entry *Entry) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry for %s: %w", entry.Key, err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry for %s: %w", entry.Key, err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry for %s: %w", entry.Key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry for %s: %w", entry.Key, err)
+	}
+
+	return os.Rename(tmp.Name(), c.path(entry.Key))
+}
+
+// Prune удаляет записи, загруженные раньше чем olderThan назад, в подкаталогах экземпляров
+// JIRA внутри root (см. ForInstance). При olderThan == 0 удаляются все записи.
+// Файлы, которые не являются записями кэша, не удаляются: root может быть общим каталогом.
+// Возвращает число удаленных файлов.
+func Prune(root string, olderThan time.Duration) (int, error) {
+	instances, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune cache: %w", err)
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+
+	for _, instance := range instances {
+		if !instance.IsDir() {
+			continue
+		}
+
+		err := filepath.WalkDir(filepath.Join(root, instance.Name()), func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || filepath.Ext(path) != ".json" {
+				return nil
+			}
+
+			entry, ok := readEntry(path)
+			if !ok || (olderThan > 0 && entry.FetchedAt.After(cutoff)) {
+				return nil
+			}
+
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			removed++
+			return nil
+		})
+		if err != nil {
+			return removed, fmt.Errorf("failed to prune cache: %w", err)
+		}
+	}
+
+	return removed, nil
+}
+
+// readEntry читает файл как запись кэша; ok == false для чужих и поврежденных файлов
+func readEntry(path string) (entry Entry, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key == "" || entry.FetchedAt.IsZero() {
+		return Entry{}, false
+	}
+	return entry, true
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCache_PutGet(t *testing.T) {
+	t.Parallel()
+
+	c := ForInstance(t.TempDir(), "https://jira.example.com")
+
+	entry, err := c.Get("TOS-1")
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+
+	err = c.Put(&Entry{
+		Key:       "TOS-1",
+		Updated:   "2025-07-01T10:00:00.000+0300",
+		FetchedAt: time.Now(),
+		Issue:     json.RawMessage(`{"key":"TOS-1"}`),
+		Comments:  json.RawMessage(`[]`),
+	})
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(c.Dir(), "TOS-1.json"))
+	assert.Equal(t, "jira.example.com", filepath.Base(c.Dir()))
+
+	entry, err = c.Get("tos-1")
+	assert.NoError(t, err)
+	assert.NotNil(t, entry)
+	assert.Equal(t, "2025-07-01T10:00:00.000+0300", entry.Updated)
+	assert.JSONEq(t, `{"key":"TOS-1"}`, string(entry.Issue))
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	c := ForInstance(root, "https://jira.example.com")
+
+	assert.NoError(t, c.Put(&Entry{Key: "TOS-1", FetchedAt: time.Now().Add(-48 * time.Hour)}))
+	assert.NoError(t, c.Put(&Entry{Key: "TOS-2", FetchedAt: time.Now()}))
+
+	removed, err := Prune(root, 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	entry, err := c.Get("TOS-2")
+	assert.NoError(t, err)
+	assert.NotNil(t, entry)
+
+	removed, err = Prune(root, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	// Отсутствующий каталог кэша - не ошибка
+	removed, err = Prune(filepath.Join(root, "missing"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removed)
+
+	_, err = os.Stat(filepath.Join(c.Dir(), "TOS-2.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestPrune_KeepsForeignFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	c := ForInstance(root, "https://jira.example.com")
+	assert.NoError(t, c.Put(&Entry{Key: "TOS-1", FetchedAt: time.Now().Add(-48 * time.Hour)}))
+
+	// Чужие JSON-файлы в корне, в каталоге экземпляра и поврежденная запись
+	foreign := []string{
+		filepath.Join(root, "package.json"),
+		filepath.Join(c.Dir(), "settings.json"),
+		filepath.Join(c.Dir(), "TOS-2.json"),
+	}
+	assert.NoError(t, os.WriteFile(foreign[0], []byte(`{"key":"TOS-1","fetched_at":"2020-01-01T00:00:00Z"}`), 0o644))
+	assert.NoError(t, os.WriteFile(foreign[1], []byte(`{"theme":"dark"}`), 0o644))
+	assert.NoError(t, os.WriteFile(foreign[2], []byte(`{`), 0o644))
+
+	removed, err := Prune(root, 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	removed, err = Prune(root, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removed)
+
+	for _, path := range foreign {
+		assert.FileExists(t, path)
+	}
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/jira"
 	"github.com/spf13/viper"
 )
 
@@ -10,6 +14,39 @@ type JiraConfig struct {
 	Username string               `mapstructure:"username"`
 	Token    string               `mapstructure:"token"`
 	Parsing  domain.ParsingConfig `mapstructure:"parsing"`
+	// CommentFormat - формат тел комментариев: wiki (REST API v2, по умолчанию) или adf (REST API v3, JIRA Cloud)
+	CommentFormat string `mapstructure:"comment_format"`
+	// RateLimit ограничивает число запросов к JIRA в секунду (0 - без ограничения)
+	RateLimit float64     `mapstructure:"rate_limit"`
+	Retry     RetryConfig `mapstructure:"retry"`
+	// Cache загружается из секции cache верхнего уровня
+	Cache CacheConfig `mapstructure:"-"`
+	// Parsers загружается из секции parsers верхнего уровня
+	Parsers []ParserConfig `mapstructure:"-"`
+}
+
+// ParserConfig выбирает парсер комментариев для тикетов перечисленных проектов
+type ParserConfig struct {
+	// Projects - ключи проектов JIRA, например TOS
+	Projects []string `mapstructure:"projects"`
+	// Parser - имя парсера; по умолчанию regex
+	Parser string `mapstructure:"parser"`
+	// Parsing заменяет общую секцию parsing для этих проектов; nil - используются общие правила
+	Parsing *domain.ParsingConfig `mapstructure:"parsing"`
+}
+
+// CacheConfig задает локальный кэш ответов JIRA
+type CacheConfig struct {
+	// Dir - каталог кэша; по умолчанию используется пользовательский каталог кэша ОС
+	Dir string `mapstructure:"dir"`
+}
+
+// RetryConfig задает повторные попытки для временных ошибок JIRA (429, 502, 503, 504, сетевые таймауты).
+// Незаданные поля получают значения jira.DefaultRetryPolicy.
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
 }
 
 func LoadConfig(path string) (*JiraConfig, error) {
@@ -24,53 +61,25 @@ func LoadConfig(path string) (*JiraConfig, error) {
 		return nil, err
 	}
 
-	// Load parsing configuration
-	var parsingCfg domain.ParsingConfig
-	if err := viper.UnmarshalKey("parsing", &parsingCfg); err != nil {
-		// If parsing config is not found, use default values
-		parsingCfg = domain.ParsingConfig{
-			VersionPatterns: []string{
-				`(?i)Tested on (?:SW )?(v?[\d.]+(?:-[\w.]+)?)`,
-				`(?i)version.*?(v?[\d.]+(?:-[\w.]+)?)`,
-				`(?i)sw.*?(v?[\d.]+(?:-[\w.]+)?)`,
-			},
-			ResultPatterns: []string{
-				`(?i)Result:\s*([^\n\r]+)`,
-				`(?i)Status:\s*([^\n\r]+)`,
-				`(?i)(Fixed|Not Fixed|Partially Fixed|Could not test|Passed|Failed|Blocked|Resolved|Verified|Re-Test|Pending|In Progress|N/A)`,
-			},
-			CommentPatterns: []string{
-				`(?i)Comment:\s*(.+)`,
-				`(?i)Notes?:\s*(.+)`,
-				`(?i)Observations?:\s*(.+)`,
-			},
-			QAIndicators: []string{
-				"tested on",
-				"could not test on sw",
-				"qa comment",
-				"qa verification",
-				"qa tested",
-				"test.*result",
-				"test.*passed",
-				"test.*failed",
-				"test.*status",
-			},
-			ResultNormalization: map[string]string{
-				"passed":         "Fixed",
-				"verified":       "Fixed",
-				"resolved":       "Fixed",
-				"re-test":        "Fixed",
-				"failed":         "Not Fixed",
-				"blocked":        "Not Fixed",
-				"pending":        "Not Fixed",
-				"in progress":    "Not Fixed",
-				"n/a":            "N/A",
-				"not applicable": "N/A",
-			},
-		}
+	if err := loadParsers(&cfg); err != nil {
+		return nil, err
 	}
 
-	cfg.Parsing = parsingCfg
+	if err := viper.UnmarshalKey("cache", &cfg.Cache); err != nil {
+		return nil, err
+	}
+
+	// Заполняем незаданные параметры повторов значениями по умолчанию клиента JIRA
+	defaultRetry := jira.DefaultRetryPolicy()
+	if cfg.Retry.MaxAttempts == 0 {
+		cfg.Retry.MaxAttempts = defaultRetry.MaxAttempts
+	}
+	if cfg.Retry.InitialBackoff == 0 {
+		cfg.Retry.InitialBackoff = defaultRetry.InitialBackoff
+	}
+	if cfg.Retry.MaxBackoff == 0 {
+		cfg.Retry.MaxBackoff = defaultRetry.MaxBackoff
+	}
 
 	// Validate required fields
 	if cfg.BaseURL == "" {
@@ -95,3 +104,102 @@ type ConfigError struct {
 func (e *ConfigError) Error() string {
 	return e.Message
 }
+
+// LoadOfflineConfig читает только правила разбора комментариев (секции parsing и parsers),
+// без проверки параметров подключения к JIRA. Используется в офлайн-режиме.
+func LoadOfflineConfig(path string) (*JiraConfig, error) {
+	viper.SetConfigFile(path)
+
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var cfg JiraConfig
+	if err := loadParsers(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// loadParsers читает секции parsing и parsers из уже прочитанной конфигурации
+func loadParsers(cfg *JiraConfig) error {
+	if err := viper.UnmarshalKey("parsing", &cfg.Parsing); err != nil {
+		// If parsing config is not found, use default values
+		cfg.Parsing = DefaultParsingConfig()
+	}
+
+	if err := viper.UnmarshalKey("parsers", &cfg.Parsers); err != nil {
+		return err
+	}
+	if err := validateResultCategories("parsing", cfg.Parsing); err != nil {
+		return err
+	}
+	for _, parser := range cfg.Parsers {
+		if len(parser.Projects) == 0 {
+			return &ConfigError{Field: "parsers", Message: "parsers: every entry must list its projects"}
+		}
+		if parser.Parsing != nil {
+			if err := validateResultCategories("parsers", *parser.Parsing); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateResultCategories проверяет, что result_categories ссылается только на известные категории
+func validateResultCategories(section string, parsing domain.ParsingConfig) error {
+	for outcome, category := range parsing.ResultCategories {
+		if _, err := domain.ParseOutcomeCategory(category); err != nil {
+			return &ConfigError{
+				Field:   section + ".result_categories",
+				Message: fmt.Sprintf("%s.result_categories: %q: %v", section, outcome, err),
+			}
+		}
+	}
+	return nil
+}
+
+// DefaultParsingConfig возвращает правила разбора QA комментариев по умолчанию
+func DefaultParsingConfig() domain.ParsingConfig {
+	return domain.ParsingConfig{
+		VersionPatterns: []string{
+			`(?i)Tested on (?:SW )?(v?\d+(?:\.\d+)*(?:-[\w.-]*\w)?(?:\+[\w.-]*\w)?)`,
+			`(?i)version.*?(v?\d+(?:\.\d+)*(?:-[\w.-]*\w)?(?:\+[\w.-]*\w)?)`,
+			`(?i)sw.*?(v?\d+(?:\.\d+)*(?:-[\w.-]*\w)?(?:\+[\w.-]*\w)?)`,
+		},
+		ResultPatterns: []string{
+			`(?i)Result:\s*([^\n\r]+)`,
+			`(?i)Status:\s*([^\n\r]+)`,
+			`(?i)(Fixed|Not Fixed|Partially Fixed|Could not test|Passed|Failed|Blocked|Resolved|Verified|Re-Test|Pending|In Progress|N/A)`,
+		},
+		CommentPatterns: []string{
+			`(?i)Comment:\s*(.+)`,
+			`(?i)Notes?:\s*(.+)`,
+			`(?i)Observations?:\s*(.+)`,
+		},
+		QAIndicators: []string{
+			"tested on",
+			"could not test on sw",
+			"qa comment",
+			"qa verification",
+			"qa tested",
+			"test.*result",
+			"test.*passed",
+			"test.*failed",
+			"test.*status",
+		},
+		ResultNormalization: map[string]string{
+			"passed":         "Fixed",
+			"verified":       "Fixed",
+			"resolved":       "Fixed",
+			"re-test":        "Fixed",
+			"failed":         "Not Fixed",
+			"blocked":        "Not Fixed",
+			"pending":        "Not Fixed",
+			"in progress":    "Not Fixed",
+			"n/a":            "N/A",
+			"not applicable": "N/A",
+		},
+	}
+}
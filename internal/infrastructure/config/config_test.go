@@ -3,8 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/rd2w/jira-parser/internal/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,6 +35,120 @@ func TestLoadConfig(t *testing.T) {
 		assert.Equal(t, "test-token", cfg.Token)
 	})
 
+	t.Run("retry settings", func(t *testing.T) {
+		retryConfig := `jira:
+  base_url: "https://test.atlassian.net"
+  username: "test@example.com"
+  token: "test-token"
+  retry:
+    max_attempts: 6
+    initial_backoff: 250ms
+`
+		retryConfigPath := filepath.Join(tempDir, "retry_config.yaml")
+		err := os.WriteFile(retryConfigPath, []byte(retryConfig), 0644)
+		assert.NoError(t, err)
+
+		cfg, err := LoadConfig(retryConfigPath)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, cfg.Retry.MaxAttempts)
+		assert.Equal(t, 250*time.Millisecond, cfg.Retry.InitialBackoff)
+		assert.Equal(t, 30*time.Second, cfg.Retry.MaxBackoff) // значение по умолчанию
+	})
+
+	t.Run("project parsers", func(t *testing.T) {
+		parsersConfig := `jira:
+  base_url: "https://test.atlassian.net"
+  username: "test@example.com"
+  token: "test-token"
+parsing:
+  qa_indicators: ["tested on"]
+parsers:
+  - projects: ["CLOUD", "MOB"]
+    parser: regex
+    parsing:
+      qa_indicators: ["verified on"]
+  - projects: ["WEB"]
+`
+		parsersConfigPath := filepath.Join(tempDir, "parsers_config.yaml")
+		err := os.WriteFile(parsersConfigPath, []byte(parsersConfig), 0644)
+		assert.NoError(t, err)
+
+		cfg, err := LoadConfig(parsersConfigPath)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"tested on"}, cfg.Parsing.QAIndicators)
+		assert.Len(t, cfg.Parsers, 2)
+		assert.Equal(t, []string{"CLOUD", "MOB"}, cfg.Parsers[0].Projects)
+		assert.Equal(t, "regex", cfg.Parsers[0].Parser)
+		assert.Equal(t, []string{"verified on"}, cfg.Parsers[0].Parsing.QAIndicators)
+		assert.Nil(t, cfg.Parsers[1].Parsing)
+
+		offline, err := LoadOfflineConfig(parsersConfigPath)
+		assert.NoError(t, err)
+		assert.Len(t, offline.Parsers, 2)
+	})
+
+	t.Run("comment template", func(t *testing.T) {
+		templateConfig := `jira:
+  base_url: "https://test.atlassian.net"
+  username: "test@example.com"
+  token: "test-token"
+parsing:
+  qa_indicators: ["tested on"]
+  template:
+    fields:
+      - name: version
+        labels: ["Tested on"]
+        required: true
+      - name: result
+        labels: ["Result", "Status"]
+        values: ["Fixed", "Not Fixed"]
+        required: true
+`
+		templateConfigPath := filepath.Join(tempDir, "template_config.yaml")
+		err := os.WriteFile(templateConfigPath, []byte(templateConfig), 0644)
+		assert.NoError(t, err)
+
+		cfg, err := LoadConfig(templateConfigPath)
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg.Parsing.Template) {
+			assert.Equal(t, []domain.TemplateField{
+				{Name: "version", Labels: []string{"Tested on"}, Required: true},
+				{Name: "result", Labels: []string{"Result", "Status"}, Values: []string{"Fixed", "Not Fixed"}, Required: true},
+			}, cfg.Parsing.Template.Fields)
+		}
+	})
+
+	t.Run("result categories", func(t *testing.T) {
+		categoriesConfig := `jira:
+  base_url: "https://test.atlassian.net"
+  username: "test@example.com"
+  token: "test-token"
+parsing:
+  result_categories:
+    Needs Retest: blocked
+    Partially OK: partial
+`
+		categoriesConfigPath := filepath.Join(tempDir, "categories_config.yaml")
+		err := os.WriteFile(categoriesConfigPath, []byte(categoriesConfig), 0644)
+		assert.NoError(t, err)
+
+		cfg, err := LoadConfig(categoriesConfigPath)
+		assert.NoError(t, err)
+		// viper приводит ключи к нижнему регистру, классификатор сравнивает их без учета регистра
+		assert.Equal(t, map[string]string{"needs retest": "blocked", "partially ok": "partial"}, cfg.Parsing.ResultCategories)
+
+		invalidConfig := strings.Replace(categoriesConfig, "Needs Retest: blocked", "Needs Retest: later", 1)
+		err = os.WriteFile(categoriesConfigPath, []byte(invalidConfig), 0644)
+		assert.NoError(t, err)
+
+		_, err = LoadConfig(categoriesConfigPath)
+		var configErr *ConfigError
+		if assert.ErrorAs(t, err, &configErr) {
+			assert.Equal(t, "parsing.result_categories", configErr.Field)
+			assert.Contains(t, configErr.Message, `unknown result category "later"`)
+		}
+	})
+
 	t.Run("missing base_url", func(t *testing.T) {
 		invalidConfig := `jira:
      username: "test@example.com"
@@ -9,6 +9,10 @@ import (
 // TicketsConfig represents the tickets configuration
 type TicketsConfig struct {
 	Tickets []string `yaml:"tickets"`
+	// JQL is an optional query whose matching issues are added to Tickets
+	JQL string `yaml:"jql"`
+	// Filter is an optional filter expression applied to the comments of these tickets
+	Filter string `yaml:"filter"`
 }
 
 // LoadTickets loads tickets from a YAML file
@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func adfBody(lines ...string) map[string]interface{} {
+	var content []interface{}
+	for i, line := range lines {
+		if i > 0 {
+			content = append(content, map[string]string{"type": "hardBreak"})
+		}
+		content = append(content, map[string]string{"type": "text", "text": line})
+	}
+	return map[string]interface{}{
+		"type":    "doc",
+		"version": 1,
+		"content": []interface{}{map[string]interface{}{"type": "paragraph", "content": content}},
+	}
+}
+
+func TestParseCommentFormat(t *testing.T) {
+	format, err := ParseCommentFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, CommentFormatWiki, format)
+
+	format, err = ParseCommentFormat("adf")
+	assert.NoError(t, err)
+	assert.Equal(t, CommentFormatADF, format)
+
+	_, err = ParseCommentFormat("html")
+	assert.Error(t, err)
+}
+
+func TestTextCommentBodies(t *testing.T) {
+	t.Parallel()
+
+	// Тела в wiki-разметке не трогаем и не перекодируем
+	wiki := json.RawMessage(`{"fields":{"comment":{"comments":[{"body":"Tested on v1.0"}]}}}`)
+	converted, err := textCommentBodies(wiki, "fields", "comment", "comments")
+	assert.NoError(t, err)
+	assert.Equal(t, string(wiki), string(converted))
+
+	noComments := json.RawMessage(`{"fields":{"summary":"x"}}`)
+	converted, err = textCommentBodies(noComments, "fields", "comment", "comments")
+	assert.NoError(t, err)
+	assert.Equal(t, string(noComments), string(converted))
+
+	raw, _ := json.Marshal(map[string]interface{}{
+		"comments": []interface{}{map[string]interface{}{"id": "1", "body": adfBody("Tested on v2.0", "Result: Fixed")}},
+	})
+	converted, err = textCommentBodies(raw, "comments")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"comments":[{"id":"1","body":"Tested on v2.0\nResult: Fixed"}]}`, string(converted))
+}
+
+func TestGetIssueWithCommentsADF(t *testing.T) {
+	t.Parallel()
+
+	comment := func(id, version string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":      id,
+			"body":    adfBody("Tested on "+version, "Result: Fixed"),
+			"created": "2025-07-01T10:00:00.000+0300",
+			"author":  map[string]string{"emailAddress": "qa@example.com"},
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/issue/TOS-1":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"key": "TOS-1",
+				"fields": map[string]interface{}{
+					"summary": "Cloud ticket",
+					"comment": map[string]interface{}{"total": 2, "comments": []interface{}{comment("1", "v3.0.0")}},
+				},
+			})
+		case "/rest/api/3/issue/TOS-1/comment":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"total":    2,
+				"comments": []interface{}{comment("1", "v3.0.0"), comment("2", "v3.0.1")},
+			})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	jc := newTestClient(t, server)
+	jc.commentFormat = CommentFormatADF
+
+	info, comments, err := jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.False(t, info.CommentsIncomplete)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "Tested on v3.0.1\nResult: Fixed", comments[1].Body)
+}
@@ -0,0 +1,239 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/rd2w/jira-parser/internal/infrastructure/cache"
+)
+
+// jiraTimeLayout is the timestamp format used by the JIRA REST API
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// revalidationMargin widens the JQL date window: JQL interprets dates in the
+// JIRA user's time zone, which may differ from the offset of the cached timestamp.
+// The exact comparison of updated timestamps makes the final decision.
+const revalidationMargin = 24 * time.Hour
+
+// issueUpdated extracts the raw updated timestamp, which go-jira would reformat
+type issueUpdated struct {
+	Fields struct {
+		Updated string `json:"updated"`
+	} `json:"fields"`
+}
+
+// formatCache returns the part of c that holds issues read through the REST API of format.
+// Cached comment bodies are already converted to text, so entries stored under another
+// comment format must not be reused.
+func formatCache(c *cache.FileCache, format CommentFormat) *cache.FileCache {
+	return cache.New(filepath.Join(c.Dir(), "api"+format.apiVersion()))
+}
+
+// revalidation is the outcome of RevalidateCacheWithContext for the cache entry with the given updated value
+type revalidation struct {
+	updated   string
+	unchanged bool
+}
+
+// RevalidateCacheWithContext checks every cached issue among issueKeys with one JQL search
+// per searchPageSize keys, asking JIRA only for issues updated since the oldest cached time.
+// Later loads of confirmed issues use the cache without another request.
+func (jc *JiraClient) RevalidateCacheWithContext(ctx context.Context, issueKeys []string) error {
+	if jc.cache == nil || jc.refreshCache {
+		return nil
+	}
+
+	cached := make(map[string]string)
+	for _, issueKey := range issueKeys {
+		// Ошибки чтения записи сообщит loadCachedIssue
+		if entry, err := jc.cache.Get(issueKey); err == nil && entry != nil {
+			cached[strings.ToUpper(issueKey)] = entry.Updated
+		}
+	}
+
+	batch := make(map[string]string)
+	flush := func() error {
+		unchanged, err := jc.unchangedIssues(ctx, batch)
+		if err != nil {
+			return err
+		}
+		for issueKey, updated := range batch {
+			_, ok := unchanged[issueKey]
+			jc.revalidated.Store(issueKey, revalidation{updated: updated, unchanged: ok})
+		}
+		batch = make(map[string]string)
+		return nil
+	}
+
+	keys := make([]string, 0, len(cached))
+	for issueKey := range cached {
+		keys = append(keys, issueKey)
+	}
+	sort.Strings(keys)
+
+	for _, issueKey := range keys {
+		batch[issueKey] = cached[issueKey]
+		if len(batch) == searchPageSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Revalidated %d cached issues", len(cached))
+	return nil
+}
+
+// loadCachedIssue returns the cached issue if JIRA reports no changes since it was stored
+func (jc *JiraClient) loadCachedIssue(ctx context.Context, issueKey string) (*loadedIssue, bool) {
+	entry, err := jc.cache.Get(issueKey)
+	if err != nil {
+		log.Printf("Warning: ignoring cache entry for %s: %v", issueKey, err)
+		return nil, false
+	}
+	if entry == nil {
+		return nil, false
+	}
+
+	fresh, err := jc.isUnchanged(ctx, issueKey, entry.Updated)
+	if err != nil {
+		log.Printf("Warning: could not revalidate cached %s, refetching: %v", issueKey, err)
+		return nil, false
+	}
+	if !fresh {
+		return nil, false
+	}
+
+	issue := &jira.Issue{}
+	if err := json.Unmarshal(entry.Issue, issue); err != nil {
+		log.Printf("Warning: ignoring corrupt cache entry for %s: %v", issueKey, err)
+		return nil, false
+	}
+	var comments []*jira.Comment
+	if len(entry.Comments) > 0 {
+		if err := json.Unmarshal(entry.Comments, &comments); err != nil {
+			log.Printf("Warning: ignoring corrupt cache entry for %s: %v", issueKey, err)
+			return nil, false
+		}
+	}
+	issue.Fields.Comments = &jira.Comments{Comments: comments}
+
+	log.Printf("Using cached %s (updated %s)", issueKey, entry.Updated)
+	return &loadedIssue{Issue: issue}, true
+}
+
+// isUnchanged uses the result of RevalidateCacheWithContext for the cached entry and
+// asks JIRA about the single issue only when it was not revalidated
+func (jc *JiraClient) isUnchanged(ctx context.Context, issueKey, updated string) (bool, error) {
+	issueKey = strings.ToUpper(issueKey)
+	if checked, ok := jc.revalidated.Load(issueKey); ok && checked.(revalidation).updated == updated {
+		return checked.(revalidation).unchanged, nil
+	}
+
+	unchanged, err := jc.unchangedIssues(ctx, map[string]string{issueKey: updated})
+	if err != nil {
+		return false, err
+	}
+	_, ok := unchanged[issueKey]
+	return ok, nil
+}
+
+// unchangedIssues takes cached updated timestamps by issue key and returns those JIRA still
+// reports unchanged. A single search restricted by JQL to changes after the oldest cached
+// timestamp returns only the updated field.
+func (jc *JiraClient) unchangedIssues(ctx context.Context, cached map[string]string) (map[string]string, error) {
+	cachedAt := make(map[string]time.Time, len(cached))
+	keys := make([]string, 0, len(cached))
+	var oldest time.Time
+	for issueKey, updated := range cached {
+		t, err := time.Parse(jiraTimeLayout, updated)
+		if err != nil {
+			log.Printf("Warning: invalid cached updated timestamp %q of %s", updated, issueKey)
+			continue
+		}
+		cachedAt[issueKey] = t
+		keys = append(keys, fmt.Sprintf("%q", issueKey))
+		if oldest.IsZero() || t.Before(oldest) {
+			oldest = t
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	sort.Strings(keys)
+
+	since := oldest.Add(-revalidationMargin).Format("2006/01/02 15:04")
+	jql := fmt.Sprintf(`key in (%s) AND updated >= "%s"`, strings.Join(keys, ", "), since)
+
+	changed := make(map[string]time.Time)
+	startAt := 0
+	for {
+		issues, resp, err := jc.client.Issue.SearchWithContext(ctx, jql, &jira.SearchOptions{
+			StartAt:    startAt,
+			MaxResults: searchPageSize,
+			Fields:     []string{"updated"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if issue.Fields != nil {
+				changed[strings.ToUpper(issue.Key)] = time.Time(issue.Fields.Updated)
+			}
+		}
+
+		startAt += len(issues)
+		if len(issues) == 0 || resp == nil || startAt >= resp.Total {
+			break
+		}
+	}
+
+	// Тикет, которого нет в ответе, не обновлялся с момента загрузки
+	unchanged := make(map[string]string)
+	for issueKey, t := range cachedAt {
+		if updated, ok := changed[issueKey]; !ok || updated.Equal(t) {
+			unchanged[issueKey] = cached[issueKey]
+		}
+	}
+	return unchanged, nil
+}
+
+// storeIssue saves the raw issue together with its complete comment list
+func (jc *JiraClient) storeIssue(issueKey string, raw json.RawMessage, issue *jira.Issue) {
+	var updated issueUpdated
+	if err := json.Unmarshal(raw, &updated); err != nil || updated.Fields.Updated == "" {
+		return
+	}
+
+	var comments []*jira.Comment
+	if issue.Fields.Comments != nil {
+		comments = issue.Fields.Comments.Comments
+	}
+	rawComments, err := json.Marshal(comments)
+	if err != nil {
+		log.Printf("Warning: could not cache %s: %v", issueKey, err)
+		return
+	}
+
+	if err := jc.cache.Put(&cache.Entry{
+		Key:       issueKey,
+		Updated:   updated.Fields.Updated,
+		FetchedAt: time.Now(),
+		Issue:     raw,
+		Comments:  rawComments,
+	}); err != nil {
+		log.Printf("Warning: could not cache %s: %v", issueKey, err)
+	}
+}
@@ -0,0 +1,220 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/infrastructure/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+var cacheIssuePathRe = regexp.MustCompile(`^/rest/api/([23])/issue/([^/]+)$`)
+
+// cacheTestServer serves issues with mutable updated timestamps and counts requests per endpoint
+type cacheTestServer struct {
+	mu       sync.Mutex
+	updated  map[string]string
+	requests map[string]int
+	queries  []string
+}
+
+func newCacheTestServer(updated map[string]string) *cacheTestServer {
+	return &cacheTestServer{updated: updated, requests: map[string]int{}}
+}
+
+func (s *cacheTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests[r.URL.Path]++
+
+	// REST API v3 отдает тело комментария в ADF и с другой версией, чтобы тесты различали форматы
+	if match := cacheIssuePathRe.FindStringSubmatch(r.URL.Path); match != nil {
+		updated, ok := s.updated[match[2]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body interface{} = "Tested on v2.0.0\nResult: Fixed"
+		if match[1] == "3" {
+			body = adfBody("Tested on v3.0.0", "Result: Fixed")
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"key": match[2],
+			"fields": map[string]interface{}{
+				"summary": "Cached ticket",
+				"updated": updated,
+				"comment": map[string]interface{}{
+					"total": 1,
+					"comments": []interface{}{map[string]interface{}{
+						"body":    body,
+						"created": "2025-07-01T10:00:00.000+0300",
+					}},
+				},
+			},
+		})
+		return
+	}
+
+	if r.URL.Path != "/rest/api/2/search" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	jql := r.URL.Query().Get("jql")
+	s.queries = append(s.queries, jql)
+	if !strings.HasPrefix(jql, "key in (") || !strings.Contains(jql, ") AND updated >= ") {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Отбор по дате не эмулируем: клиент сам сравнивает updated с кэшем
+	var issues []interface{}
+	for key, updated := range s.updated {
+		if strings.Contains(jql, `"`+key+`"`) {
+			issues = append(issues, map[string]interface{}{"key": key, "fields": map[string]string{"updated": updated}})
+		}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"total": len(issues), "issues": issues})
+}
+
+func (s *cacheTestServer) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func (s *cacheTestServer) setUpdated(key, updated string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updated[key] = updated
+}
+
+func (s *cacheTestServer) lastQuery() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries[len(s.queries)-1]
+}
+
+func TestLoadIssueUsesCacheUntilUpdated(t *testing.T) {
+	t.Parallel()
+
+	handler := newCacheTestServer(map[string]string{"TOS-1": "2025-07-01T10:00:00.000+0300"})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	jc := newTestClient(t, server)
+	jc.cache = cache.New(t.TempDir())
+
+	const issuePath = "/rest/api/2/issue/TOS-1"
+
+	// Первая загрузка идет в JIRA и сохраняется в кэш
+	_, comments, err := jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, 1, handler.count(issuePath))
+
+	// Тикет не менялся: используется кэш, в JIRA уходит только ревалидация
+	info, comments, err := jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Cached ticket", info.Summary)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, 1, handler.count(issuePath))
+	assert.Equal(t, 1, handler.count("/rest/api/2/search"))
+	assert.Equal(t, `key in ("TOS-1") AND updated >= "2025/06/30 10:00"`, handler.lastQuery())
+
+	// Тикет изменился: загружаем заново
+	handler.setUpdated("TOS-1", "2025-07-02T09:30:00.000+0300")
+	_, _, err = jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, handler.count(issuePath))
+
+	// --refresh игнорирует кэш без ревалидации
+	jc.refreshCache = true
+	_, _, err = jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, handler.count(issuePath))
+	assert.Equal(t, 2, handler.count("/rest/api/2/search"))
+}
+
+func TestRevalidateCacheBatchesSearch(t *testing.T) {
+	t.Parallel()
+
+	handler := newCacheTestServer(map[string]string{
+		"TOS-1": "2025-07-01T10:00:00.000+0300",
+		"TOS-2": "2025-07-03T10:00:00.000+0300",
+		"TOS-3": "2025-07-05T10:00:00.000+0300",
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	jc := newTestClient(t, server)
+	jc.cache = cache.New(t.TempDir())
+
+	keys := []string{"TOS-1", "TOS-2", "TOS-3"}
+	for _, key := range keys {
+		_, _, err := jc.GetIssueWithComments(key)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 0, handler.count("/rest/api/2/search"))
+
+	// Один поисковый запрос на все закэшированные тикеты, начиная с самого старого
+	handler.setUpdated("TOS-2", "2025-07-04T08:00:00.000+0300")
+	assert.NoError(t, jc.RevalidateCacheWithContext(context.Background(), keys))
+	assert.Equal(t, 1, handler.count("/rest/api/2/search"))
+	assert.Equal(t, `key in ("TOS-1", "TOS-2", "TOS-3") AND updated >= "2025/06/30 10:00"`, handler.lastQuery())
+
+	// Неизмененные тикеты берутся из кэша без запросов, измененный загружается заново
+	for _, key := range keys {
+		_, _, err := jc.GetIssueWithComments(key)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, handler.count("/rest/api/2/search"))
+	assert.Equal(t, 1, handler.count("/rest/api/2/issue/TOS-1"))
+	assert.Equal(t, 2, handler.count("/rest/api/2/issue/TOS-2"))
+	assert.Equal(t, 1, handler.count("/rest/api/2/issue/TOS-3"))
+}
+
+func TestCacheSeparatesCommentFormats(t *testing.T) {
+	t.Parallel()
+
+	handler := newCacheTestServer(map[string]string{"TOS-1": "2025-07-01T10:00:00.000+0300"})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	root := t.TempDir()
+	wiki := newTestClient(t, server)
+	wiki.cache = formatCache(cache.New(root), CommentFormatWiki)
+	adf := newTestClient(t, server)
+	adf.commentFormat = CommentFormatADF
+	adf.cache = formatCache(cache.New(root), CommentFormatADF)
+
+	_, comments, err := wiki.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Tested on v2.0.0\nResult: Fixed", comments[0].Body)
+
+	// Запись, сохраненная через REST API v2, не используется клиентом ADF
+	_, comments, err = adf.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Tested on v3.0.0\nResult: Fixed", comments[0].Body)
+	assert.Equal(t, 1, handler.count("/rest/api/3/issue/TOS-1"))
+	assert.Equal(t, 0, handler.count("/rest/api/2/search"))
+
+	// Каждый формат читает свою запись
+	_, comments, err = wiki.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Tested on v2.0.0\nResult: Fixed", comments[0].Body)
+	_, comments, err = adf.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Tested on v3.0.0\nResult: Fixed", comments[0].Body)
+	assert.Equal(t, 1, handler.count("/rest/api/2/issue/TOS-1"))
+	assert.Equal(t, 1, handler.count("/rest/api/3/issue/TOS-1"))
+
+	assert.FileExists(t, filepath.Join(root, "api2", "TOS-1.json"))
+	assert.FileExists(t, filepath.Join(root, "api3", "TOS-1.json"))
+}
@@ -2,27 +2,106 @@ package jira
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
-	"regexp"
+	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/andygrunwald/go-jira"
 	"github.com/rd2w/jira-parser/internal/domain"
 	"github.com/rd2w/jira-parser/internal/infrastructure/auth"
+	"github.com/rd2w/jira-parser/internal/infrastructure/cache"
 )
 
 const (
 	// QAOwnerField represents the custom field for QA Owner in JIRA
 	QAOwnerField = "customfield_12601"
+
+	// searchPageSize is the number of issues requested per search page
+	searchPageSize = 100
 )
 
 type JiraClient struct {
-	client        *jira.Client
-	parsingConfig domain.ParsingConfig
+	client *jira.Client
+
+	// cache is optional; with refreshCache set it is written but never read
+	cache        *cache.FileCache
+	refreshCache bool
+	// revalidated holds a revalidation per issue key checked by RevalidateCacheWithContext
+	revalidated sync.Map
+
+	// commentFormat selects the REST API version and thus the comment body format
+	commentFormat CommentFormat
+}
+
+// clientOptions collects the optional settings of NewJiraClient
+type clientOptions struct {
+	retryPolicy   RetryPolicy
+	rateLimit     float64
+	cache         *cache.FileCache
+	refreshCache  bool
+	commentFormat CommentFormat
+}
+
+// ClientOption configures optional behaviour of the JIRA client
+type ClientOption func(*clientOptions)
+
+// WithRetryPolicy overrides DefaultRetryPolicy for the client's HTTP transport
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithRateLimit limits the HTTP requests sent to JIRA to requestsPerSecond (0 - no limit).
+// The limit is shared by all goroutines using the client and counts every request, retries included.
+func WithRateLimit(requestsPerSecond float64) ClientOption {
+	return func(o *clientOptions) {
+		o.rateLimit = requestsPerSecond
+	}
+}
+
+// WithCache stores fetched issues in c and reuses them while JIRA reports no updates.
+// With refresh set, cached entries are ignored and overwritten.
+func WithCache(c *cache.FileCache, refresh bool) ClientOption {
+	return func(o *clientOptions) {
+		o.cache = c
+		o.refreshCache = refresh
+	}
+}
+
+// WithCommentFormat selects the comment body format of the instance. With CommentFormatADF
+// issues are read through REST API v3 and ADF bodies are converted to plain text.
+func WithCommentFormat(format CommentFormat) ClientOption {
+	return func(o *clientOptions) {
+		o.commentFormat = format
+	}
+}
+
+func NewJiraClient(baseURL, username, token string, opts ...ClientOption) (*JiraClient, error) {
+	return NewJiraClientWithContext(context.Background(), baseURL, username, token, opts...)
 }
 
-func NewJiraClient(baseURL, username, token string, parsingConfig domain.ParsingConfig) (*JiraClient, error) {
+// NewJiraClientWithContext creates the client; ctx bounds the token validation request
+func NewJiraClientWithContext(ctx context.Context, baseURL, username, token string, opts ...ClientOption) (*JiraClient, error) {
+	options := clientOptions{retryPolicy: DefaultRetryPolicy(), commentFormat: CommentFormatWiki}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// The rate limiter sits below the retry transport, so every attempt takes its own slot
+	var base http.RoundTripper = http.DefaultTransport
+	if options.rateLimit > 0 {
+		base = newRateLimitTransport(base, options.rateLimit)
+	}
+
+	// Retries happen underneath authentication, so every attempt carries the credentials
+	retrying := newRetryTransport(base, options.retryPolicy)
+
 	var client *jira.Client
 	var err error
 
@@ -30,21 +109,24 @@ func NewJiraClient(baseURL, username, token string, parsingConfig domain.Parsing
 	if strings.HasPrefix(strings.ToLower(token), "bearer ") {
 		// Bearer token authentication
 		tp := jira.BearerAuthTransport{
-			Token: strings.TrimPrefix(token, "bearer "),
+			Token:     strings.TrimPrefix(token, "bearer "),
+			Transport: retrying,
 		}
 		client, err = jira.NewClient(tp.Client(), baseURL)
 	} else if strings.HasPrefix(strings.ToLower(token), "basic ") {
 		// Basic token authentication
 		tp := jira.BasicAuthTransport{
-			Username: username,
-			Password: strings.TrimPrefix(token, "basic "),
+			Username:  username,
+			Password:  strings.TrimPrefix(token, "basic "),
+			Transport: retrying,
 		}
 		client, err = jira.NewClient(tp.Client(), baseURL)
 	} else {
 		// Assume personal access token or password
 		tp := jira.BasicAuthTransport{
-			Username: username,
-			Password: token,
+			Username:  username,
+			Password:  token,
+			Transport: retrying,
 		}
 		client, err = jira.NewClient(tp.Client(), baseURL)
 	}
@@ -55,329 +137,233 @@ func NewJiraClient(baseURL, username, token string, parsingConfig domain.Parsing
 
 	// Validate the token before returning the client
 	validator := auth.NewJiraTokenValidator()
-	if err := validator.ValidateToken(baseURL, username, token); err != nil {
+	if err := validator.ValidateTokenWithContext(ctx, baseURL, username, token); err != nil {
 		return nil, fmt.Errorf("token validation failed: %w", err)
 	}
 
-	return &JiraClient{client: client, parsingConfig: parsingConfig}, nil
+	issueCache := options.cache
+	if issueCache != nil {
+		issueCache = formatCache(issueCache, options.commentFormat)
+	}
+
+	return &JiraClient{
+		client:        client,
+		cache:         issueCache,
+		refreshCache:  options.refreshCache,
+		commentFormat: options.commentFormat,
+	}, nil
 }
 
+// issueFields lists every field needed to build both IssueInfo and the comments,
+// so a single GET of the issue is enough
+var issueFields = "summary,assignee,updated,comment," + QAOwnerField
+
 func (jc *JiraClient) GetIssueInfo(issueKey string) (*domain.IssueInfo, error) {
-	issue, _, err := jc.client.Issue.GetWithContext(context.Background(), issueKey, &jira.GetQueryOptions{
-		Expand: "names,renderedFields", // Расширяем, чтобы получить больше информации о полях
-	})
+	return jc.GetIssueInfoWithContext(context.Background(), issueKey)
+}
+
+func (jc *JiraClient) GetIssueInfoWithContext(ctx context.Context, issueKey string) (*domain.IssueInfo, error) {
+	issue, err := jc.loadIssue(ctx, issueKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get issue %s: %w", issueKey, err)
+		return nil, err
 	}
 
-	assigneeEmail := ""
-	if issue.Fields.Assignee != nil {
-		assigneeEmail = issue.Fields.Assignee.EmailAddress
-	}
+	return issueInfo(issue.Issue), nil
+}
 
-	// Попробуем получить QA владельца из кастомного поля customfield_12601
-	qaOwnerEmail := jc.getQaOwnerFromCustomField(issue)
+// GetIssueWithComments fetches the issue once and returns both its info and all of its comments
+func (jc *JiraClient) GetIssueWithComments(issueKey string) (*domain.IssueInfo, []domain.RawComment, error) {
+	return jc.GetIssueWithCommentsWithContext(context.Background(), issueKey)
+}
 
-	// Если кастомное поле пустое, используем резервную логику - последний QA комментарий
-	if qaOwnerEmail == "" {
-		qaOwnerEmail = jc.getQaOwnerFromLastComment(issue)
+func (jc *JiraClient) GetIssueWithCommentsWithContext(ctx context.Context, issueKey string) (*domain.IssueInfo, []domain.RawComment, error) {
+	issue, err := jc.loadIssue(ctx, issueKey)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return &domain.IssueInfo{
-		Key:           issue.Key,
-		Summary:       issue.Fields.Summary,
-		AssigneeEmail: assigneeEmail,
-		QaOwnerEmail:  qaOwnerEmail,
-	}, nil
+	info := issueInfo(issue.Issue)
+	info.CommentsIncomplete = issue.commentsIncomplete
+	return info, rawComments(issue.Issue), nil
 }
 
-func (jc *JiraClient) GetIssueComments(issueKey string) ([]domain.QAComment, error) {
-	return jc.getComments(context.Background(), issueKey)
+// loadedIssue is an issue together with the completeness of its comment list
+type loadedIssue struct {
+	*jira.Issue
+	commentsIncomplete bool
 }
 
-func (jc *JiraClient) GetLastQAComment(issueKey string) (*domain.QAComment, error) {
+// loadIssue выполняет единственный GET тикета со всеми нужными полями и догружает
+// комментарии постранично, если JIRA усекла их список в ответе
+func (jc *JiraClient) loadIssue(ctx context.Context, issueKey string) (*loadedIssue, error) {
 	if issueKey == "" {
 		return nil, fmt.Errorf("issue key cannot be empty")
 	}
 
-	issue, _, err := jc.client.Issue.GetWithContext(context.Background(), issueKey, &jira.GetQueryOptions{
-		Expand: "comments",
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get issue %s: %w", issueKey, err)
-	}
-
-	if issue.Fields.Comments == nil {
-		log.Printf("No comments found for issue %s", issueKey)
-		return nil, nil
-	}
-
-	// Process comments in reverse order to find the last QA comment
-	for i := len(issue.Fields.Comments.Comments) - 1; i >= 0; i-- {
-		comment := issue.Fields.Comments.Comments[i]
-		if jc.isQAComment(comment.Body) {
-			qaComment, err := jc.parseQAComment(comment.Body, comment.Created)
-			if err != nil {
-				log.Printf("Error parsing QA comment for issue %s: %v", issueKey, err)
-				// Continue processing other comments even if one fails
-				continue
-			}
-
-			// Добавляем email автора комментария
-			qaComment.AuthorEmail = comment.Author.EmailAddress
-
-			// Only return the comment if it has meaningful data
-			if qaComment.SoftwareVersion != "" || qaComment.TestResult != "" || qaComment.Comment != "" {
-				return &qaComment, nil
-			}
+	if jc.cache != nil && !jc.refreshCache {
+		if issue, ok := jc.loadCachedIssue(ctx, issueKey); ok {
+			return issue, nil
 		}
 	}
 
-	return nil, nil
-}
-
-func (jc *JiraClient) getComments(ctx context.Context, issueKey string) ([]domain.QAComment, error) {
-	if issueKey == "" {
-		return nil, fmt.Errorf("issue key cannot be empty")
+	endpoint := fmt.Sprintf("rest/api/%s/issue/%s?fields=%s",
+		jc.commentFormat.apiVersion(), url.PathEscape(issueKey), url.QueryEscape(issueFields))
+	req, err := jc.client.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request for issue %s: %w", issueKey, err)
 	}
 
-	issue, _, err := jc.client.Issue.GetWithContext(ctx, issueKey, &jira.GetQueryOptions{
-		Expand: "comments",
-	})
-	if err != nil {
+	// Сохраняем сырой ответ: go-jira отбрасывает total у встроенного списка комментариев
+	var raw json.RawMessage
+	if _, err := jc.client.Do(req, &raw); err != nil {
 		return nil, fmt.Errorf("failed to get issue %s: %w", issueKey, err)
 	}
 
-	if issue.Fields.Comments == nil {
-		log.Printf("No comments found for issue %s", issueKey)
-		return []domain.QAComment{}, nil
+	// REST API v3 возвращает тела комментариев в ADF; дальше работаем с их текстом
+	raw, err = textCommentBodies(raw, "fields", "comment", "comments")
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode comments of issue %s: %w", issueKey, err)
 	}
 
-	var qaComments []domain.QAComment
-	// Process comments to find QA comments
-	for _, comment := range issue.Fields.Comments.Comments {
-		if jc.isQAComment(comment.Body) {
-			qaComment, err := jc.parseQAComment(comment.Body, comment.Created)
-			if err != nil {
-				log.Printf("Error parsing QA comment for issue %s: %v", issueKey, err)
-				// Continue processing other comments even if one fails
-				continue
-			}
+	issue := &jira.Issue{}
+	if err := json.Unmarshal(raw, issue); err != nil {
+		return nil, fmt.Errorf("failed to decode issue %s: %w", issueKey, err)
+	}
 
-			// Добавляем email автора комментария
-			qaComment.AuthorEmail = comment.Author.EmailAddress
+	loaded := &loadedIssue{
+		Issue:              issue,
+		commentsIncomplete: jc.completeComments(ctx, issue, raw),
+	}
 
-			// Only add the comment if it has meaningful data
-			if qaComment.SoftwareVersion != "" || qaComment.TestResult != "" || qaComment.Comment != "" {
-				qaComments = append(qaComments, qaComment)
-			}
-		}
+	// Неполный список комментариев не кэшируем, чтобы следующий запуск догрузил его
+	if jc.cache != nil && !loaded.commentsIncomplete {
+		jc.storeIssue(issueKey, raw, issue)
 	}
 
-	log.Printf("Found %d QA comments for issue %s", len(qaComments), issueKey)
-	return qaComments, nil
+	return loaded, nil
 }
 
-func (jc *JiraClient) isQAComment(body string) bool {
-	normalized := jc.removeJiraFormatting(body)
-	normalized = strings.ToLower(normalized)
-
-	// Check for various QA comment indicators using configurable patterns
-	for _, indicator := range jc.parsingConfig.QAIndicators {
-		// First, check for exact string matches
-		if strings.Contains(normalized, strings.ToLower(indicator)) {
-			return true
-		}
-		// Then, check if the indicator is a regex pattern
-		if strings.Contains(indicator, ".*") {
-			matched, err := regexp.MatchString("(?is)"+indicator, normalized)
-			if err == nil && matched {
-				return true
-			}
-		}
+// issueInfo собирает основную информацию о тикете из загруженного issue
+func issueInfo(issue *jira.Issue) *domain.IssueInfo {
+	assigneeEmail := ""
+	if issue.Fields.Assignee != nil {
+		assigneeEmail = issue.Fields.Assignee.EmailAddress
 	}
 
-	// Additional check for specific regex patterns that might span multiple words
-	// For example, "test.*result" should match "test scenario: login\nresult: success"
-	for _, indicator := range jc.parsingConfig.QAIndicators {
-		if strings.Contains(indicator, ".*") {
-			// Replace spaces with .* to match patterns across multiple words
-			// This is a special case for patterns like "test.*result" to match "test X result"
-			spaceAwarePattern := strings.ReplaceAll(indicator, " ", ".*")
-			matched, err := regexp.MatchString("(?is)"+spaceAwarePattern, normalized)
-			if err == nil && matched {
-				return true
-			}
-		}
-	}
+	// QA владелец берется из кастомного поля customfield_12601; если оно пустое,
+	// сервис определяет владельца по автору последнего QA комментария
+	qaOwnerEmail := getQaOwnerFromCustomField(issue)
 
-	return false
+	return &domain.IssueInfo{
+		Key:           issue.Key,
+		Summary:       issue.Fields.Summary,
+		AssigneeEmail: assigneeEmail,
+		QaOwnerEmail:  qaOwnerEmail,
+	}
 }
 
-func (jc *JiraClient) parseQAComment(body string, created string) (domain.QAComment, error) {
-	var comment domain.QAComment
-	normalizedBody := jc.removeJiraFormatting(body)
+// SearchIssueKeys resolves a JQL query into issue keys, following all result pages
+func (jc *JiraClient) SearchIssueKeys(jql string) ([]string, error) {
+	return jc.SearchIssueKeysWithContext(context.Background(), jql)
+}
 
-	// Extract version with configurable patterns
-	for _, pattern := range jc.parsingConfig.VersionPatterns {
-		re := regexp.MustCompile(pattern)
-		if matches := re.FindStringSubmatch(normalizedBody); len(matches) > 1 {
-			comment.SoftwareVersion = matches[1]
-			break
-		}
+func (jc *JiraClient) SearchIssueKeysWithContext(ctx context.Context, jql string) ([]string, error) {
+	if strings.TrimSpace(jql) == "" {
+		return nil, fmt.Errorf("jql query cannot be empty")
 	}
 
-	// Handle "could not test" case - extract version if possible
-	if strings.Contains(strings.ToLower(normalizedBody), "could not test on sw") {
-		// Extract version from "could not test on SW vX.X.X" pattern
-		couldNotTestVersionRe := regexp.MustCompile(`(?i)could not test on sw (v?[\d.]+(?:-[\w.]+)?)`)
-		if matches := couldNotTestVersionRe.FindStringSubmatch(normalizedBody); len(matches) > 1 {
-			comment.SoftwareVersion = matches[1]
+	var keys []string
+	startAt := 0
+	for {
+		issues, resp, err := jc.client.Issue.SearchWithContext(ctx, jql, &jira.SearchOptions{
+			StartAt:    startAt,
+			MaxResults: searchPageSize,
+			Fields:     []string{"key"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search issues with jql %q: %w", jql, err)
 		}
-		comment.TestResult = "Could not test"
-	}
 
-	// Extract result with configurable patterns
-	for _, pattern := range jc.parsingConfig.ResultPatterns {
-		re := regexp.MustCompile(pattern)
-		if matches := re.FindStringSubmatch(normalizedBody); len(matches) > 1 {
-			result := strings.TrimSpace(matches[1])
-			// Normalize common variations using configurable mapping
-			if normalized, exists := jc.parsingConfig.ResultNormalization[strings.ToLower(result)]; exists {
-				result = normalized
-			}
-			comment.TestResult = result
-			break
+		for _, issue := range issues {
+			keys = append(keys, issue.Key)
 		}
-	}
 
-	// Extract comment with configurable patterns
-	for _, pattern := range jc.parsingConfig.CommentPatterns {
-		re := regexp.MustCompile(pattern)
-		if matches := re.FindStringSubmatch(normalizedBody); len(matches) > 1 {
-			comment.Comment = strings.TrimSpace(matches[1])
+		startAt += len(issues)
+		// Stop on an empty page as well, so a server that misreports Total can't loop us forever
+		if len(issues) == 0 || resp == nil || startAt >= resp.Total {
 			break
 		}
 	}
 
-	// Set created date
-	comment.Created = created
+	log.Printf("JQL query %q matched %d issues", jql, len(keys))
+	return keys, nil
+}
 
-	// If we still don't have a result but found "could not test" somewhere, set it
-	if comment.TestResult == "" && strings.Contains(strings.ToLower(normalizedBody), "could not test") {
-		comment.TestResult = "Could not test"
-	}
+func (jc *JiraClient) GetIssueComments(issueKey string) ([]domain.RawComment, error) {
+	return jc.GetIssueCommentsWithContext(context.Background(), issueKey)
+}
 
-	// If we still don't have a result, try to infer from other common keywords
-	if comment.TestResult == "" {
-		lowerBody := strings.ToLower(normalizedBody)
-		for indicator, normalizedResult := range jc.parsingConfig.ResultNormalization {
-			if strings.Contains(lowerBody, indicator) {
-				comment.TestResult = normalizedResult
-				break
-			}
-		}
+func (jc *JiraClient) GetIssueCommentsWithContext(ctx context.Context, issueKey string) ([]domain.RawComment, error) {
+	issue, err := jc.loadIssue(ctx, issueKey)
+	if err != nil {
+		return nil, err
 	}
 
-	// Additional fallback for common result indicators not covered by patterns
-	if comment.TestResult == "" {
-		lowerBody := strings.ToLower(normalizedBody)
-		if strings.Contains(lowerBody, "not fixed") {
-			comment.TestResult = "not fixed"
-		} else if strings.Contains(lowerBody, "partially fixed") {
-			comment.TestResult = "partially fixed"
-		} else if strings.Contains(lowerBody, "fixed") {
-			comment.TestResult = "fixed"
-		} else if strings.Contains(lowerBody, "passed") {
-			comment.TestResult = "passed"
-		} else if strings.Contains(lowerBody, "failed") {
-			comment.TestResult = "failed"
-		} else if strings.Contains(lowerBody, "could not test") {
-			comment.TestResult = "could not test"
-		} else if strings.Contains(lowerBody, "verified") {
-			comment.TestResult = "verified"
-		} else if strings.Contains(lowerBody, "resolved") {
-			comment.TestResult = "resolved"
-		} else if strings.Contains(lowerBody, "blocked") {
-			comment.TestResult = "blocked"
-		} else if strings.Contains(lowerBody, "pending") {
-			comment.TestResult = "pending"
-		}
+	return rawComments(issue.Issue), nil
+}
+
+// rawComments переводит комментарии загруженного issue в доменную модель без разбора
+func rawComments(issue *jira.Issue) []domain.RawComment {
+	if issue.Fields.Comments == nil {
+		log.Printf("No comments found for issue %s", issue.Key)
+		return []domain.RawComment{}
 	}
 
-	// Final normalization using configurable mapping
-	if comment.TestResult != "" {
-		if normalized, exists := jc.parsingConfig.ResultNormalization[strings.ToLower(comment.TestResult)]; exists {
-			comment.TestResult = normalized
+	comments := make([]domain.RawComment, 0, len(issue.Fields.Comments.Comments))
+	for _, comment := range issue.Fields.Comments.Comments {
+		if comment == nil {
+			continue
 		}
+		comments = append(comments, domain.RawComment{
+			ID:          comment.ID,
+			Body:        comment.Body,
+			Created:     commentTime(issue.Key, comment.ID, "created", comment.Created),
+			Updated:     commentTime(issue.Key, comment.ID, "updated", comment.Updated),
+			AuthorEmail: comment.Author.EmailAddress,
+		})
 	}
-
-	return comment, nil
+	return comments
 }
 
-// removeJiraFormatting удаляет JIRA-разметку из текста
-func (jc *JiraClient) removeJiraFormatting(text string) string {
-	// Удаляем базовое форматирование JIRA
-	replacements := map[string]string{
-		"*":               "", // жирный
-		"_":               "", // курсив
-		"-":               "", // зачеркивание
-		"??":              "", // моноширинный
-		"{{":              "",
-		"}}":              "",
-		"{*}":             "",
-		"{code}":          "",
-		"{code:":          "",
-		"{noformat}":      "",
-		"{quote}":         "",
-		"{panel}":         "",
-		"{panel:bgcolor=": "",
-		"{color:":         "",
-		"{color}":         "",
-	}
-
-	// Удаляем цветовую разметку {color}
-	colorRe := regexp.MustCompile(`\{color[^\}]*\}(.*?)\{color\}`)
-	text = colorRe.ReplaceAllString(text, "$1")
-
-	// Удаляем код-блоки {code}...{code}
-	codeRe := regexp.MustCompile(`\{code[^\}]*\}(.*?)\{code\}`)
-	text = codeRe.ReplaceAllString(text, "$1")
+// timeLayouts - форматы дат комментариев: REST API (с миллисекундами или без) и RFC3339
+var timeLayouts = []string{jiraTimeLayout, "2006-01-02T15:04:05-0700", time.RFC3339Nano}
 
-	// Удаляем панели {panel}...{panel}
-	panelRe := regexp.MustCompile(`\{panel[^\}]*\}(.*?)\{panel\}`)
-	text = panelRe.ReplaceAllString(text, "$1")
-
-	// Удаляем другие элементы форматирования
-	for old, new := range replacements {
-		text = strings.ReplaceAll(text, old, new)
+// parseTime разбирает дату JIRA; пустая строка дает нулевое время
+func parseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
 	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date format %q", value)
+}
 
-	// Удаляем ссылки [текст|url]
-	linkRe := regexp.MustCompile(`\[([^\|\]]+)(?:\|[^\]]+)?\]`)
-	text = linkRe.ReplaceAllString(text, "$1")
-
-	// Удаляем встроенные изображения !image.png!
-	imageRe := regexp.MustCompile(`!\S*!`)
-	text = imageRe.ReplaceAllString(text, "")
-
-	// Удаляем упоминания пользователей [~username]
-	mentionRe := regexp.MustCompile(`\[~[^\]]+\]`)
-	text = mentionRe.ReplaceAllString(text, "")
-
-	// Удаляем метки {noformat}...{noformat}
-	noformatRe := regexp.MustCompile(`\{noformat\}(.*?)\{noformat\}`)
-	text = noformatRe.ReplaceAllString(text, "$1")
-
-	// Удаляем цитаты {quote}...{quote}
-	quoteRe := regexp.MustCompile(`\{quote\}(.*?)\{quote\}`)
-	text = quoteRe.ReplaceAllString(text, "$1")
-
-	return strings.TrimSpace(text)
+// commentTime разбирает дату комментария; нераспознанная дата становится нулевой, чтобы
+// такие комментарии не проходили фильтры по дате, и попадает в лог
+func commentTime(issueKey, commentID, field, value string) time.Time {
+	t, err := parseTime(value)
+	if err != nil {
+		log.Printf("Warning: comment %s of %s has an invalid %s date: %v", commentID, issueKey, field, err)
+	}
+	return t
 }
 
 // getQaOwnerFromCustomField пытается получить email QA владельца из кастомного поля
-func (jc *JiraClient) getQaOwnerFromCustomField(issue *jira.Issue) string {
+func getQaOwnerFromCustomField(issue *jira.Issue) string {
 	if qaOwnerField, exists := issue.Fields.Unknowns[QAOwnerField]; exists && qaOwnerField != nil {
 		// Проверяем, что поле содержит структуру пользователя с email
 		if userMap, ok := qaOwnerField.(map[string]interface{}); ok {
@@ -388,20 +374,3 @@ func (jc *JiraClient) getQaOwnerFromCustomField(issue *jira.Issue) string {
 	}
 	return ""
 }
-
-// getQaOwnerFromLastComment определяет QA владельца как пользователя, оставившего последний QA комментарий
-func (jc *JiraClient) getQaOwnerFromLastComment(issue *jira.Issue) string {
-	if issue.Fields.Comments == nil {
-		return ""
-	}
-
-	// Ищем последний QA комментарий и возвращаем email его автора
-	for i := len(issue.Fields.Comments.Comments) - 1; i >= 0; i-- {
-		comment := issue.Fields.Comments.Comments[i]
-		if jc.isQAComment(comment.Body) && comment.Author.EmailAddress != "" {
-			return comment.Author.EmailAddress
-		}
-	}
-
-	return ""
-}
@@ -1,360 +1,147 @@
 package jira
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
-	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/andygrunwald/go-jira"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestIsQAComment(t *testing.T) {
+func TestSearchIssueKeysPagination(t *testing.T) {
 	t.Parallel()
 
-	// Create a JiraClient with default parsing configuration
-	parsingConfig := domain.ParsingConfig{
-		QAIndicators: []string{
-			"tested on",
-			"could not test on sw",
-			"qa comment",
-			"qa verification",
-			"qa tested",
-			"test.*result",
-			"test.*passed",
-			"test.*failed",
-			"test.*status",
-		},
-	}
-	jc := &JiraClient{parsingConfig: parsingConfig}
-
-	tests := []struct {
-		name     string
-		body     string
-		expected bool
-	}{
-		{
-			name:     "contains Tested on",
-			body:     "Tested on v1.2.3\nResult: Fixed",
-			expected: true,
-		},
-		{
-			name:     "contains Could not test on SW",
-			body:     "Could not test on SW v1.2.3 due to environment issues",
-			expected: true,
-		},
-		{
-			name:     "contains QA Comment",
-			body:     "QA Comment: Tested functionality\nResult: Passed",
-			expected: true,
-		},
-		{
-			name:     "contains Test and Result",
-			body:     "Test scenario: Login\nResult: Success",
-			expected: true,
-		},
-		{
-			name:     "regular comment",
-			body:     "This is a regular comment without QA keywords",
-			expected: false,
-		},
-		{
-			name:     "JIRA formatted text",
-			body:     "*Tested* on _v2.0.0_ ??Result:?? *Fixed*",
-			expected: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := jc.isQAComment(tt.body)
-			assert.Equal(t, tt.expected, result)
+	const total = 5
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/rest/api/2/search", r.URL.Path)
+		assert.Equal(t, "project = TOS", r.URL.Query().Get("jql"))
+
+		// Serve at most two issues per page regardless of the requested page size
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		var issues []map[string]string
+		for i := startAt; i < total && i < startAt+2; i++ {
+			issues = append(issues, map[string]string{"key": fmt.Sprintf("TOS-%d", i+1)})
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"startAt":    startAt,
+			"maxResults": 2,
+			"total":      total,
+			"issues":     issues,
 		})
-	}
-}
+	}))
+	defer server.Close()
 
-func TestParseQAComment(t *testing.T) {
-	t.Parallel()
+	client, err := jira.NewClient(nil, server.URL)
+	assert.NoError(t, err)
+	jc := &JiraClient{client: client}
 
-	// Create a JiraClient with default parsing configuration
-	parsingConfig := domain.ParsingConfig{
-		VersionPatterns: []string{
-			`(?i)Tested on (?:SW )?(v?[\d.]+(?:-[\w.]+)?)`,
-			`(?i)version.*?(v?[\d.]+(?:-[\w.]+)?)`,
-			`(?i)sw.*?(v?[\d.]+(?:-[\w.]+)?)`,
-		},
-		ResultPatterns: []string{
-			`(?i)Result:\s*([^\n\r]+)`,
-			`(?i)Status:\s*([^\n\r]+)`,
-			`(?i)(Fixed|Not Fixed|Partially Fixed|Could not test|Passed|Failed|Blocked|Resolved|Verified|Re-Test|Pending|In Progress|N/A)`,
-		},
-		CommentPatterns: []string{
-			`(?i)Comment:\s*(.+)`,
-			`(?i)Notes?:\s*(.+)`,
-			`(?i)Observations?:\s*(.+)`,
-		},
-		ResultNormalization: map[string]string{
-			"passed":         "Fixed",
-			"verified":       "Fixed",
-			"resolved":       "Fixed",
-			"re-test":        "Fixed",
-			"failed":         "Not Fixed",
-			"blocked":        "Not Fixed",
-			"pending":        "Not Fixed",
-			"in progress":    "Not Fixed",
-			"n/a":            "N/A",
-			"not applicable": "N/A",
-		},
-	}
-	jc := &JiraClient{parsingConfig: parsingConfig}
+	keys, err := jc.SearchIssueKeys("project = TOS")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TOS-1", "TOS-2", "TOS-3", "TOS-4", "TOS-5"}, keys)
+	assert.Equal(t, 3, requests)
 
-	tests := []struct {
-		name     string
-		body     string
-		expected domain.QAComment
-	}{
-		{
-			name: "full comment with version and result",
-			body: "Tested on v1.2.3\nResult: Fixed\nComment: All tests passed",
-			expected: domain.QAComment{
-				SoftwareVersion: "v1.2.3",
-				TestResult:      "Fixed",
-				Comment:         "All tests passed",
-			},
-		},
-		{
-			name: "comment with alternative version format",
-			body: "Tested on SW v2.0.0\nResult: Not Fixed\nComment: Issue still exists",
-			expected: domain.QAComment{
-				SoftwareVersion: "v2.0.0",
-				TestResult:      "Not Fixed",
-				Comment:         "Issue still exists",
-			},
-		},
-		{
-			name: "could not test scenario",
-			body: "Could not test on SW v1.0.0\nResult: Could not test",
-			expected: domain.QAComment{
-				SoftwareVersion: "v1.0.0",
-				TestResult:      "Could not test",
-			},
-		},
-		{
-			name: "comment with notes instead of comment",
-			body: "Tested on v3.0.0\nResult: Fixed\nNotes: Additional validation required",
-			expected: domain.QAComment{
-				SoftwareVersion: "v3.0.0",
-				TestResult:      "Fixed",
-				Comment:         "Additional validation required",
-			},
-		},
-		{
-			name: "comment with passed/failed result",
-			body: "Tested on v1.1\nResult: Passed\nComment: Functionality works",
-			expected: domain.QAComment{
-				SoftwareVersion: "v1.1",
-				TestResult:      "Fixed", // Should normalize "Passed" to "Fixed"
-				Comment:         "Functionality works",
-			},
-		},
-		{
-			name:     "empty comment",
-			body:     "",
-			expected: domain.QAComment{},
-		},
-		{
-			name: "JIRA formatted comment",
-			body: "*Tested* on {{v2.0.0}}\n*Result:* Fixed\n*Comment:* ??All good??",
-			expected: domain.QAComment{
-				SoftwareVersion: "v2.0.0",
-				TestResult:      "Fixed",
-				Comment:         "All good",
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := jc.parseQAComment(tt.body, "2023-01-01T00:00:00.000+0000")
-			assert.NoError(t, err)
-			assert.Equal(t, tt.expected.SoftwareVersion, result.SoftwareVersion)
-			assert.Equal(t, tt.expected.TestResult, result.TestResult)
-			assert.Equal(t, tt.expected.Comment, result.Comment)
-		})
-	}
+	_, err = jc.SearchIssueKeys("")
+	assert.Error(t, err)
 }
 
-func TestRemoveJiraFormatting(t *testing.T) {
+func TestGetIssueWithCommentsSingleRequest(t *testing.T) {
 	t.Parallel()
 
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "remove bold formatting",
-			input:    "*bold text*",
-			expected: "bold text",
-		},
-		{
-			name:     "remove italic formatting",
-			input:    "_italic text_",
-			expected: "italic text",
-		},
-		{
-			name:     "remove monospace formatting",
-			input:    "??monospace text??",
-			expected: "monospace text",
-		},
-		{
-			name:     "remove color formatting",
-			input:    "{color:red}colored text{color}",
-			expected: "colored text",
-		},
-		{
-			name:     "remove link formatting",
-			input:    "[link text|http://example.com]",
-			expected: "link text",
-		},
-		{
-			name:     "complex formatting",
-			input:    "*Tested* on {{v2.0.0}} with _result_ ??Passed?? [link|http://test.com]",
-			expected: "Tested on v2.0.0 with result Passed link",
-		},
-		{
-			name:     "multiple formatting types",
-			input:    "{color:blue}*Important* _notice_{color}",
-			expected: "Important notice",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Create a JiraClient with default parsing configuration
-			parsingConfig := domain.ParsingConfig{
-				QAIndicators: []string{
-					"tested on",
-					"could not test on sw",
-					"qa comment",
-					"qa verification",
-					"qa tested",
-					"test.*result",
-					"test.*passed",
-					"test.*failed",
-					"test.*status",
-				},
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/rest/api/2/issue/TOS-1", r.URL.Path)
+		assert.Contains(t, r.URL.Query().Get("fields"), QAOwnerField)
+
+		_, _ = w.Write([]byte(`{
+			"key": "TOS-1",
+			"fields": {
+				"summary": "Modem reboot",
+				"assignee": {"emailAddress": "dev@example.com"},
+				"customfield_12601": {"emailAddress": "qa@example.com"},
+				"comment": {"comments": [
+					{"body": "Looks good to me", "created": "2025-07-01T10:00:00.000+0300", "author": {"emailAddress": "dev@example.com"}},
+					{"body": "Tested on v1.4.0\nResult: Fixed", "created": "2025-07-02T10:00:00.000+0300", "updated": "2025-07-02T11:30:00.000+0300", "author": {"emailAddress": "tester@example.com"}}
+				]}
 			}
-			jc := &JiraClient{parsingConfig: parsingConfig}
-			result := jc.removeJiraFormatting(tt.input)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := jira.NewClient(nil, server.URL)
+	assert.NoError(t, err)
+	jc := &JiraClient{client: client}
+
+	info, comments, err := jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, "Modem reboot", info.Summary)
+	assert.Equal(t, "dev@example.com", info.AssigneeEmail)
+	assert.Equal(t, "qa@example.com", info.QaOwnerEmail)
+	// Репозиторий возвращает все комментарии без разбора
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "Tested on v1.4.0\nResult: Fixed", comments[1].Body)
+	assert.True(t, time.Date(2025, 7, 2, 7, 0, 0, 0, time.UTC).Equal(comments[1].Created))
+	assert.True(t, time.Date(2025, 7, 2, 8, 30, 0, 0, time.UTC).Equal(comments[1].Updated))
+	assert.Equal(t, "tester@example.com", comments[1].AuthorEmail)
 }
 
-func TestRemoveJiraFormattingWithLinks(t *testing.T) {
+func TestGetIssueWithCommentsRespectsContext(t *testing.T) {
 	t.Parallel()
 
-	// Create a JiraClient with default parsing configuration
-	parsingConfig := domain.ParsingConfig{
-		QAIndicators: []string{
-			"tested on",
-			"could not test on sw",
-			"qa comment",
-			"qa verification",
-			"qa tested",
-			"test.*result",
-			"test.*passed",
-			"test.*failed",
-			"test.*status",
-		},
-	}
-	jc := &JiraClient{parsingConfig: parsingConfig}
-
-	// Test link removal specifically
-	input := "Tested on v1.0.0, see [results|https://jira.example.com/results] for details"
-	expected := "Tested on v1.0.0, see results for details"
-	result := jc.removeJiraFormatting(input)
-	assert.Equal(t, expected, result)
-
-	// Test link without URL part
-	input2 := "See [this link] for more info"
-	expected2 := "See this link for more info"
-	result2 := jc.removeJiraFormatting(input2)
-	assert.Equal(t, expected2, result2)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Имитируем зависший узел JIRA
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := jira.NewClient(nil, server.URL)
+	assert.NoError(t, err)
+	jc := &JiraClient{client: client}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err = jc.GetIssueWithCommentsWithContext(ctx, "TOS-1")
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
 }
 
-func TestParseQACommentResultNormalization(t *testing.T) {
+func TestParseTime(t *testing.T) {
 	t.Parallel()
 
-	// Create a JiraClient with default parsing configuration
-	parsingConfig := domain.ParsingConfig{
-		VersionPatterns: []string{
-			`(?i)Tested on (?:SW )?(v?[\d.]+(?:-[\w.]+)?)`,
-			`(?i)version.*?(v?[\d.]+(?:-[\w.]+)?)`,
-			`(?i)sw.*?(v?[\d.]+(?:-[\w.]+)?)`,
-		},
-		ResultPatterns: []string{
-			`(?i)Result:\s*([^\n\r]+)`,
-			`(?i)Status:\s*([^\n\r]+)`,
-			`(?i)(Fixed|Not Fixed|Partially Fixed|Could not test|Passed|Failed|Blocked|Resolved|Verified|Re-Test|Pending|In Progress|N/A)`,
-		},
-		CommentPatterns: []string{
-			`(?i)Comment:\s*(.+)`,
-			`(?i)Notes?:\s*(.+)`,
-			`(?i)Observations?:\s*(.+)`,
-		},
-		ResultNormalization: map[string]string{
-			"passed":         "Fixed",
-			"verified":       "Fixed",
-			"resolved":       "Fixed",
-			"re-test":        "Fixed",
-			"failed":         "Not Fixed",
-			"blocked":        "Not Fixed",
-			"pending":        "Not Fixed",
-			"in progress":    "Not Fixed",
-			"n/a":            "N/A",
-			"not applicable": "N/A",
-			"not fixed":      "Not Fixed",
-		},
+	want := time.Date(2025, 8, 12, 13, 35, 38, 0, time.UTC)
+	for _, value := range []string{
+		"2025-08-12T16:35:38.000+0300",
+		"2025-08-12T16:35:38+0300",
+		"2025-08-12T16:35:38+03:00",
+		"2025-08-12T13:35:38Z",
+	} {
+		got, err := parseTime(value)
+		assert.NoError(t, err, value)
+		assert.True(t, want.Equal(got), value)
 	}
-	jc := &JiraClient{parsingConfig: parsingConfig}
 
-	tests := []struct {
-		name     string
-		body     string
-		expected string
-	}{
-		{
-			name:     "normalize passed to fixed",
-			body:     "Tested on v1.0.0\nResult: Passed",
-			expected: "Fixed",
-		},
-		{
-			name:     "normalize failed to not fixed",
-			body:     "Tested on v1.0.0\nResult: Failed",
-			expected: "Not Fixed",
-		},
-		{
-			name:     "preserve could not test",
-			body:     "Could not test on SW v1.0",
-			expected: "Could not test",
-		},
-		{
-			name:     "infer result from keywords",
-			body:     "Tested on v1.0.0\nFixed after validation",
-			expected: "Fixed",
-		},
-		{
-			name:     "infer not fixed from keywords",
-			body:     "Tested on v1.0\nNot fixed yet",
-			expected: "Not Fixed",
-		},
-	}
+	got, err := parseTime("")
+	assert.NoError(t, err)
+	assert.True(t, got.IsZero())
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := jc.parseQAComment(tt.body, "2023-01-01T00:00:00.000+0000")
-			assert.NoError(t, err)
-			assert.Equal(t, tt.expected, result.TestResult)
-		})
-	}
+	_, err = parseTime("12.08.2025 16:35")
+	assert.EqualError(t, err, `unsupported date format "12.08.2025 16:35"`)
 }
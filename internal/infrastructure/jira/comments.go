@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// commentPageSize is the number of comments requested per page of the comment endpoint
+const commentPageSize = 100
+
+// commentPage mirrors the paginated response of GET /rest/api/2/issue/{key}/comment.
+// The same shape is embedded in the issue payload under fields.comment.
+type commentPage struct {
+	StartAt    int             `json:"startAt"`
+	MaxResults int             `json:"maxResults"`
+	Total      int             `json:"total"`
+	Comments   []*jira.Comment `json:"comments"`
+}
+
+// embeddedComments extracts the pagination metadata go-jira drops from fields.comment
+type embeddedComments struct {
+	Fields struct {
+		Comment *commentPage `json:"comment"`
+	} `json:"fields"`
+}
+
+// fetchAllComments pages through the comment endpoint until every comment has been read.
+// On failure it returns the comments read so far together with the error.
+func (jc *JiraClient) fetchAllComments(ctx context.Context, issueKey string) ([]*jira.Comment, error) {
+	var comments []*jira.Comment
+	startAt := 0
+	for {
+		endpoint := fmt.Sprintf("rest/api/%s/issue/%s/comment?startAt=%d&maxResults=%d",
+			jc.commentFormat.apiVersion(), url.PathEscape(issueKey), startAt, commentPageSize)
+		req, err := jc.client.NewRequestWithContext(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return comments, fmt.Errorf("failed to build comment request for %s: %w", issueKey, err)
+		}
+
+		var raw json.RawMessage
+		if _, err := jc.client.Do(req, &raw); err != nil {
+			return comments, fmt.Errorf("failed to get comments for %s (startAt=%d): %w", issueKey, startAt, err)
+		}
+
+		var page commentPage
+		raw, err = textCommentBodies(raw, "comments")
+		if err == nil {
+			err = json.Unmarshal(raw, &page)
+		}
+		if err != nil {
+			return comments, fmt.Errorf("failed to decode comments for %s (startAt=%d): %w", issueKey, startAt, err)
+		}
+
+		comments = append(comments, page.Comments...)
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			return comments, nil
+		}
+	}
+}
+
+// completeComments replaces the comments embedded in the issue payload with the full list
+// when JIRA has truncated them. It reports whether the list is still incomplete.
+func (jc *JiraClient) completeComments(ctx context.Context, issue *jira.Issue, raw json.RawMessage) bool {
+	var embedded embeddedComments
+	if err := json.Unmarshal(raw, &embedded); err != nil || embedded.Fields.Comment == nil {
+		return false
+	}
+
+	have := 0
+	if issue.Fields.Comments != nil {
+		have = len(issue.Fields.Comments.Comments)
+	}
+	if have >= embedded.Fields.Comment.Total {
+		return false
+	}
+
+	log.Printf("Issue %s embeds %d of %d comments, fetching the rest", issue.Key, have, embedded.Fields.Comment.Total)
+	comments, err := jc.fetchAllComments(ctx, issue.Key)
+	if len(comments) > have {
+		issue.Fields.Comments = &jira.Comments{Comments: comments}
+		have = len(comments)
+	}
+	if err != nil {
+		log.Printf("Warning: comment list for %s is incomplete: %v", issue.Key, err)
+		return true
+	}
+
+	return have < embedded.Fields.Comment.Total
+}
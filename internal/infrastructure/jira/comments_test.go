@@ -0,0 +1,102 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCommentServer serves an issue that embeds only the first two of total comments
+// and a comment endpoint returning two comments per page. Pages starting at failAt fail.
+func newCommentServer(t *testing.T, total, failAt int) *httptest.Server {
+	comment := func(i int) map[string]interface{} {
+		return map[string]interface{}{
+			"id":      strconv.Itoa(i + 1),
+			"body":    fmt.Sprintf("Tested on v1.0.%d\nResult: Fixed", i),
+			"created": "2025-07-01T10:00:00.000+0300",
+			"author":  map[string]string{"emailAddress": "qa@example.com"},
+		}
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/TOS-1":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"key": "TOS-1",
+				"fields": map[string]interface{}{
+					"summary": "Busy ticket",
+					"comment": map[string]interface{}{
+						"startAt":    0,
+						"maxResults": 2,
+						"total":      total,
+						"comments":   []interface{}{comment(0), comment(1)},
+					},
+				},
+			})
+		case "/rest/api/2/issue/TOS-1/comment":
+			startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+			if failAt >= 0 && startAt >= failAt {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			var comments []interface{}
+			for i := startAt; i < total && i < startAt+2; i++ {
+				comments = append(comments, comment(i))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"startAt":    startAt,
+				"maxResults": 2,
+				"total":      total,
+				"comments":   comments,
+			})
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *JiraClient {
+	client, err := jira.NewClient(nil, server.URL)
+	assert.NoError(t, err)
+	return &JiraClient{client: client}
+}
+
+func TestGetIssueWithCommentsPaginatesTruncatedComments(t *testing.T) {
+	t.Parallel()
+
+	server := newCommentServer(t, 5, -1)
+	defer server.Close()
+	jc := newTestClient(t, server)
+
+	info, comments, err := jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.False(t, info.CommentsIncomplete)
+	assert.Len(t, comments, 5)
+	assert.Equal(t, "5", comments[4].ID)
+	assert.Equal(t, "Tested on v1.0.4\nResult: Fixed", comments[4].Body)
+
+	all, err := jc.GetIssueComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Len(t, all, 5)
+}
+
+func TestGetIssueWithCommentsFlagsIncompleteComments(t *testing.T) {
+	t.Parallel()
+
+	server := newCommentServer(t, 5, 4)
+	defer server.Close()
+	jc := newTestClient(t, server)
+
+	info, comments, err := jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.True(t, info.CommentsIncomplete)
+	// Сохраняются все комментарии, прочитанные до ошибки
+	assert.Len(t, comments, 4)
+}
@@ -0,0 +1,439 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// rssTimeLayout is the date format of the XML RSS export, e.g. "Tue, 12 Aug 2025 16:35:38 +0300"
+const rssTimeLayout = time.RFC1123Z
+
+// DumpRepository реализует domain.CommentRepository поверх каталога с выгрузками JIRA:
+// XML RSS экспортом и JSON ответами REST API v2 или v3 (один тикет, массив тикетов или ответ /search).
+// Комментарии возвращаются в том же виде, что и из JiraClient.
+type DumpRepository struct {
+	dir    string
+	issues map[string]*loadedIssue
+}
+
+// NewDumpRepository reads every .xml and .json file under dir.
+// When an issue appears in several files, the file read last (in lexical path order) wins.
+func NewDumpRepository(dir string) (*DumpRepository, error) {
+	repo := &DumpRepository{
+		dir:    dir,
+		issues: make(map[string]*loadedIssue),
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".xml", ".json":
+			if !d.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump directory %s: %w", dir, err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		issues, err := loadDumpFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			key := strings.ToUpper(issue.Key)
+			if _, exists := repo.issues[key]; exists {
+				log.Printf("Issue %s is exported more than once, using %s", issue.Key, file)
+			}
+			repo.issues[key] = issue
+		}
+	}
+
+	if len(repo.issues) == 0 {
+		return nil, fmt.Errorf("no JIRA issues found in %s", dir)
+	}
+
+	log.Printf("Loaded %d issues from %d files in %s", len(repo.issues), len(files), dir)
+	return repo, nil
+}
+
+func (r *DumpRepository) GetIssueInfo(issueKey string) (*domain.IssueInfo, error) {
+	return r.GetIssueInfoWithContext(context.Background(), issueKey)
+}
+
+func (r *DumpRepository) GetIssueInfoWithContext(ctx context.Context, issueKey string) (*domain.IssueInfo, error) {
+	issue, err := r.issue(ctx, issueKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return issueInfo(issue.Issue), nil
+}
+
+func (r *DumpRepository) GetIssueWithComments(issueKey string) (*domain.IssueInfo, []domain.RawComment, error) {
+	return r.GetIssueWithCommentsWithContext(context.Background(), issueKey)
+}
+
+func (r *DumpRepository) GetIssueWithCommentsWithContext(ctx context.Context, issueKey string) (*domain.IssueInfo, []domain.RawComment, error) {
+	issue, err := r.issue(ctx, issueKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := issueInfo(issue.Issue)
+	info.CommentsIncomplete = issue.commentsIncomplete
+	return info, rawComments(issue.Issue), nil
+}
+
+func (r *DumpRepository) GetIssueComments(issueKey string) ([]domain.RawComment, error) {
+	return r.GetIssueCommentsWithContext(context.Background(), issueKey)
+}
+
+func (r *DumpRepository) GetIssueCommentsWithContext(ctx context.Context, issueKey string) ([]domain.RawComment, error) {
+	issue, err := r.issue(ctx, issueKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return rawComments(issue.Issue), nil
+}
+
+// SearchIssueKeys is not available offline: JQL can only be evaluated by a JIRA server
+func (r *DumpRepository) SearchIssueKeys(jql string) ([]string, error) {
+	return r.SearchIssueKeysWithContext(context.Background(), jql)
+}
+
+func (r *DumpRepository) SearchIssueKeysWithContext(ctx context.Context, jql string) ([]string, error) {
+	return nil, fmt.Errorf("JQL search is not supported for offline source %s; list the tickets explicitly", r.dir)
+}
+
+// issue находит тикет в выгрузке без учета регистра ключа
+func (r *DumpRepository) issue(ctx context.Context, issueKey string) (*loadedIssue, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if issueKey == "" {
+		return nil, fmt.Errorf("issue key cannot be empty")
+	}
+
+	issue, ok := r.issues[strings.ToUpper(issueKey)]
+	if !ok {
+		return nil, fmt.Errorf("issue %s not found in %s", issueKey, r.dir)
+	}
+	return issue, nil
+}
+
+// loadDumpFile разбирает один файл выгрузки в зависимости от расширения
+func loadDumpFile(path string) ([]*loadedIssue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump file %s: %w", path, err)
+	}
+
+	var issues []*loadedIssue
+	if strings.EqualFold(filepath.Ext(path), ".xml") {
+		issues, err = parseRSSExport(data)
+	} else {
+		issues, err = parseJSONExport(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dump file %s: %w", path, err)
+	}
+	return issues, nil
+}
+
+// parseJSONExport принимает ответ GET /issue/{key}, массив таких ответов или ответ /search
+func parseJSONExport(data []byte) ([]*loadedIssue, error) {
+	data = bytes.TrimSpace(data)
+
+	var rawIssues []json.RawMessage
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &rawIssues); err != nil {
+			return nil, err
+		}
+	} else {
+		var envelope struct {
+			Key    string            `json:"key"`
+			Issues []json.RawMessage `json:"issues"`
+		}
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return nil, err
+		}
+		switch {
+		case envelope.Issues != nil:
+			rawIssues = envelope.Issues
+		case envelope.Key != "":
+			rawIssues = []json.RawMessage{data}
+		default:
+			return nil, fmt.Errorf("neither an issue nor a search result")
+		}
+	}
+
+	issues := make([]*loadedIssue, 0, len(rawIssues))
+	for _, raw := range rawIssues {
+		// Выгрузки REST API v3 содержат тела комментариев в ADF
+		raw, err := textCommentBodies(raw, "fields", "comment", "comments")
+		if err != nil {
+			return nil, err
+		}
+
+		var dumped dumpIssue
+		if err := json.Unmarshal(raw, &dumped); err != nil {
+			return nil, err
+		}
+		if dumped.Key == "" || dumped.Fields == nil {
+			continue
+		}
+		issue := dumped.issue()
+		issues = append(issues, &loadedIssue{
+			Issue:              issue,
+			commentsIncomplete: commentsTruncated(issue, raw),
+		})
+	}
+	return issues, nil
+}
+
+// dumpIssue holds only the fields listed in issueFields. REST API v3 exports keep other
+// fields, e.g. description, in ADF, which jira.IssueFields cannot decode.
+type dumpIssue struct {
+	Key    string `json:"key"`
+	Fields *struct {
+		Summary  string         `json:"summary"`
+		Assignee *jira.User     `json:"assignee"`
+		Updated  jira.Time      `json:"updated"`
+		Comment  *jira.Comments `json:"comment"`
+		QAOwner  interface{}    `json:"customfield_12601"`
+	} `json:"fields"`
+}
+
+// issue собирает jira.Issue в том же виде, в каком его возвращает JiraClient
+func (d *dumpIssue) issue() *jira.Issue {
+	fields := &jira.IssueFields{
+		Summary:  d.Fields.Summary,
+		Assignee: d.Fields.Assignee,
+		Updated:  d.Fields.Updated,
+		Comments: d.Fields.Comment,
+		Unknowns: make(map[string]interface{}),
+	}
+	if d.Fields.QAOwner != nil {
+		fields.Unknowns[QAOwnerField] = d.Fields.QAOwner
+	}
+	return &jira.Issue{Key: d.Key, Fields: fields}
+}
+
+// commentsTruncated сообщает, что JIRA усекла встроенный список комментариев при выгрузке
+func commentsTruncated(issue *jira.Issue, raw json.RawMessage) bool {
+	var embedded embeddedComments
+	if err := json.Unmarshal(raw, &embedded); err != nil || embedded.Fields.Comment == nil {
+		return false
+	}
+
+	have := 0
+	if issue.Fields.Comments != nil {
+		have = len(issue.Fields.Comments.Comments)
+	}
+	return have < embedded.Fields.Comment.Total
+}
+
+// rssExport mirrors the parts of the JIRA XML RSS export used by the parser
+type rssExport struct {
+	Items []rssItem `xml:"channel>item"`
+}
+
+type rssItem struct {
+	Key          string           `xml:"key"`
+	Summary      string           `xml:"summary"`
+	Assignee     rssUser          `xml:"assignee"`
+	Comments     []rssComment     `xml:"comments>comment"`
+	CustomFields []rssCustomField `xml:"customfields>customfield"`
+}
+
+type rssUser struct {
+	Username  string `xml:"username,attr"`
+	AccountID string `xml:"accountid,attr"`
+	Name      string `xml:",chardata"`
+}
+
+type rssComment struct {
+	ID      string `xml:"id,attr"`
+	Author  string `xml:"author,attr"`
+	Created string `xml:"created,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type rssCustomField struct {
+	ID     string   `xml:"id,attr"`
+	Values []string `xml:"customfieldvalues>customfieldvalue"`
+}
+
+// parseRSSExport разбирает XML RSS экспорт. В нем нет email-адресов пользователей,
+// поэтому вместо них используются имена пользователей (или accountId в Cloud).
+func parseRSSExport(data []byte) ([]*loadedIssue, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// Экспорт JIRA содержит HTML-сущности и не всегда является строгим XML
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var export rssExport
+	if err := decoder.Decode(&export); err != nil {
+		return nil, err
+	}
+
+	issues := make([]*loadedIssue, 0, len(export.Items))
+	for _, item := range export.Items {
+		key := strings.TrimSpace(item.Key)
+		if key == "" {
+			continue
+		}
+
+		fields := &jira.IssueFields{
+			Summary:  strings.TrimSpace(item.Summary),
+			Unknowns: make(map[string]interface{}),
+			Comments: &jira.Comments{},
+		}
+
+		if assignee := item.Assignee.identity(); assignee != "" {
+			fields.Assignee = &jira.User{
+				Name:         item.Assignee.Username,
+				AccountID:    item.Assignee.AccountID,
+				DisplayName:  strings.TrimSpace(item.Assignee.Name),
+				EmailAddress: assignee,
+			}
+		}
+
+		for _, field := range item.CustomFields {
+			if field.ID == QAOwnerField && len(field.Values) > 0 {
+				fields.Unknowns[QAOwnerField] = map[string]interface{}{"emailAddress": strings.TrimSpace(field.Values[0])}
+			}
+		}
+
+		for _, comment := range item.Comments {
+			fields.Comments.Comments = append(fields.Comments.Comments, &jira.Comment{
+				ID:      comment.ID,
+				Body:    rssHTMLToText(comment.Body),
+				Created: rssTime(comment.Created),
+				Author:  jira.User{Name: comment.Author, EmailAddress: comment.Author},
+			})
+		}
+
+		issues = append(issues, &loadedIssue{Issue: &jira.Issue{Key: key, Fields: fields}})
+	}
+	return issues, nil
+}
+
+// identity возвращает имя пользователя, а для JIRA Cloud - accountId
+func (u rssUser) identity() string {
+	if u.Username != "" {
+		return u.Username
+	}
+	return u.AccountID
+}
+
+// rssTime переводит дату RSS экспорта в формат REST API, который ожидают команды вывода.
+// Нераспознанная дата возвращается без изменений.
+func rssTime(value string) string {
+	t, err := time.Parse(rssTimeLayout, strings.TrimSpace(value))
+	if err != nil {
+		return value
+	}
+	return t.Format(jiraTimeLayout)
+}
+
+var (
+	rssLineBreakRe = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6])>`)
+	rssTagRe       = regexp.MustCompile(`<[^>]*>`)
+	rssRowRe       = regexp.MustCompile(`(?is)<tr\b[^>]*>(.*?)</tr>`)
+	rssCellRe      = regexp.MustCompile(`(?is)<(t[dh])\b[^>]*>(.*?)</t[dh]>`)
+	rssListRe      = regexp.MustCompile(`(?i)<(/?)(ul|ol|li)\b[^>]*>`)
+)
+
+// rssHTMLToText превращает отрендеренный HTML комментария из RSS экспорта в текст,
+// сохраняя переносы строк, на которые опираются шаблоны разбора. Списки и таблицы
+// возвращаются к wiki-разметке (* пункт, # пункт, ||заголовок|| и |ячейка|), как в REST API.
+func rssHTMLToText(body string) string {
+	text := rssTables(body)
+	text = rssLists(text)
+	text = rssLineBreakRe.ReplaceAllString(text, "\n")
+	text = rssTagRe.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		line = strings.TrimSpace(strings.ReplaceAll(line, " ", " "))
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// rssTables заменяет строки таблиц строками wiki-разметки: ||a||b|| для строки заголовков, |a|b| для остальных
+func rssTables(body string) string {
+	return rssRowRe.ReplaceAllStringFunc(body, func(row string) string {
+		cells := rssCellRe.FindAllStringSubmatch(row, -1)
+		if len(cells) == 0 {
+			return "\n"
+		}
+		separator := "||"
+		values := make([]string, len(cells))
+		for i, cell := range cells {
+			if strings.ToLower(cell[1]) != "th" {
+				separator = "|"
+			}
+			values[i] = strings.Join(strings.Fields(rssTagRe.ReplaceAllString(cell[2], " ")), " ")
+		}
+		return "\n" + separator + strings.Join(values, separator) + separator + "\n"
+	})
+}
+
+// rssLists ставит перед пунктами списков маркеры wiki-разметки: * для ul, # для ol,
+// у вложенных списков маркеры повторяются (**, *#)
+func rssLists(body string) string {
+	var markers []string
+	return rssListRe.ReplaceAllStringFunc(body, func(tag string) string {
+		parts := rssListRe.FindStringSubmatch(tag)
+		closing, name := parts[1] == "/", strings.ToLower(parts[2])
+		switch {
+		case name == "li" && closing:
+			return "\n"
+		case name == "li":
+			if len(markers) == 0 {
+				return "\n* "
+			}
+			return "\n" + strings.Join(markers, "") + " "
+		case closing:
+			if len(markers) > 0 {
+				markers = markers[:len(markers)-1]
+			}
+		case name == "ol":
+			markers = append(markers, "#")
+		default:
+			markers = append(markers, "*")
+		}
+		return "\n"
+	})
+}
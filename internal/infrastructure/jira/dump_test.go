@@ -0,0 +1,234 @@
+package jira
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+const rssDump = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="0.92">
+<channel>
+  <title>Example JIRA</title>
+  <item>
+    <title>[TOS-10] Login fails</title>
+    <key id="10010">TOS-10</key>
+    <summary>Login fails</summary>
+    <assignee username="jdoe">John Doe</assignee>
+    <comments>
+      <comment id="501" author="qa.user" created="Tue, 12 Aug 2025 16:35:38 +0300">&lt;p&gt;Tested on SW v2.1.0&lt;br/&gt;
+Result: Passed&lt;br/&gt;
+Comment: login works &amp;amp; logout too&lt;/p&gt;</comment>
+      <comment id="502" author="dev" created="Wed, 13 Aug 2025 09:00:00 +0300">&lt;p&gt;Thanks!&lt;/p&gt;</comment>
+    </comments>
+    <customfields>
+      <customfield id="customfield_12601" key="com.atlassian.jira.plugin.system.customfieldtypes:userpicker">
+        <customfieldname>QA Owner</customfieldname>
+        <customfieldvalues><customfieldvalue>owner@example.com</customfieldvalue></customfieldvalues>
+      </customfield>
+    </customfields>
+  </item>
+</channel>
+</rss>`
+
+const searchDump = `{
+  "startAt": 0, "maxResults": 50, "total": 2,
+  "issues": [
+    {"key": "TOS-20", "fields": {"summary": "Crash on start", "comment": {"total": 1, "comments": [
+      {"id": "1", "body": "Tested on v3.0.0\nResult: Not Fixed", "created": "2025-08-01T10:00:00.000+0300",
+       "author": {"emailAddress": "qa@example.com"}}
+    ]}}},
+    {"key": "TOS-21", "fields": {"summary": "Truncated", "comment": {"total": 5, "comments": []}}}
+  ]
+}`
+
+const issueDump = `{"key": "TOS-30", "fields": {"summary": "Single issue", "assignee": {"emailAddress": "dev@example.com"}}}`
+
+// issueV3Dump - ответ REST API v3: описание и тела комментариев в ADF
+const issueV3Dump = `{"key": "TOS-40", "fields": {
+  "summary": "Cloud issue",
+  "description": {"type": "doc", "version": 1, "content": [
+    {"type": "paragraph", "content": [{"type": "text", "text": "Steps to reproduce"}]}
+  ]},
+  "updated": "2025-08-02T12:00:00.000+0300",
+  "customfield_12601": {"emailAddress": "owner@example.com"},
+  "comment": {"total": 1, "comments": [
+    {"id": "7", "created": "2025-08-02T11:00:00.000+0300", "author": {"emailAddress": "qa@example.com"},
+     "body": {"type": "doc", "version": 1, "content": [
+       {"type": "paragraph", "content": [{"type": "text", "text": "Tested on v4.0.0"}]},
+       {"type": "paragraph", "content": [{"type": "text", "text": "Result: Passed"}]}
+     ]}}
+  ]}
+}}`
+
+func writeDump(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestDumpRepository_RSSExport(t *testing.T) {
+	t.Parallel()
+
+	repo, err := NewDumpRepository(writeDump(t, map[string]string{"export.xml": rssDump}))
+	assert.NoError(t, err)
+
+	info, comments, err := repo.GetIssueWithComments("tos-10")
+	assert.NoError(t, err)
+	assert.Equal(t, "TOS-10", info.Key)
+	assert.Equal(t, "Login fails", info.Summary)
+	assert.Equal(t, "jdoe", info.AssigneeEmail)
+	assert.Equal(t, "owner@example.com", info.QaOwnerEmail)
+
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "501", comments[0].ID)
+	assert.Equal(t, "Tested on SW v2.1.0\nResult: Passed\nComment: login works & logout too", comments[0].Body)
+	assert.True(t, time.Date(2025, 8, 12, 13, 35, 38, 0, time.UTC).Equal(comments[0].Created))
+	assert.Equal(t, "qa.user", comments[0].AuthorEmail)
+	assert.Equal(t, "Thanks!", comments[1].Body)
+}
+
+// rssScenarioDump - комментарий со списком и таблицей в том виде, в каком JIRA рендерит wiki-разметку в RSS экспорте
+const rssScenarioDump = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="0.92">
+<channel>
+  <item>
+    <key id="10011">TOS-11</key>
+    <summary>Session handling</summary>
+    <comments>
+      <comment id="601" author="qa.user" created="Tue, 12 Aug 2025 16:35:38 +0300">&lt;p&gt;Tested on SW v2.2.0&lt;/p&gt;
+&lt;ul class=&quot;alternate&quot; type=&quot;square&quot;&gt;
+	&lt;li&gt;Login: Fixed&lt;/li&gt;
+	&lt;li&gt;Logout - Not Fixed (session is kept)
+	&lt;ol&gt;
+		&lt;li&gt;Remember me: Fixed&lt;/li&gt;
+	&lt;/ol&gt;
+	&lt;/li&gt;
+&lt;/ul&gt;
+&lt;div class=&#39;table-wrap&#39;&gt;
+&lt;table class=&#39;confluenceTable&#39;&gt;&lt;tbody&gt;
+&lt;tr&gt;
+&lt;th class=&#39;confluenceTh&#39;&gt;Scenario&lt;/th&gt;
+&lt;th class=&#39;confluenceTh&#39;&gt;Result&lt;/th&gt;
+&lt;/tr&gt;
+&lt;tr&gt;
+&lt;td class=&#39;confluenceTd&#39;&gt;Upload &lt;b&gt;large&lt;/b&gt; file&lt;/td&gt;
+&lt;td class=&#39;confluenceTd&#39;&gt;Could not test&lt;/td&gt;
+&lt;/tr&gt;
+&lt;/tbody&gt;&lt;/table&gt;
+&lt;/div&gt;
+&lt;p&gt;Result: Partially Fixed&lt;/p&gt;</comment>
+    </comments>
+  </item>
+</channel>
+</rss>`
+
+func TestDumpRepository_RSSScenarioResults(t *testing.T) {
+	t.Parallel()
+
+	repo, err := NewDumpRepository(writeDump(t, map[string]string{"export.xml": rssScenarioDump}))
+	assert.NoError(t, err)
+
+	_, comments, err := repo.GetIssueWithComments("TOS-11")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, `Tested on SW v2.2.0
+* Login: Fixed
+* Logout - Not Fixed (session is kept)
+*# Remember me: Fixed
+||Scenario||Result||
+|Upload large file|Could not test|
+Result: Partially Fixed`, comments[0].Body)
+
+	// Офлайн-разбор выделяет сценарии по тем же правилам, что и комментарии из REST API
+	regexParser, err := parser.NewRegexParser(domain.ParsingConfig{QAIndicators: []string{"tested on"}})
+	assert.NoError(t, err)
+	qaComment, ok := regexParser.ParseComment(comments[0])
+	assert.True(t, ok)
+	assert.Equal(t, []domain.TestCaseResult{
+		{Scenario: "Login", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+		{Scenario: "Logout", Result: domain.OutcomeNotFixed, Category: domain.CategoryFail, Note: "session is kept"},
+		{Scenario: "Remember me", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+		{Scenario: "Upload large file", Result: domain.OutcomeCouldNotTest, Category: domain.CategoryBlocked},
+	}, qaComment.Results)
+}
+
+func TestDumpRepository_JSONExports(t *testing.T) {
+	t.Parallel()
+
+	dir := writeDump(t, map[string]string{
+		"search.json":        searchDump,
+		"issues/TOS-30.json": issueDump,
+		"notes.txt":          "ignored",
+	})
+	repo, err := NewDumpRepository(dir)
+	assert.NoError(t, err)
+
+	rawComments, err := repo.GetIssueComments("TOS-20")
+	assert.NoError(t, err)
+	assert.Len(t, rawComments, 1)
+	assert.Equal(t, "Tested on v3.0.0\nResult: Not Fixed", rawComments[0].Body)
+	assert.Equal(t, "qa@example.com", rawComments[0].AuthorEmail)
+
+	info, comments, err := repo.GetIssueWithComments("TOS-21")
+	assert.NoError(t, err)
+	assert.True(t, info.CommentsIncomplete)
+	assert.Empty(t, comments)
+
+	info, err = repo.GetIssueInfo("TOS-30")
+	assert.NoError(t, err)
+	assert.Equal(t, "dev@example.com", info.AssigneeEmail)
+
+	_, err = repo.GetIssueComments("TOS-99")
+	assert.ErrorContains(t, err, "issue TOS-99 not found")
+
+	_, err = repo.SearchIssueKeys("project = TOS")
+	assert.ErrorContains(t, err, "not supported")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = repo.GetIssueCommentsWithContext(ctx, "TOS-20")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDumpRepository_JSONExportV3(t *testing.T) {
+	t.Parallel()
+
+	repo, err := NewDumpRepository(writeDump(t, map[string]string{"TOS-40.json": issueV3Dump}))
+	assert.NoError(t, err)
+
+	info, comments, err := repo.GetIssueWithComments("TOS-40")
+	assert.NoError(t, err)
+	assert.Equal(t, "Cloud issue", info.Summary)
+	assert.Equal(t, "owner@example.com", info.QaOwnerEmail)
+	assert.False(t, info.CommentsIncomplete)
+
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "Tested on v4.0.0\nResult: Passed", comments[0].Body)
+	assert.Equal(t, "qa@example.com", comments[0].AuthorEmail)
+}
+
+func TestNewDumpRepository_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewDumpRepository(writeDump(t, map[string]string{"readme.txt": "nothing"}))
+	assert.ErrorContains(t, err, "no JIRA issues found")
+
+	_, err = NewDumpRepository(writeDump(t, map[string]string{"broken.json": "{"}))
+	assert.ErrorContains(t, err, "broken.json")
+
+	_, err = NewDumpRepository(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
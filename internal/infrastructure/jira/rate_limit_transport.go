@@ -0,0 +1,65 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces requests evenly, at most one per interval
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// Wait blocks the caller until its slot comes up or ctx is cancelled
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimitTransport enforces the configured request rate on every HTTP request sent to JIRA:
+// searches, issue reads, comment pages and retried attempts alike
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func newRateLimitTransport(base http.RoundTripper, requestsPerSecond float64) *rateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &rateLimitTransport{base: base, limiter: newRateLimiter(requestsPerSecond)}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
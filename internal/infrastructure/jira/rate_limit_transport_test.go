@@ -0,0 +1,71 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRateLimiter(100) // один запрос каждые 10ms
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// Первый запрос проходит сразу, остальные четыре ждут своего слота
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	t.Parallel()
+
+	limiter := newRateLimiter(1) // один запрос в секунду
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+// countingTransport считает HTTP запросы, дошедшие до сети
+type countingTransport struct {
+	requests int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRateLimitTransportLimitsEveryRequest(t *testing.T) {
+	t.Parallel()
+
+	server := newCommentServer(t, 5, -1)
+	defer server.Close()
+
+	counter := &countingTransport{}
+	client, err := jira.NewClient(&http.Client{Transport: newRateLimitTransport(counter, 20)}, server.URL)
+	assert.NoError(t, err)
+	jc := &JiraClient{client: client}
+
+	start := time.Now()
+	_, comments, err := jc.GetIssueWithComments("TOS-1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 5)
+
+	// Один вызов репозитория - четыре HTTP запроса: тикет и три страницы комментариев.
+	// Каждый запрос ждет своего слота в 50ms, а не только первый.
+	assert.Equal(t, int32(4), atomic.LoadInt32(&counter.requests))
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
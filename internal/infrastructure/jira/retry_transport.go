@@ -0,0 +1,258 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how transient JIRA failures are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including the first one
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the first jittered backoff
+	InitialBackoff time.Duration
+	// MaxBackoff caps both the computed backoff and server-requested waits
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used when config.yaml does not override it
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// retryableStatuses are the responses treated as transient
+var retryableStatuses = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// retryTransport retries idempotent requests on transient failures with exponential
+// backoff and full jitter, honouring Retry-After and X-RateLimit-* headers.
+// It also pauses all requests while JIRA reports an exhausted rate limit.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+
+	// now and sleep are replaced in tests
+	now   func() time.Time
+	sleep func(req *http.Request, d time.Duration) error
+}
+
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &retryTransport{base: base, policy: policy, now: time.Now, sleep: sleepContext}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.waitForRateLimit(req); err != nil {
+		return nil, err
+	}
+
+	// Only idempotent reads are retried: replaying a POST could duplicate its side effects
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			t.observeRateLimit(resp)
+		}
+		return resp, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err == nil {
+			t.observeRateLimit(resp)
+		}
+
+		if req.Context().Err() != nil || attempt >= t.policy.MaxAttempts || !isRetryable(resp, err) {
+			return resp, err
+		}
+
+		delay, hinted := t.serverDelay(resp)
+		if hinted && delay > t.policy.MaxBackoff {
+			// The server asks for a longer pause than we are allowed to wait: give up now
+			log.Printf("JIRA asked to retry %s after %s, which exceeds the %s limit", req.URL.Path, delay, t.policy.MaxBackoff)
+			return resp, err
+		}
+		if !hinted {
+			delay = t.backoff(attempt)
+		}
+
+		if err != nil {
+			log.Printf("Request %s failed (attempt %d/%d): %v; retrying in %s", req.URL.Path, attempt, t.policy.MaxAttempts, err, delay)
+		} else {
+			log.Printf("Request %s returned %d (attempt %d/%d); retrying in %s", req.URL.Path, resp.StatusCode, attempt, t.policy.MaxAttempts, delay)
+			drainAndClose(resp)
+		}
+
+		if err := t.sleep(req, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return isTransientError(err)
+	}
+	return retryableStatuses[resp.StatusCode]
+}
+
+// isTransientError reports network timeouts and temporary errors such as a reset connection.
+// Configuration problems (DNS, TLS, connection refused) and cancellation fail immediately.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	// A connection reset by JIRA or a proxy in between is worth another attempt
+	if errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	// net.Error.Temporary is deprecated, but still reports EINTR, EMFILE and ENFILE
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
+
+// backoff returns a full-jitter exponential delay for the given attempt
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	ceiling := t.policy.InitialBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > t.policy.MaxBackoff {
+		ceiling = t.policy.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// serverDelay extracts the wait requested by JIRA, if any
+func (t *retryTransport) serverDelay(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now()); ok {
+		return d, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset")); ok {
+			return clampDelay(reset.Sub(t.now())), true
+		}
+	}
+	return 0, false
+}
+
+// observeRateLimit remembers an exhausted rate limit so later requests wait for the reset
+func (t *retryTransport) observeRateLimit(resp *http.Response) {
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	reset, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"))
+	if !ok {
+		return
+	}
+
+	// Never pause longer than the policy allows
+	if limit := t.now().Add(t.policy.MaxBackoff); reset.After(limit) {
+		reset = limit
+	}
+
+	t.mu.Lock()
+	if reset.After(t.pausedUntil) {
+		t.pausedUntil = reset
+	}
+	t.mu.Unlock()
+}
+
+func (t *retryTransport) waitForRateLimit(req *http.Request) error {
+	t.mu.Lock()
+	wait := t.pausedUntil.Sub(t.now())
+	t.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	log.Printf("JIRA rate limit exhausted, waiting %s before %s", wait, req.URL.Path)
+	return t.sleep(req, wait)
+}
+
+// parseRetryAfter understands both delta-seconds and HTTP-date values
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return clampDelay(time.Duration(seconds) * time.Second), true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return clampDelay(at.Sub(now)), true
+	}
+	return 0, false
+}
+
+// parseRateLimitReset understands ISO 8601 timestamps (Jira Cloud) and Unix seconds
+func parseRateLimitReset(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, true
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	return time.Time{}, false
+}
+
+func clampDelay(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// drainAndClose discards the body of a response that is about to be retried,
+// so the underlying connection can be reused
+func drainAndClose(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
+
+func sleepContext(req *http.Request, d time.Duration) error {
+	if d <= 0 {
+		return req.Context().Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
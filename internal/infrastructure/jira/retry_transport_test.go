@@ -0,0 +1,254 @@
+package jira
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRetryTransport records requested sleeps instead of waiting
+func newTestRetryTransport(policy RetryPolicy, sleeps *[]time.Duration) *retryTransport {
+	rt := newRetryTransport(http.DefaultTransport, policy)
+	rt.sleep = func(req *http.Request, d time.Duration) error {
+		*sleeps = append(*sleeps, d)
+		return req.Context().Err()
+	}
+	return rt
+}
+
+func TestRetryTransportRetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	var sleeps []time.Duration
+	rt := newTestRetryTransport(RetryPolicy{MaxAttempts: 4, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}, &sleeps)
+
+	resp, err := (&http.Client{Transport: rt}).Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// Full jitter: каждая пауза не превышает экспоненциально растущий потолок
+	assert.Len(t, sleeps, 2)
+	assert.LessOrEqual(t, sleeps[0], 100*time.Millisecond)
+	assert.LessOrEqual(t, sleeps[1], 200*time.Millisecond)
+}
+
+func TestRetryTransportHonoursRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var sleeps []time.Duration
+	rt := newTestRetryTransport(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Second}, &sleeps)
+
+	resp, err := (&http.Client{Transport: rt}).Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{3 * time.Second}, sleeps)
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("after max attempts", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		var sleeps []time.Duration
+		rt := newTestRetryTransport(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}, &sleeps)
+
+		resp, err := (&http.Client{Transport: rt}).Get(server.URL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("retry-after beyond max backoff", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		var sleeps []time.Duration
+		rt := newTestRetryTransport(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second}, &sleeps)
+
+		resp, err := (&http.Client{Transport: rt}).Get(server.URL)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		assert.Empty(t, sleeps)
+	})
+
+	t.Run("non-idempotent request", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		var sleeps []time.Duration
+		rt := newTestRetryTransport(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}, &sleeps)
+
+		resp, err := (&http.Client{Transport: rt}).Post(server.URL, "application/json", strings.NewReader("{}"))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
+
+// roundTripFunc позволяет подменить сетевой вызов в тестах
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// timeoutError - сетевой таймаут
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryTransportTransportErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		err   error
+		calls int32
+	}{
+		{"timeout", &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}, 3},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, 3},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, 1},
+		{"unknown host", &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "jira.invalid", IsNotFound: true}}, 1},
+		{"tls", x509.UnknownAuthorityError{}, 1},
+		{"cancelled", context.Canceled, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			var sleeps []time.Duration
+			rt := newTestRetryTransport(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}, &sleeps)
+			rt.base = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&calls, 1)
+				return nil, tt.err
+			})
+
+			req, err := http.NewRequest(http.MethodGet, "https://jira.example.com/rest/api/2/issue/TOS-1", nil)
+			assert.NoError(t, err)
+			_, err = rt.RoundTrip(req)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.calls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestRetryTransportStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	ctx, cancel := context.WithCancel(context.Background())
+	rt := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// Запрос отменяется во время сетевого таймаута
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}
+	}), RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: time.Second})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://jira.example.com/rest/api/2/issue/TOS-1", nil)
+	assert.NoError(t, err)
+	start := time.Now()
+	_, err = rt.RoundTrip(req)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestRetryTransportRateLimitHeaders(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", now.Add(5*time.Second).Format(time.RFC3339))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var sleeps []time.Duration
+	rt := newTestRetryTransport(RetryPolicy{MaxAttempts: 1, MaxBackoff: time.Minute}, &sleeps)
+	rt.now = func() time.Time { return now }
+	client := &http.Client{Transport: rt}
+
+	// Первый запрос исчерпывает лимит, второй ждет его сброса
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, sleeps)
+
+	resp, err = client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []time.Duration{5 * time.Second}, sleeps)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("7", now)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Second, d)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,366 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// RegexParser - парсер по умолчанию: распознает QA комментарии по индикаторам
+// и извлекает версию, результат и комментарий регулярными выражениями из ParsingConfig
+type RegexParser struct {
+	config   domain.ParsingConfig
+	outcomes *domain.OutcomeClassifier
+
+	// Скомпилированные version_patterns, result_patterns и comment_patterns
+	versionPatterns []*regexp.Regexp
+	resultPatterns  []*regexp.Regexp
+	commentPatterns []*regexp.Regexp
+}
+
+// NewRegexParser компилирует выражения из config; ошибка в любом из них возвращается сразу,
+// а не при разборе первого комментария
+func NewRegexParser(config domain.ParsingConfig) (*RegexParser, error) {
+	p := &RegexParser{config: config, outcomes: domain.NewOutcomeClassifier(config)}
+	var err error
+	if p.versionPatterns, err = compilePatterns("version_patterns", config.VersionPatterns); err != nil {
+		return nil, err
+	}
+	if p.resultPatterns, err = compilePatterns("result_patterns", config.ResultPatterns); err != nil {
+		return nil, err
+	}
+	if p.commentPatterns, err = compilePatterns("comment_patterns", config.CommentPatterns); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// compilePatterns компилирует выражения секции parsing; name - ключ секции для сообщения об ошибке
+func compilePatterns(name string, patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", name, pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// ParseComment разбирает комментарий; ok == false, если это не QA комментарий
+// или в нем нет значимых данных
+func (p *RegexParser) ParseComment(comment domain.RawComment) (domain.QAComment, bool) {
+	if !p.IsQAComment(comment.Body) {
+		return domain.QAComment{}, false
+	}
+
+	qaComment := p.parseQAComment(comment.Body, comment.Created)
+
+	// Добавляем email автора комментария и дату изменения
+	qaComment.AuthorEmail = comment.AuthorEmail
+	qaComment.Updated = comment.Updated
+
+	// Only keep the comment if it has meaningful data
+	if qaComment.SoftwareVersion == "" && qaComment.TestResult == "" && qaComment.Comment == "" && len(qaComment.Results) == 0 {
+		return domain.QAComment{}, false
+	}
+
+	return qaComment, true
+}
+
+// IsQAComment сообщает, содержит ли текст один из настроенных QA индикаторов
+func (p *RegexParser) IsQAComment(body string) bool {
+	normalized := p.removeJiraFormatting(body)
+	normalized = strings.ToLower(normalized)
+
+	for _, indicator := range p.config.QAIndicators {
+		if indicatorMatch(indicator, normalized) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// indicatorMatch проверяет индикатор на нормализованном тексте в нижнем регистре
+// и возвращает способ, которым он сработал, или пустую строку
+func indicatorMatch(indicator, normalized string) string {
+	// First, check for exact string matches
+	if strings.Contains(normalized, strings.ToLower(indicator)) {
+		return "substring"
+	}
+	if !strings.Contains(indicator, ".*") {
+		return ""
+	}
+
+	// Then, check if the indicator is a regex pattern
+	matched, err := regexp.MatchString("(?is)"+indicator, normalized)
+	if err == nil && matched {
+		return "regex"
+	}
+
+	// Additional check for specific regex patterns that might span multiple words.
+	// Replace spaces with .* to match patterns like "test.*result" against "test X result"
+	spaceAwarePattern := strings.ReplaceAll(indicator, " ", ".*")
+	matched, err = regexp.MatchString("(?is)"+spaceAwarePattern, normalized)
+	if err == nil && matched {
+		return "regex, spaces as .*"
+	}
+	return ""
+}
+
+// ExplainComment повторяет разбор ParseComment и записывает каждый шаг: сработавшие индикаторы,
+// выражения для версии, результата и комментария и примененную нормализацию
+func (p *RegexParser) ExplainComment(comment domain.RawComment) domain.CommentExplanation {
+	normalized := p.removeJiraFormatting(comment.Body)
+	explanation := domain.CommentExplanation{
+		CommentID:      comment.ID,
+		NormalizedText: normalized,
+	}
+
+	lower := strings.ToLower(normalized)
+	for _, indicator := range p.config.QAIndicators {
+		match := indicatorMatch(indicator, lower)
+		explanation.Indicators = append(explanation.Indicators, domain.IndicatorCheck{Indicator: indicator, Match: match})
+		if match != "" {
+			explanation.IsQAComment = true
+		}
+	}
+	if !explanation.IsQAComment {
+		return explanation
+	}
+
+	p.traceQAComment(comment.Body, comment.Created, &explanation)
+	if qaComment, ok := p.ParseComment(comment); ok {
+		explanation.Comment = &qaComment
+	}
+	return explanation
+}
+
+func (p *RegexParser) parseQAComment(body string, created time.Time) domain.QAComment {
+	return p.traceQAComment(body, created, nil)
+}
+
+// traceQAComment извлекает поля комментария; если trace не nil, в него записываются шаги разбора
+func (p *RegexParser) traceQAComment(body string, created time.Time, trace *domain.CommentExplanation) domain.QAComment {
+	var comment domain.QAComment
+	normalizedBody := p.removeJiraFormatting(body)
+	version := domain.FieldExplanation{Name: "version"}
+	result := domain.FieldExplanation{Name: "result"}
+	note := domain.FieldExplanation{Name: "comment"}
+
+	// Extract version with configurable patterns
+	version.Value, version.Attempts = firstSubmatch(p.versionPatterns, normalizedBody)
+	comment.SoftwareVersion = version.Value
+	if comment.SoftwareVersion != "" {
+		version.Source = "version_patterns"
+	}
+
+	// testResult - результат в том виде, в котором он найден; канонический вид он получает в конце
+	var testResult string
+
+	// Handle "could not test" case - extract version if possible
+	if strings.Contains(strings.ToLower(normalizedBody), "could not test on sw") {
+		// Extract version from "could not test on SW vX.X.X" pattern
+		couldNotTestVersionRe := regexp.MustCompile(`(?i)could not test on sw (v?\d+(?:\.\d+)*(?:-[\w.-]*\w)?(?:\+[\w.-]*\w)?)`)
+		if matches := couldNotTestVersionRe.FindStringSubmatch(normalizedBody); len(matches) > 1 {
+			comment.SoftwareVersion = matches[1]
+			version.Source = `"could not test on SW" phrase`
+		}
+		testResult = string(domain.OutcomeCouldNotTest)
+		result.Source = `"could not test on SW" phrase`
+	}
+
+	// Extract result with configurable patterns
+	if matched, attempts := firstSubmatch(p.resultPatterns, normalizedBody); matched != "" {
+		testResult = matched
+		result.Source = "result_patterns"
+		result.Attempts = attempts
+	} else {
+		result.Attempts = attempts
+	}
+
+	// Extract comment with configurable patterns
+	comment.Comment, note.Attempts = firstSubmatch(p.commentPatterns, normalizedBody)
+	if comment.Comment != "" {
+		note.Source = "comment_patterns"
+	}
+
+	// Set created date
+	comment.Created = created
+
+	// Результаты отдельных сценариев ищем в исходном тексте: разметка списков
+	// и таблиц теряется после removeJiraFormatting
+	comment.Results = p.extractResults(body)
+
+	// If we still don't have a result but found "could not test" somewhere, set it
+	if testResult == "" && strings.Contains(strings.ToLower(normalizedBody), "could not test") {
+		testResult = string(domain.OutcomeCouldNotTest)
+		result.Source = `"could not test" phrase`
+	}
+
+	// If we still don't have a result, try to infer from other common keywords
+	if testResult == "" {
+		lowerBody := strings.ToLower(normalizedBody)
+		for indicator := range p.config.ResultNormalization {
+			if strings.Contains(lowerBody, indicator) {
+				testResult = indicator
+				result.Source = fmt.Sprintf("keyword %q from result_normalization found in text", indicator)
+				break
+			}
+		}
+	}
+
+	// Additional fallback for common result indicators not covered by patterns
+	if testResult == "" {
+		lowerBody := strings.ToLower(normalizedBody)
+		for _, keyword := range fallbackResults {
+			if strings.Contains(lowerBody, keyword) {
+				testResult = keyword
+				result.Source = fmt.Sprintf("built-in keyword %q found in text", keyword)
+				break
+			}
+		}
+	}
+
+	// Final normalization: ResultNormalization and canonical spelling of the outcome
+	comment.TestResult = p.outcomes.Normalize(testResult)
+	comment.Category = p.outcomes.Category(comment.TestResult)
+	if comment.TestResult != "" && string(comment.TestResult) != testResult {
+		result.Normalization = testResult + " -> " + string(comment.TestResult)
+	}
+
+	// Нераспознанная версия остается только в SoftwareVersion и не участвует в сравнении версий
+	comment.Version, _ = domain.ParseVersion(comment.SoftwareVersion)
+	if !comment.Version.IsZero() && comment.Version.String() != comment.SoftwareVersion {
+		version.Normalization = comment.SoftwareVersion + " -> " + comment.Version.String()
+	}
+
+	if trace != nil {
+		version.Value = comment.SoftwareVersion
+		result.Value = string(comment.TestResult)
+		note.Value = comment.Comment
+		trace.Fields = []domain.FieldExplanation{version, result, note}
+	}
+
+	return comment
+}
+
+// fallbackResults - ключевые слова, по которым результат угадывается, если шаблоны не сработали.
+// Порядок важен: "not fixed" и "partially fixed" проверяются раньше "fixed".
+var fallbackResults = []string{
+	"not fixed",
+	"partially fixed",
+	"fixed",
+	"passed",
+	"failed",
+	"could not test",
+	"verified",
+	"resolved",
+	"blocked",
+	"pending",
+}
+
+// firstSubmatch возвращает первую группу первого сработавшего выражения и записи о всех
+// проверенных выражениях; выражения после сработавшего не проверяются
+func firstSubmatch(patterns []*regexp.Regexp, text string) (string, []domain.PatternAttempt) {
+	var attempts []domain.PatternAttempt
+	for _, re := range patterns {
+		pattern := re.String()
+		matches := re.FindStringSubmatch(text)
+		switch {
+		case matches == nil:
+			attempts = append(attempts, domain.PatternAttempt{Pattern: pattern, Failure: "no match"})
+		case len(matches) < 2:
+			attempts = append(attempts, domain.PatternAttempt{Pattern: pattern, Failure: "matched, but the pattern has no capture group"})
+		default:
+			value := strings.TrimSpace(matches[1])
+			attempts = append(attempts, domain.PatternAttempt{Pattern: pattern, Value: value})
+			return value, attempts
+		}
+	}
+	return "", attempts
+}
+
+// removeMarkupDelimiters удаляет "-" (зачеркивание) и "_" (курсив) на границах слов. Внутри слова
+// они остаются, поэтому версии 5.4.0-hotfix.2 и v5.4.0-rc1 и результат Re-Test не теряют дефис.
+func removeMarkupDelimiters(text string) string {
+	runes := []rune(text)
+	isWord := func(i int) bool {
+		return i >= 0 && i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]))
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for i, r := range runes {
+		if (r == '-' || r == '_') && !(isWord(i-1) && isWord(i+1)) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// removeJiraFormatting удаляет JIRA-разметку из текста
+func (p *RegexParser) removeJiraFormatting(text string) string {
+	// Удаляем базовое форматирование JIRA
+	replacements := map[string]string{
+		"*":               "", // жирный
+		"??":              "", // моноширинный
+		"{{":              "",
+		"}}":              "",
+		"{*}":             "",
+		"{code}":          "",
+		"{code:":          "",
+		"{noformat}":      "",
+		"{quote}":         "",
+		"{panel}":         "",
+		"{panel:bgcolor=": "",
+		"{color:":         "",
+		"{color}":         "",
+	}
+
+	// Удаляем цветовую разметку {color}
+	colorRe := regexp.MustCompile(`\{color[^\}]*\}(.*?)\{color\}`)
+	text = colorRe.ReplaceAllString(text, "$1")
+
+	// Удаляем код-блоки {code}...{code}
+	codeRe := regexp.MustCompile(`\{code[^\}]*\}(.*?)\{code\}`)
+	text = codeRe.ReplaceAllString(text, "$1")
+
+	// Удаляем панели {panel}...{panel}
+	panelRe := regexp.MustCompile(`\{panel[^\}]*\}(.*?)\{panel\}`)
+	text = panelRe.ReplaceAllString(text, "$1")
+
+	// Удаляем другие элементы форматирования
+	for old, new := range replacements {
+		text = strings.ReplaceAll(text, old, new)
+	}
+	text = removeMarkupDelimiters(text)
+
+	// Удаляем ссылки [текст|url]
+	linkRe := regexp.MustCompile(`\[([^\|\]]+)(?:\|[^\]]+)?\]`)
+	text = linkRe.ReplaceAllString(text, "$1")
+
+	// Удаляем встроенные изображения !image.png!
+	imageRe := regexp.MustCompile(`!\S*!`)
+	text = imageRe.ReplaceAllString(text, "")
+
+	// Удаляем упоминания пользователей [~username]
+	mentionRe := regexp.MustCompile(`\[~[^\]]+\]`)
+	text = mentionRe.ReplaceAllString(text, "")
+
+	// Удаляем метки {noformat}...{noformat}
+	noformatRe := regexp.MustCompile(`\{noformat\}(.*?)\{noformat\}`)
+	text = noformatRe.ReplaceAllString(text, "$1")
+
+	// Удаляем цитаты {quote}...{quote}
+	quoteRe := regexp.MustCompile(`\{quote\}(.*?)\{quote\}`)
+	text = quoteRe.ReplaceAllString(text, "$1")
+
+	return strings.TrimSpace(text)
+}
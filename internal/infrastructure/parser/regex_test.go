@@ -0,0 +1,535 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRegexParser создает парсер из правил, которые должны компилироваться без ошибок
+// mustVersion разбирает заведомо корректную версию из тестовых данных
+func mustVersion(t *testing.T, value string) domain.Version {
+	t.Helper()
+
+	v, err := domain.ParseVersion(value)
+	assert.NoError(t, err)
+	return v
+}
+
+func newRegexParser(t *testing.T, config domain.ParsingConfig) *RegexParser {
+	t.Helper()
+	p, err := NewRegexParser(config)
+	assert.NoError(t, err)
+	return p
+}
+
+func TestIsQAComment(t *testing.T) {
+	t.Parallel()
+
+	// Create a parser with default parsing configuration
+	parsingConfig := domain.ParsingConfig{
+		QAIndicators: []string{
+			"tested on",
+			"could not test on sw",
+			"qa comment",
+			"qa verification",
+			"qa tested",
+			"test.*result",
+			"test.*passed",
+			"test.*failed",
+			"test.*status",
+		},
+	}
+	p := newRegexParser(t, parsingConfig)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected bool
+	}{
+		{
+			name:     "contains Tested on",
+			body:     "Tested on v1.2.3\nResult: Fixed",
+			expected: true,
+		},
+		{
+			name:     "contains Could not test on SW",
+			body:     "Could not test on SW v1.2.3 due to environment issues",
+			expected: true,
+		},
+		{
+			name:     "contains QA Comment",
+			body:     "QA Comment: Tested functionality\nResult: Passed",
+			expected: true,
+		},
+		{
+			name:     "contains Test and Result",
+			body:     "Test scenario: Login\nResult: Success",
+			expected: true,
+		},
+		{
+			name:     "regular comment",
+			body:     "This is a regular comment without QA keywords",
+			expected: false,
+		},
+		{
+			name:     "JIRA formatted text",
+			body:     "*Tested* on _v2.0.0_ ??Result:?? *Fixed*",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := p.IsQAComment(tt.body)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParseQAComment(t *testing.T) {
+	t.Parallel()
+
+	// Create a parser with default parsing configuration
+	parsingConfig := domain.ParsingConfig{
+		VersionPatterns: []string{
+			`(?i)Tested on (?:SW )?(v?[\d.]+(?:-[\w.]+)?)`,
+			`(?i)version.*?(v?[\d.]+(?:-[\w.]+)?)`,
+			`(?i)sw.*?(v?[\d.]+(?:-[\w.]+)?)`,
+		},
+		ResultPatterns: []string{
+			`(?i)Result:\s*([^\n\r]+)`,
+			`(?i)Status:\s*([^\n\r]+)`,
+			`(?i)(Fixed|Not Fixed|Partially Fixed|Could not test|Passed|Failed|Blocked|Resolved|Verified|Re-Test|Pending|In Progress|N/A)`,
+		},
+		CommentPatterns: []string{
+			`(?i)Comment:\s*(.+)`,
+			`(?i)Notes?:\s*(.+)`,
+			`(?i)Observations?:\s*(.+)`,
+		},
+		ResultNormalization: map[string]string{
+			"passed":         "Fixed",
+			"verified":       "Fixed",
+			"resolved":       "Fixed",
+			"re-test":        "Fixed",
+			"failed":         "Not Fixed",
+			"blocked":        "Not Fixed",
+			"pending":        "Not Fixed",
+			"in progress":    "Not Fixed",
+			"n/a":            "N/A",
+			"not applicable": "N/A",
+		},
+	}
+	p := newRegexParser(t, parsingConfig)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected domain.QAComment
+	}{
+		{
+			name: "full comment with version and result",
+			body: "Tested on v1.2.3\nResult: Fixed\nComment: All tests passed",
+			expected: domain.QAComment{
+				SoftwareVersion: "v1.2.3",
+				TestResult:      "Fixed",
+				Comment:         "All tests passed",
+			},
+		},
+		{
+			name: "comment with alternative version format",
+			body: "Tested on SW v2.0.0\nResult: Not Fixed\nComment: Issue still exists",
+			expected: domain.QAComment{
+				SoftwareVersion: "v2.0.0",
+				TestResult:      "Not Fixed",
+				Comment:         "Issue still exists",
+			},
+		},
+		{
+			name: "could not test scenario",
+			body: "Could not test on SW v1.0.0\nResult: Could not test",
+			expected: domain.QAComment{
+				SoftwareVersion: "v1.0.0",
+				TestResult:      "Could not test",
+			},
+		},
+		{
+			name: "comment with notes instead of comment",
+			body: "Tested on v3.0.0\nResult: Fixed\nNotes: Additional validation required",
+			expected: domain.QAComment{
+				SoftwareVersion: "v3.0.0",
+				TestResult:      "Fixed",
+				Comment:         "Additional validation required",
+			},
+		},
+		{
+			name: "comment with passed/failed result",
+			body: "Tested on v1.1\nResult: Passed\nComment: Functionality works",
+			expected: domain.QAComment{
+				SoftwareVersion: "v1.1",
+				TestResult:      "Fixed", // Should normalize "Passed" to "Fixed"
+				Comment:         "Functionality works",
+			},
+		},
+		{
+			name:     "empty comment",
+			body:     "",
+			expected: domain.QAComment{},
+		},
+		{
+			name: "JIRA formatted comment",
+			body: "*Tested* on {{v2.0.0}}\n*Result:* Fixed\n*Comment:* ??All good??",
+			expected: domain.QAComment{
+				SoftwareVersion: "v2.0.0",
+				TestResult:      "Fixed",
+				Comment:         "All good",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := p.parseQAComment(tt.body, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+			assert.Equal(t, tt.expected.SoftwareVersion, result.SoftwareVersion)
+			assert.Equal(t, tt.expected.TestResult, result.TestResult)
+			assert.Equal(t, tt.expected.Comment, result.Comment)
+		})
+	}
+}
+
+func TestParseCommentVersions(t *testing.T) {
+	t.Parallel()
+
+	p := newRegexParser(t, config.DefaultParsingConfig())
+
+	tests := []struct {
+		body     string
+		expected string
+	}{
+		{body: "Tested on 5.4.0-hotfix.2\nResult: Fixed", expected: "5.4.0-hotfix.2"},
+		{body: "*Tested on SW* v5.4.0-rc1. Result: Not Fixed", expected: "v5.4.0-rc1"},
+		{body: "Tested on 5.4.1+build.17, Result: Fixed", expected: "5.4.1+build.17"},
+		{body: "Tested on _5.4.0-hotfix-3_\nResult: Fixed", expected: "5.4.0-hotfix-3"},
+		{body: "Tested on -5.3.0- 5.4.0-rc2+build.5\nResult: Fixed", expected: "5.3.0"},
+		{body: "Could not test on SW 5.4.0-rc2: the device is missing", expected: "5.4.0-rc2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			comment, ok := p.ParseComment(domain.RawComment{Body: tt.body})
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, comment.SoftwareVersion)
+			assert.Equal(t, mustVersion(t, tt.expected), comment.Version)
+		})
+	}
+
+	// Pre-release идет раньше релиза, hotfix - после него
+	var comments []domain.QAComment
+	for _, body := range []string{"Tested on 5.4.0-hotfix.2. Result: Fixed", "Tested on v5.4.0-rc1. Result: Fixed", "Tested on 5.4.0. Result: Fixed"} {
+		comment, ok := p.ParseComment(domain.RawComment{Body: body})
+		assert.True(t, ok)
+		comments = append(comments, comment)
+	}
+	assert.Equal(t, "5.4.0-hotfix.2", domain.LatestVersion(comments).String())
+	assert.Equal(t, -1, comments[1].Version.Compare(comments[2].Version))
+}
+
+func TestRemoveJiraFormatting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "remove bold formatting",
+			input:    "*bold text*",
+			expected: "bold text",
+		},
+		{
+			name:     "remove italic formatting",
+			input:    "_italic text_",
+			expected: "italic text",
+		},
+		{
+			name:     "remove monospace formatting",
+			input:    "??monospace text??",
+			expected: "monospace text",
+		},
+		{
+			name:     "remove color formatting",
+			input:    "{color:red}colored text{color}",
+			expected: "colored text",
+		},
+		{
+			name:     "remove link formatting",
+			input:    "[link text|http://example.com]",
+			expected: "link text",
+		},
+		{
+			name:     "complex formatting",
+			input:    "*Tested* on {{v2.0.0}} with _result_ ??Passed?? [link|http://test.com]",
+			expected: "Tested on v2.0.0 with result Passed link",
+		},
+		{
+			name:     "multiple formatting types",
+			input:    "{color:blue}*Important* _notice_{color}",
+			expected: "Important notice",
+		},
+		{
+			name:     "strikethrough and hyphens inside words",
+			input:    "-Tested on 5.3.0- Tested on _5.4.0-hotfix.2_ - Re-Test",
+			expected: "Tested on 5.3.0 Tested on 5.4.0-hotfix.2  Re-Test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create a parser with default parsing configuration
+			parsingConfig := domain.ParsingConfig{
+				QAIndicators: []string{
+					"tested on",
+					"could not test on sw",
+					"qa comment",
+					"qa verification",
+					"qa tested",
+					"test.*result",
+					"test.*passed",
+					"test.*failed",
+					"test.*status",
+				},
+			}
+			p := newRegexParser(t, parsingConfig)
+			result := p.removeJiraFormatting(tt.input)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestRemoveJiraFormattingWithLinks(t *testing.T) {
+	t.Parallel()
+
+	// Create a parser with default parsing configuration
+	parsingConfig := domain.ParsingConfig{
+		QAIndicators: []string{
+			"tested on",
+			"could not test on sw",
+			"qa comment",
+			"qa verification",
+			"qa tested",
+			"test.*result",
+			"test.*passed",
+			"test.*failed",
+			"test.*status",
+		},
+	}
+	p := newRegexParser(t, parsingConfig)
+
+	// Test link removal specifically
+	input := "Tested on v1.0.0, see [results|https://jira.example.com/results] for details"
+	expected := "Tested on v1.0.0, see results for details"
+	result := p.removeJiraFormatting(input)
+	assert.Equal(t, expected, result)
+
+	// Test link without URL part
+	input2 := "See [this link] for more info"
+	expected2 := "See this link for more info"
+	result2 := p.removeJiraFormatting(input2)
+	assert.Equal(t, expected2, result2)
+}
+
+func TestParseQACommentResultNormalization(t *testing.T) {
+	t.Parallel()
+
+	// Create a parser with default parsing configuration
+	parsingConfig := domain.ParsingConfig{
+		VersionPatterns: []string{
+			`(?i)Tested on (?:SW )?(v?[\d.]+(?:-[\w.]+)?)`,
+			`(?i)version.*?(v?[\d.]+(?:-[\w.]+)?)`,
+			`(?i)sw.*?(v?[\d.]+(?:-[\w.]+)?)`,
+		},
+		ResultPatterns: []string{
+			`(?i)Result:\s*([^\n\r]+)`,
+			`(?i)Status:\s*([^\n\r]+)`,
+			`(?i)(Fixed|Not Fixed|Partially Fixed|Could not test|Passed|Failed|Blocked|Resolved|Verified|Re-Test|Pending|In Progress|N/A)`,
+		},
+		CommentPatterns: []string{
+			`(?i)Comment:\s*(.+)`,
+			`(?i)Notes?:\s*(.+)`,
+			`(?i)Observations?:\s*(.+)`,
+		},
+		ResultNormalization: map[string]string{
+			"passed":         "Fixed",
+			"verified":       "Fixed",
+			"resolved":       "Fixed",
+			"re-test":        "Fixed",
+			"failed":         "Not Fixed",
+			"blocked":        "Not Fixed",
+			"pending":        "Not Fixed",
+			"in progress":    "Not Fixed",
+			"n/a":            "N/A",
+			"not applicable": "N/A",
+			"not fixed":      "Not Fixed",
+		},
+	}
+	p := newRegexParser(t, parsingConfig)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected domain.TestOutcome
+	}{
+		{
+			name:     "normalize passed to fixed",
+			body:     "Tested on v1.0.0\nResult: Passed",
+			expected: "Fixed",
+		},
+		{
+			name:     "normalize failed to not fixed",
+			body:     "Tested on v1.0.0\nResult: Failed",
+			expected: "Not Fixed",
+		},
+		{
+			name:     "preserve could not test",
+			body:     "Could not test on SW v1.0",
+			expected: "Could not test",
+		},
+		{
+			name:     "infer result from keywords",
+			body:     "Tested on v1.0.0\nFixed after validation",
+			expected: "Fixed",
+		},
+		{
+			name:     "infer not fixed from keywords",
+			body:     "Tested on v1.0\nNot fixed yet",
+			expected: "Not Fixed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := p.parseQAComment(tt.body, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+			assert.Equal(t, tt.expected, result.TestResult)
+		})
+	}
+}
+
+func TestRegexParser_ParseComment(t *testing.T) {
+	t.Parallel()
+
+	p := newRegexParser(t, domain.ParsingConfig{
+		QAIndicators:    []string{"tested on"},
+		VersionPatterns: []string{`(?i)Tested on (?:SW )?(v?[\d.]+(?:-[\w.]+)?)`},
+		ResultPatterns:  []string{`(?i)Result:\s*([^\n\r]+)`},
+	})
+
+	created := time.Date(2025, 8, 12, 16, 35, 38, 514000000, time.FixedZone("", 3*60*60))
+	updated := created.Add(time.Hour)
+	comment, ok := p.ParseComment(domain.RawComment{
+		ID:          "10",
+		Body:        "Tested on SW v1.2.3\nResult: Fixed",
+		Created:     created,
+		Updated:     updated,
+		AuthorEmail: "qa@example.com",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, domain.QAComment{
+		SoftwareVersion: "v1.2.3",
+		Version:         mustVersion(t, "1.2.3"),
+		TestResult:      domain.OutcomeFixed,
+		Category:        domain.CategoryPass,
+		Created:         created,
+		Updated:         updated,
+		AuthorEmail:     "qa@example.com",
+	}, comment)
+
+	_, ok = p.ParseComment(domain.RawComment{Body: "Looks good to me"})
+	assert.False(t, ok)
+
+	// QA индикатор без версии, результата и комментария - не QA комментарий
+	_, ok = p.ParseComment(domain.RawComment{Body: "Will be tested on Monday"})
+	assert.False(t, ok)
+}
+
+func TestRegexParser_ExplainComment(t *testing.T) {
+	t.Parallel()
+
+	p := newRegexParser(t, domain.ParsingConfig{
+		QAIndicators:    []string{"qa comment", "tested on", "test.*result"},
+		VersionPatterns: []string{`(?i)build (\d+)`, `(?i)Tested on (?:SW )?(v?[\d.]+)`},
+		ResultPatterns:  []string{`(?i)Status:\s*([^\n\r]+)`, `(?i)Result:\s*([^\n\r]+)`},
+		CommentPatterns: []string{`(?i)Comment:`},
+		ResultNormalization: map[string]string{
+			"passed": "Fixed",
+		},
+	})
+
+	explanation := p.ExplainComment(domain.RawComment{
+		ID:   "10042",
+		Body: "*Tested on* SW v1.2.3\nResult: {color:green}Passed{color}\nComment: ok",
+	})
+
+	assert.Equal(t, "10042", explanation.CommentID)
+	assert.Equal(t, "Tested on SW v1.2.3\nResult: Passed\nComment: ok", explanation.NormalizedText)
+	assert.True(t, explanation.IsQAComment)
+	assert.Equal(t, []domain.IndicatorCheck{
+		{Indicator: "qa comment"},
+		{Indicator: "tested on", Match: "substring"},
+		{Indicator: "test.*result", Match: "regex"},
+	}, explanation.Indicators)
+
+	if assert.Len(t, explanation.Fields, 3) {
+		version, result, comment := explanation.Fields[0], explanation.Fields[1], explanation.Fields[2]
+
+		assert.Equal(t, "v1.2.3", version.Value)
+		assert.Equal(t, "version_patterns", version.Source)
+		assert.Equal(t, "v1.2.3 -> 1.2.3", version.Normalization)
+		assert.Equal(t, []domain.PatternAttempt{
+			{Pattern: `(?i)build (\d+)`, Failure: "no match"},
+			{Pattern: `(?i)Tested on (?:SW )?(v?[\d.]+)`, Value: "v1.2.3"},
+		}, version.Attempts)
+
+		assert.Equal(t, "Fixed", result.Value)
+		assert.Equal(t, "result_patterns", result.Source)
+		assert.Equal(t, "Passed -> Fixed", result.Normalization)
+		assert.Len(t, result.Attempts, 2)
+
+		assert.Empty(t, comment.Value)
+		assert.Equal(t, []domain.PatternAttempt{
+			{Pattern: `(?i)Comment:`, Failure: "matched, but the pattern has no capture group"},
+		}, comment.Attempts)
+	}
+
+	if assert.NotNil(t, explanation.Comment) {
+		assert.Equal(t, domain.OutcomeFixed, explanation.Comment.TestResult)
+		assert.Equal(t, domain.CategoryPass, explanation.Comment.Category)
+	}
+
+	// Результат, угаданный по ключевому слову, объясняется эвристикой
+	explanation = p.ExplainComment(domain.RawComment{Body: "Tested on v2.0, pending deploy"})
+	assert.Equal(t, `built-in keyword "pending" found in text`, explanation.Fields[1].Source)
+
+	explanation = p.ExplainComment(domain.RawComment{Body: "Looks good to me"})
+	assert.False(t, explanation.IsQAComment)
+	assert.Empty(t, explanation.Fields)
+	assert.Nil(t, explanation.Comment)
+}
+
+func TestNewRegexParserInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewRegexParser(domain.ParsingConfig{
+		VersionPatterns: []string{`(?i)Tested on (v?[\d.]+)`},
+		ResultPatterns:  []string{`(?i)Result:\s*(\S+`},
+	})
+	assert.ErrorContains(t, err, "invalid result_patterns entry")
+	assert.ErrorContains(t, err, "missing closing )")
+
+	// Ошибка выражения возвращается и при создании по имени из конфигурации
+	_, err = New(TemplateParserName, domain.ParsingConfig{CommentPatterns: []string{`[`}})
+	assert.ErrorContains(t, err, "invalid comment_patterns entry")
+}
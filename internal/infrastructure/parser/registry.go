@@ -0,0 +1,51 @@
+// Package parser содержит реализации domain.CommentParser и их выбор по имени из конфигурации
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// DefaultParser - имя парсера, используемого, если в конфигурации парсер не указан
+const DefaultParser = "regex"
+
+// TemplateParserName - имя строгого парсера по шаблону; его же использует режим --strict
+const TemplateParserName = "template"
+
+// factory создает парсер из правил разбора
+type factory func(config domain.ParsingConfig) (domain.CommentParser, error)
+
+var factories = map[string]factory{
+	"regex": func(config domain.ParsingConfig) (domain.CommentParser, error) {
+		return NewRegexParser(config)
+	},
+	TemplateParserName: func(config domain.ParsingConfig) (domain.CommentParser, error) {
+		return NewTemplateParser(config)
+	},
+}
+
+// New создает парсер по имени; пустое имя означает DefaultParser
+func New(name string, config domain.ParsingConfig) (domain.CommentParser, error) {
+	if name == "" {
+		name = DefaultParser
+	}
+
+	create, ok := factories[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown comment parser %q, available: %s", name, strings.Join(Names(), ", "))
+	}
+	return create(config)
+}
+
+// Names возвращает имена всех доступных парсеров
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	p, err := New("", domain.ParsingConfig{})
+	assert.NoError(t, err)
+	assert.IsType(t, &RegexParser{}, p)
+
+	p, err = New("Regex", domain.ParsingConfig{})
+	assert.NoError(t, err)
+	assert.IsType(t, &RegexParser{}, p)
+
+	p, err = New("template", domain.ParsingConfig{})
+	assert.NoError(t, err)
+	assert.IsType(t, &TemplateParser{}, p)
+
+	_, err = New("template", domain.ParsingConfig{Template: &domain.CommentTemplate{
+		Fields: []domain.TemplateField{{Name: "build", Labels: []string{"Build"}}},
+	}})
+	assert.ErrorContains(t, err, `unknown field "build"`)
+
+	_, err = New("neural", domain.ParsingConfig{})
+	assert.ErrorContains(t, err, `unknown comment parser "neural"`)
+	assert.ErrorContains(t, err, "regex")
+}
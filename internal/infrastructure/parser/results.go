@@ -0,0 +1,263 @@
+package parser
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// fieldLabels - подписи полей самого комментария, которые не являются сценариями
+var fieldLabels = map[string]bool{
+	"result": true, "status": true, "version": true, "tested on": true, "sw": true,
+	"comment": true, "note": true, "notes": true, "observation": true, "observations": true,
+}
+
+var (
+	// bulletRe распознает пункты списков wiki-разметки (*, #, -) и текста из ADF
+	bulletRe = regexp.MustCompile(`^(?:[*#-]+|•)\s+(.+)$`)
+	// itemRe делит пункт на сценарий и результат: "Login: Fixed", "Login - Fixed", "Login => Fixed"
+	itemRe = regexp.MustCompile(`^(.+?)\s*(?::|\s[-–—]\s|=>|->)\s*(.+)$`)
+	// wikiLinkRe заменяет ссылки [текст|url] до разбиения строк таблицы по "|"
+	wikiLinkRe = regexp.MustCompile(`\[([^\|\]]+)\|[^\]]+\]`)
+)
+
+// Заголовки колонок таблиц, по которым определяется их назначение
+var (
+	scenarioHeaders = []string{"scenario", "test", "case", "name", "feature", "check", "step"}
+	resultHeaders   = []string{"result", "status", "verdict", "outcome"}
+	noteHeaders     = []string{"note", "comment", "detail", "remark"}
+)
+
+// extractResults находит результаты отдельных сценариев: пункты списков,
+// строки вида "Login: Fixed, Logout: Not Fixed" и таблицы с заголовком ||...||
+func (p *RegexParser) extractResults(body string) []domain.TestCaseResult {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+
+	var results []domain.TestCaseResult
+	// run накапливает подряд идущие строки "Сценарий: Результат" без маркеров списка;
+	// одиночная такая строка сценарием не считается
+	var run []domain.TestCaseResult
+	flush := func() {
+		if len(run) > 1 {
+			results = append(results, run...)
+		}
+		run = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if strings.HasPrefix(line, "||") {
+			flush()
+			tableResults, consumed := p.tableResults(lines[i:])
+			results = append(results, tableResults...)
+			i += consumed - 1
+			continue
+		}
+
+		if matches := bulletRe.FindStringSubmatch(line); matches != nil {
+			flush()
+			if inline := p.inlineResults(matches[1]); len(inline) > 0 {
+				results = append(results, inline...)
+			}
+			continue
+		}
+
+		inline := p.inlineResults(line)
+		switch {
+		case len(inline) > 1:
+			flush()
+			results = append(results, inline...)
+		case len(inline) == 1:
+			run = append(run, inline[0])
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return results
+}
+
+// inlineResults разбирает строку из одного или нескольких пунктов, разделенных "," или ";".
+// Строка принимается, только если каждый пункт содержит распознанный результат.
+func (p *RegexParser) inlineResults(line string) []domain.TestCaseResult {
+	items := []string{line}
+	if strings.ContainsAny(line, ",;") {
+		items = strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' })
+	}
+
+	var results []domain.TestCaseResult
+	for _, item := range items {
+		result, ok := p.itemResult(item)
+		if !ok {
+			// Запятая могла быть частью пояснения: пробуем строку целиком
+			if len(items) > 1 {
+				if whole, ok := p.itemResult(line); ok {
+					return []domain.TestCaseResult{whole}
+				}
+			}
+			return nil
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// itemResult разбирает пункт "Сценарий: Результат (пояснение)"
+func (p *RegexParser) itemResult(item string) (domain.TestCaseResult, bool) {
+	matches := itemRe.FindStringSubmatch(strings.TrimSpace(item))
+	if matches == nil {
+		return domain.TestCaseResult{}, false
+	}
+
+	scenario := p.removeJiraFormatting(matches[1])
+	if scenario == "" || fieldLabels[strings.ToLower(scenario)] {
+		return domain.TestCaseResult{}, false
+	}
+
+	rest := strings.TrimSpace(matches[2])
+	lowerRest := strings.ToLower(rest)
+	for _, candidate := range p.resultCandidates() {
+		if !strings.HasPrefix(lowerRest, candidate) || !wordBoundary(lowerRest, len(candidate)) {
+			continue
+		}
+		return p.testCaseResult(scenario, rest[:len(candidate)], cleanNote(p.removeJiraFormatting(rest[len(candidate):]))), true
+	}
+	return domain.TestCaseResult{}, false
+}
+
+// tableResults разбирает таблицу wiki-разметки, начинающуюся со строки заголовков,
+// и возвращает результаты вместе с числом прочитанных строк
+func (p *RegexParser) tableResults(lines []string) ([]domain.TestCaseResult, int) {
+	headers := splitTableRow(lines[0], "||")
+	scenarioCol, resultCol, noteCol := -1, -1, -1
+	for i, header := range headers {
+		header = strings.ToLower(p.removeJiraFormatting(header))
+		switch {
+		case resultCol < 0 && containsAny(header, resultHeaders):
+			resultCol = i
+		case noteCol < 0 && containsAny(header, noteHeaders):
+			noteCol = i
+		case scenarioCol < 0 && containsAny(header, scenarioHeaders):
+			scenarioCol = i
+		}
+	}
+	// Без заголовка сценария используем первую колонку, не занятую результатом
+	if scenarioCol < 0 {
+		for i := range headers {
+			if i != resultCol && i != noteCol {
+				scenarioCol = i
+				break
+			}
+		}
+	}
+
+	consumed := 1
+	var results []domain.TestCaseResult
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") || strings.HasPrefix(line, "||") {
+			break
+		}
+		consumed++
+
+		if resultCol < 0 || scenarioCol < 0 {
+			continue
+		}
+		cells := splitTableRow(wikiLinkRe.ReplaceAllString(line, "$1"), "|")
+		cell := func(col int) string {
+			if col < 0 || col >= len(cells) {
+				return ""
+			}
+			return p.removeJiraFormatting(cells[col])
+		}
+
+		scenario, result := cell(scenarioCol), cell(resultCol)
+		if scenario == "" && result == "" {
+			continue
+		}
+		results = append(results, p.testCaseResult(scenario, result, cell(noteCol)))
+	}
+	return results, consumed
+}
+
+// resultCandidates возвращает распознаваемые результаты в нижнем регистре, длинные первыми,
+// чтобы "not fixed" не принимался за "not"
+func (p *RegexParser) resultCandidates() []string {
+	seen := make(map[string]bool)
+	for _, outcome := range p.outcomes.Outcomes() {
+		seen[strings.ToLower(string(outcome))] = true
+	}
+	for from := range p.config.ResultNormalization {
+		seen[strings.ToLower(from)] = true
+	}
+	for outcome := range p.config.ResultCategories {
+		seen[strings.ToLower(outcome)] = true
+	}
+
+	candidates := make([]string, 0, len(seen))
+	for candidate := range seen {
+		if candidate != "" {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if len(candidates[i]) != len(candidates[j]) {
+			return len(candidates[i]) > len(candidates[j])
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates
+}
+
+// testCaseResult собирает результат сценария с каноническим результатом и его категорией
+func (p *RegexParser) testCaseResult(scenario, result, note string) domain.TestCaseResult {
+	outcome := p.outcomes.Normalize(result)
+	return domain.TestCaseResult{
+		Scenario: scenario,
+		Result:   outcome,
+		Category: p.outcomes.Category(outcome),
+		Note:     note,
+	}
+}
+
+func splitTableRow(line, separator string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSuffix(strings.TrimPrefix(line, separator), separator)
+	cells := strings.Split(line, separator)
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// cleanNote убирает разделители и скобки вокруг пояснения: "- no IdP", "(no IdP)"
+func cleanNote(note string) string {
+	note = strings.TrimSpace(strings.TrimLeft(note, " ,;:-–—"))
+	if strings.HasPrefix(note, "(") && strings.HasSuffix(note, ")") {
+		note = strings.TrimSpace(note[1 : len(note)-1])
+	}
+	return note
+}
+
+// wordBoundary сообщает, что в позиции i строки s заканчивается слово
+func wordBoundary(s string, i int) bool {
+	if i >= len(s) {
+		return true
+	}
+	r := rune(s[i])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func containsAny(s string, substrings []string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractResults(t *testing.T) {
+	t.Parallel()
+
+	p := newRegexParser(t, domain.ParsingConfig{
+		ResultNormalization: map[string]string{
+			"passed": "Fixed",
+			"failed": "Not Fixed",
+		},
+	})
+
+	tests := []struct {
+		name     string
+		body     string
+		expected []domain.TestCaseResult
+	}{
+		{
+			name: "bullet list",
+			body: "Tested on v1.2.3\n* Login: Fixed\n* Logout - Not Fixed (session is kept)\n* SSO: Could not test, no IdP\n\nResult: Partially Fixed",
+			expected: []domain.TestCaseResult{
+				{Scenario: "Login", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+				{Scenario: "Logout", Result: domain.OutcomeNotFixed, Category: domain.CategoryFail, Note: "session is kept"},
+				{Scenario: "SSO", Result: domain.OutcomeCouldNotTest, Category: domain.CategoryBlocked, Note: "no IdP"},
+			},
+		},
+		{
+			name: "normalized results in numbered list",
+			body: "# Upload => passed\n# Download -> FAILED timeout",
+			expected: []domain.TestCaseResult{
+				{Scenario: "Upload", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+				{Scenario: "Download", Result: domain.OutcomeNotFixed, Category: domain.CategoryFail, Note: "timeout"},
+			},
+		},
+		{
+			name: "inline list",
+			body: "Tested on v2.0.0\nLogin: Fixed, Logout: Not Fixed; SSO: Could not test",
+			expected: []domain.TestCaseResult{
+				{Scenario: "Login", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+				{Scenario: "Logout", Result: domain.OutcomeNotFixed, Category: domain.CategoryFail},
+				{Scenario: "SSO", Result: domain.OutcomeCouldNotTest, Category: domain.CategoryBlocked},
+			},
+		},
+		{
+			name: "consecutive plain lines",
+			body: "Login: Fixed\nLogout: Not Fixed",
+			expected: []domain.TestCaseResult{
+				{Scenario: "Login", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+				{Scenario: "Logout", Result: domain.OutcomeNotFixed, Category: domain.CategoryFail},
+			},
+		},
+		{
+			name: "wiki table",
+			body: "Tested on v1.2.3\n||Scenario||Result||Comment||\n|Login|*Fixed*| |\n|[Logout|https://example.com/TOS-1]|Failed|session is kept|\nResult: Partially Fixed",
+			expected: []domain.TestCaseResult{
+				{Scenario: "Login", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+				{Scenario: "Logout", Result: domain.OutcomeNotFixed, Category: domain.CategoryFail, Note: "session is kept"},
+			},
+		},
+		{
+			name: "table without result column",
+			body: "||Step||Action||\n|1|Open the page|",
+		},
+		{
+			name: "field labels are not scenarios",
+			body: "Tested on v1.2.3\n* Result: Fixed\n* Comment: verified on two devices",
+		},
+		{
+			name: "single plain line",
+			body: "Tested on v1.2.3\nResult: Fixed",
+		},
+		{
+			name: "item without known result",
+			body: "* Login: works as expected\n* Note - retest tomorrow",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, p.extractResults(tt.body))
+		})
+	}
+}
+
+func TestRegexParser_ParseCommentResults(t *testing.T) {
+	t.Parallel()
+
+	p := newRegexParser(t, domain.ParsingConfig{
+		QAIndicators:    []string{"tested on"},
+		VersionPatterns: []string{`(?i)tested on\s+(v?[\d.]+)`},
+		ResultPatterns:  []string{`(?i)result:\s*([^\n]+)`},
+	})
+
+	comment, ok := p.ParseComment(domain.RawComment{
+		Body: "Tested on v1.2.3\n* Login: Fixed\n* Logout: Not Fixed\nResult: Partially Fixed",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, "v1.2.3", comment.SoftwareVersion)
+	assert.Equal(t, domain.OutcomePartiallyFixed, comment.TestResult)
+	assert.Equal(t, domain.CategoryPartial, comment.Category)
+	assert.Equal(t, []domain.TestCaseResult{
+		{Scenario: "Login", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+		{Scenario: "Logout", Result: domain.OutcomeNotFixed, Category: domain.CategoryFail},
+	}, comment.Results)
+}
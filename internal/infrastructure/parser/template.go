@@ -0,0 +1,288 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// Поля шаблона, которые переносятся в domain.QAComment
+const (
+	fieldVersion = "version"
+	fieldResult  = "result"
+	fieldComment = "comment"
+)
+
+// DefaultTemplate возвращает шаблон для основного формата "Tested on vX. Result: Y. Comment: Z"
+func DefaultTemplate() domain.CommentTemplate {
+	return domain.CommentTemplate{
+		Fields: []domain.TemplateField{
+			{
+				Name:     fieldVersion,
+				Labels:   []string{"Tested on SW", "Tested on version", "Tested on"},
+				Pattern:  `^(v?\d+(?:\.\d+)*\S*)`,
+				Required: true,
+			},
+			{
+				Name:     fieldResult,
+				Labels:   []string{"Result"},
+				Values:   []string{"Fixed", "Not Fixed", "Partially Fixed", "Could not test"},
+				Required: true,
+			},
+			{
+				Name:   fieldComment,
+				Labels: []string{"Comment", "Notes", "Note", "Observations", "Observation"},
+			},
+		},
+	}
+}
+
+// TemplateParser принимает только QA комментарии, соответствующие шаблону из ParsingConfig,
+// без эвристик RegexParser, и сообщает причину отказа для остальных
+type TemplateParser struct {
+	base   *RegexParser
+	fields []templateField
+}
+
+// templateField - поле шаблона с подготовленными подписями и регулярным выражением
+type templateField struct {
+	domain.TemplateField
+	labels  []*regexp.Regexp // без учета регистра, длинные подписи первыми
+	pattern *regexp.Regexp
+}
+
+// NewTemplateParser проверяет шаблон и создает парсер; без шаблона используется DefaultTemplate
+func NewTemplateParser(config domain.ParsingConfig) (*TemplateParser, error) {
+	template := DefaultTemplate()
+	if config.Template != nil {
+		template = *config.Template
+	}
+	if len(template.Fields) == 0 {
+		return nil, fmt.Errorf("comment template has no fields")
+	}
+
+	base, err := NewRegexParser(config)
+	if err != nil {
+		return nil, err
+	}
+	p := &TemplateParser{base: base}
+	seen := make(map[string]bool)
+	for _, field := range template.Fields {
+		name := strings.ToLower(field.Name)
+		switch name {
+		case fieldVersion, fieldResult, fieldComment:
+		default:
+			return nil, fmt.Errorf("comment template: unknown field %q, use version, result or comment", field.Name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("comment template: field %q is listed twice", field.Name)
+		}
+		seen[name] = true
+
+		if len(field.Labels) == 0 {
+			return nil, fmt.Errorf("comment template: field %q has no labels", field.Name)
+		}
+
+		prepared := templateField{TemplateField: field}
+		prepared.Name = name
+		labels := make([]string, 0, len(field.Labels))
+		for _, label := range field.Labels {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+		sort.SliceStable(labels, func(i, j int) bool {
+			return len(labels[i]) > len(labels[j])
+		})
+		// Подписи ищутся в исходном тексте: strings.ToLower может изменить длину строки в байтах
+		// (например, "İ"), и смещения в тексте в нижнем регистре не совпали бы с исходными
+		for _, label := range labels {
+			prepared.labels = append(prepared.labels, regexp.MustCompile("(?i)"+regexp.QuoteMeta(label)))
+		}
+
+		if field.Pattern != "" {
+			re, err := regexp.Compile(field.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("comment template: field %q: invalid pattern: %w", field.Name, err)
+			}
+			prepared.pattern = re
+		}
+		p.fields = append(p.fields, prepared)
+	}
+	return p, nil
+}
+
+// IsQAComment использует те же индикаторы, что и RegexParser
+func (p *TemplateParser) IsQAComment(body string) bool {
+	return p.base.IsQAComment(body)
+}
+
+// ExplainComment показывает нормализованный текст, проверку индикаторов и итог сверки с шаблоном.
+// Выражения RegexParser в строгом режиме не применяются, поэтому поля не расписываются.
+func (p *TemplateParser) ExplainComment(comment domain.RawComment) domain.CommentExplanation {
+	explanation := p.base.ExplainComment(comment)
+	explanation.Fields = nil
+	explanation.Comment = nil
+
+	if qaComment, ok, reason := p.CheckComment(comment); ok {
+		explanation.Comment = &qaComment
+	} else {
+		explanation.Rejection = reason
+	}
+	return explanation
+}
+
+// ParseComment принимает только комментарии, прошедшие CheckComment
+func (p *TemplateParser) ParseComment(comment domain.RawComment) (domain.QAComment, bool) {
+	qaComment, ok, _ := p.CheckComment(comment)
+	return qaComment, ok
+}
+
+// CheckComment сверяет QA комментарий с шаблоном: поля ищутся по подписям в заданном порядке,
+// значение поля продолжается до конца строки или до подписи следующего поля
+func (p *TemplateParser) CheckComment(comment domain.RawComment) (domain.QAComment, bool, string) {
+	if !p.base.IsQAComment(comment.Body) {
+		return domain.QAComment{}, false, ""
+	}
+
+	text := p.base.removeJiraFormatting(comment.Body)
+
+	qaComment := domain.QAComment{
+		Created:     comment.Created,
+		Updated:     comment.Updated,
+		AuthorEmail: comment.AuthorEmail,
+	}
+
+	var problems []string
+	cursor := 0
+	previous := ""
+	for i, field := range p.fields {
+		start, labelEnd := field.find(text, cursor)
+		if start < 0 {
+			if anywhere, _ := field.find(text, 0); anywhere >= 0 && previous != "" {
+				problems = append(problems, fmt.Sprintf("field %q must follow %q", field.Name, previous))
+			} else if field.Required {
+				problems = append(problems, fmt.Sprintf("missing required field %q (labels: %s)", field.Name, strings.Join(field.Labels, ", ")))
+			}
+			continue
+		}
+
+		// Значение заканчивается концом строки или подписью одного из следующих полей
+		end := len(text)
+		if eol := strings.IndexByte(text[labelEnd:], '\n'); eol >= 0 {
+			end = labelEnd + eol
+		}
+		for _, next := range p.fields[i+1:] {
+			if nextStart, _ := next.find(text, labelEnd); nextStart >= 0 && nextStart < end {
+				end = nextStart
+			}
+		}
+		cursor, previous = end, field.Name
+
+		value, problem := p.fieldValue(field, text[labelEnd:end])
+		if problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+
+		switch field.Name {
+		case fieldVersion:
+			qaComment.SoftwareVersion = value
+			qaComment.Version, _ = domain.ParseVersion(value)
+		case fieldResult:
+			qaComment.TestResult = domain.TestOutcome(value)
+			qaComment.Category = p.base.outcomes.Category(qaComment.TestResult)
+		case fieldComment:
+			qaComment.Comment = value
+		}
+	}
+
+	if len(problems) > 0 {
+		return domain.QAComment{}, false, strings.Join(problems, "; ")
+	}
+
+	qaComment.Results = p.base.extractResults(comment.Body)
+	return qaComment, true, ""
+}
+
+// fieldValue очищает значение поля и проверяет его по шаблону; problem описывает несоответствие
+func (p *TemplateParser) fieldValue(field templateField, raw string) (value string, problem string) {
+	value = strings.TrimSpace(strings.TrimLeft(raw, " \t:"))
+	value = strings.TrimRight(value, " \t.,;")
+
+	if value == "" {
+		if field.Required {
+			return "", fmt.Sprintf("field %q is empty", field.Name)
+		}
+		return "", ""
+	}
+
+	if field.pattern != nil {
+		matches := field.pattern.FindStringSubmatch(value)
+		if matches == nil {
+			return "", fmt.Sprintf("field %q: %q does not match %s", field.Name, value, field.Pattern)
+		}
+		if len(matches) > 1 {
+			value = matches[1]
+		} else {
+			value = matches[0]
+		}
+	}
+
+	if field.Name == fieldResult {
+		value = string(p.base.outcomes.Normalize(value))
+	}
+
+	if len(field.Values) > 0 {
+		for _, allowed := range field.Values {
+			if strings.EqualFold(value, allowed) {
+				return allowed, ""
+			}
+		}
+		return "", fmt.Sprintf("field %q: %q is not one of %s", field.Name, value, strings.Join(field.Values, ", "))
+	}
+
+	return value, ""
+}
+
+// find возвращает начало первой подписи поля в text начиная с from и конец этой подписи;
+// подпись должна быть отдельным словом. -1, если подпись не найдена.
+func (f templateField) find(text string, from int) (start, end int) {
+	start, end = -1, -1
+	for _, label := range f.labels {
+		for offset := from; offset <= len(text); {
+			loc := label.FindStringIndex(text[offset:])
+			if loc == nil {
+				break
+			}
+			i, j := offset+loc[0], offset+loc[1]
+			if labelBoundary(text, i, j) {
+				// Более длинные подписи проверяются первыми, поэтому при равном начале выигрывают они
+				if start < 0 || i < start {
+					start, end = i, j
+				}
+				break
+			}
+			_, size := utf8.DecodeRuneInString(text[i:])
+			offset = i + size
+		}
+	}
+	return start, end
+}
+
+// labelBoundary проверяет, что text[start:end] не продолжает слово ни слева, ни справа
+func labelBoundary(text string, start, end int) bool {
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWord(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWord(after) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateParser_CheckComment(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewTemplateParser(domain.ParsingConfig{
+		QAIndicators: []string{"tested on", "result"},
+		ResultNormalization: map[string]string{
+			"passed":  "Fixed",
+			"pending": "Not Fixed",
+		},
+	})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected domain.QAComment
+		ok       bool
+		reason   string
+	}{
+		{
+			name:     "single line",
+			body:     "Tested on SW v1.4.0. Result: Fixed. Comment: works on both devices",
+			expected: domain.QAComment{SoftwareVersion: "v1.4.0", Version: mustVersion(t, "v1.4.0"), TestResult: domain.OutcomeFixed, Category: domain.CategoryPass, Comment: "works on both devices"},
+			ok:       true,
+		},
+		{
+			name:     "one field per line with formatting",
+			body:     "*Tested on* v2.0\n*Result:* {color:green}passed{color}",
+			expected: domain.QAComment{SoftwareVersion: "v2.0", Version: mustVersion(t, "v2.0"), TestResult: domain.OutcomeFixed, Category: domain.CategoryPass},
+			ok:       true,
+		},
+		{
+			// "İ" в нижнем регистре длиннее в байтах: подписи ищутся и вырезаются из одного и того же текста
+			name:     "text that changes length in lower case",
+			body:     "İİİ Tested on v1.4.0. Result: Fixed. Comment: İzmir office",
+			expected: domain.QAComment{SoftwareVersion: "v1.4.0", Version: mustVersion(t, "v1.4.0"), TestResult: domain.OutcomeFixed, Category: domain.CategoryPass, Comment: "İzmir office"},
+			ok:       true,
+		},
+		{
+			name:     "hyphenated version",
+			body:     "Tested on 5.4.0-hotfix.2. Result: Fixed",
+			expected: domain.QAComment{SoftwareVersion: "5.4.0-hotfix.2", Version: mustVersion(t, "5.4.0-hotfix.2"), TestResult: domain.OutcomeFixed, Category: domain.CategoryPass},
+			ok:       true,
+		},
+		{
+			name:     "pre-release version with build metadata",
+			body:     "*Tested on* _v5.4.0-rc1+build.7_\nResult: Not Fixed",
+			expected: domain.QAComment{SoftwareVersion: "v5.4.0-rc1+build.7", Version: mustVersion(t, "v5.4.0-rc1+build.7"), TestResult: domain.OutcomeNotFixed, Category: domain.CategoryFail},
+			ok:       true,
+		},
+		{
+			name: "not a QA comment",
+			body: "Deployed to staging, pending deploy to production",
+		},
+		{
+			name:   "heuristic result is not accepted",
+			body:   "Tested on v1.4.0, looks fixed to me",
+			reason: `missing required field "result" (labels: Result)`,
+		},
+		{
+			name:   "value outside of the allowed list",
+			body:   "Tested on v1.4.0\nResult: Done",
+			reason: `field "result": "Done" is not one of Fixed, Not Fixed, Partially Fixed, Could not test`,
+		},
+		{
+			name:   "fields out of order",
+			body:   "Result: Fixed\nTested on v1.4.0",
+			reason: `field "result" must follow "version"`,
+		},
+		{
+			name:   "version does not match pattern",
+			body:   "Tested on staging. Result: Fixed",
+			reason: `field "version": "staging" does not match ^(v?\d+(?:\.\d+)*\S*)`,
+		},
+		{
+			name:   "several problems",
+			body:   "Tested on: \nResult: maybe",
+			reason: `field "version" is empty; field "result": "maybe" is not one of Fixed, Not Fixed, Partially Fixed, Could not test`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			comment, ok, reason := p.CheckComment(domain.RawComment{Body: tt.body})
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.reason, reason)
+			if tt.ok {
+				assert.Equal(t, tt.expected, comment)
+			}
+		})
+	}
+}
+
+func TestTemplateParser_CustomTemplate(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewTemplateParser(domain.ParsingConfig{
+		QAIndicators: []string{"qa verdict"},
+		Template: &domain.CommentTemplate{Fields: []domain.TemplateField{
+			{Name: "result", Labels: []string{"QA verdict"}, Values: []string{"OK", "NOK"}, Required: true},
+			{Name: "version", Labels: []string{"Build"}},
+			{Name: "comment", Labels: []string{"Примечание"}},
+		}},
+	})
+	assert.NoError(t, err)
+
+	comment, ok := p.ParseComment(domain.RawComment{Body: "QA verdict: ok\nBuild: 5.2.1", AuthorEmail: "qa@example.com"})
+	assert.True(t, ok)
+	assert.Equal(t, domain.QAComment{SoftwareVersion: "5.2.1", Version: mustVersion(t, "5.2.1"), TestResult: "OK", Category: domain.CategoryPass, AuthorEmail: "qa@example.com"}, comment)
+
+	// Подписи на кириллице сравниваются без учета регистра
+	comment, ok = p.ParseComment(domain.RawComment{Body: "QA verdict: NOK\nBuild: 5.2.1\nПРИМЕЧАНИЕ: падает на шаге 3"})
+	assert.True(t, ok)
+	assert.Equal(t, "падает на шаге 3", comment.Comment)
+
+	_, err = NewTemplateParser(domain.ParsingConfig{Template: &domain.CommentTemplate{Fields: []domain.TemplateField{
+		{Name: "result", Labels: []string{"Result"}, Pattern: "("},
+	}}})
+	assert.ErrorContains(t, err, "invalid pattern")
+}
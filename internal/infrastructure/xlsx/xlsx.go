@@ -0,0 +1,336 @@
+// Package xlsx writes minimal Office Open XML workbooks (.xlsx) using only the standard library.
+// It supports text and numeric cells, per-cell font and fill styles, a frozen header row and an
+// autofilter over the header, which is all the exporters need.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Style describes the look of a cell. Colors are RGB hex strings such as "C6EFCE".
+type Style struct {
+	Bold      bool
+	FontColor string
+	FillColor string
+	Wrap      bool
+}
+
+// StyleID references a style registered with Workbook.AddStyle; 0 is the default style
+type StyleID int
+
+// Cell is a single worksheet cell
+type Cell struct {
+	Text     string
+	Number   float64
+	IsNumber bool
+	Style    StyleID
+}
+
+// Text returns a text cell
+func Text(value string, style StyleID) Cell {
+	return Cell{Text: value, Style: style}
+}
+
+// Number returns a numeric cell
+func Number(value float64, style StyleID) Cell {
+	return Cell{Number: value, IsNumber: true, Style: style}
+}
+
+// Sheet is a worksheet. The first row is treated as the header when FreezeHeader or AutoFilter is set.
+type Sheet struct {
+	Name         string
+	Rows         [][]Cell
+	FreezeHeader bool
+	AutoFilter   bool
+}
+
+// AddRow appends a row of cells
+func (s *Sheet) AddRow(cells ...Cell) {
+	s.Rows = append(s.Rows, cells)
+}
+
+// Workbook is an in-memory workbook
+type Workbook struct {
+	Sheets []*Sheet
+	styles []Style
+}
+
+// NewWorkbook creates an empty workbook with the default style
+func NewWorkbook() *Workbook {
+	return &Workbook{styles: []Style{{}}}
+}
+
+// AddStyle registers a cell style
+func (w *Workbook) AddStyle(style Style) StyleID {
+	w.styles = append(w.styles, style)
+	return StyleID(len(w.styles) - 1)
+}
+
+// AddSheet appends a worksheet. Excel limits names to 31 characters without []:*?/\.
+func (w *Workbook) AddSheet(name string) *Sheet {
+	sheet := &Sheet{Name: name}
+	w.Sheets = append(w.Sheets, sheet)
+	return sheet
+}
+
+// Write writes the workbook as an .xlsx (zip) archive
+func (w *Workbook) Write(out io.Writer) error {
+	if len(w.Sheets) == 0 {
+		return fmt.Errorf("workbook has no sheets")
+	}
+
+	archive := zip.NewWriter(out)
+	parts := []struct {
+		name    string
+		content []byte
+	}{
+		{"[Content_Types].xml", w.contentTypes()},
+		{"_rels/.rels", []byte(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`)},
+		{"xl/workbook.xml", w.workbook()},
+		{"xl/_rels/workbook.xml.rels", w.workbookRels()},
+		{"xl/styles.xml", w.stylesheet()},
+	}
+	for i, sheet := range w.Sheets {
+		parts = append(parts, struct {
+			name    string
+			content []byte
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.xml()})
+	}
+
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (w *Workbook) contentTypes() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range w.Sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.Bytes()
+}
+
+func (w *Workbook) workbook() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, sheet := range w.Sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheet.Name), i+1, i+1)
+	}
+	b.WriteString(`</sheets>`)
+
+	// Excel expects a hidden _FilterDatabase name for every sheet with an autofilter
+	var names bytes.Buffer
+	for i, sheet := range w.Sheets {
+		if ref := sheet.filterRef(); ref != "" {
+			fmt.Fprintf(&names, `<definedName name="_xlnm._FilterDatabase" localSheetId="%d" hidden="1">%s!%s</definedName>`,
+				i, escape(quoteSheetName(sheet.Name)), absoluteRef(ref))
+		}
+	}
+	if names.Len() > 0 {
+		b.WriteString(`<definedNames>`)
+		b.Write(names.Bytes())
+		b.WriteString(`</definedNames>`)
+	}
+	b.WriteString(`</workbook>`)
+	return b.Bytes()
+}
+
+func (w *Workbook) workbookRels() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range w.Sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.Sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.Bytes()
+}
+
+// stylesheet writes one font and one fill per style; fills 0 and 1 are reserved by the format
+func (w *Workbook) stylesheet() []byte {
+	var fonts, fills, xfs bytes.Buffer
+	fillCount := 2
+	for i, style := range w.styles {
+		fonts.WriteString(`<font>`)
+		if style.Bold {
+			fonts.WriteString(`<b/>`)
+		}
+		fonts.WriteString(`<sz val="11"/>`)
+		if style.FontColor != "" {
+			fmt.Fprintf(&fonts, `<color rgb="FF%s"/>`, style.FontColor)
+		}
+		fonts.WriteString(`<name val="Calibri"/><family val="2"/></font>`)
+
+		fillID := 0
+		if style.FillColor != "" {
+			fmt.Fprintf(&fills, `<fill><patternFill patternType="solid"><fgColor rgb="FF%s"/><bgColor indexed="64"/></patternFill></fill>`, style.FillColor)
+			fillID = fillCount
+			fillCount++
+		}
+
+		fmt.Fprintf(&xfs, `<xf numFmtId="0" fontId="%d" fillId="%d" borderId="0" xfId="0"`, i, fillID)
+		if i > 0 {
+			xfs.WriteString(` applyFont="1"`)
+		}
+		if fillID > 0 {
+			xfs.WriteString(` applyFill="1"`)
+		}
+		if style.Wrap {
+			xfs.WriteString(` applyAlignment="1"><alignment vertical="top" wrapText="1"/></xf>`)
+		} else {
+			xfs.WriteString(`/>`)
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	fmt.Fprintf(&b, `<fonts count="%d">%s</fonts>`, len(w.styles), fonts.String())
+	fmt.Fprintf(&b, `<fills count="%d"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill>%s</fills>`, fillCount, fills.String())
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&b, `<cellXfs count="%d">%s</cellXfs>`, len(w.styles), xfs.String())
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+	return b.Bytes()
+}
+
+// maxColumnWidth keeps columns with long comments readable
+const maxColumnWidth = 60
+
+func (s *Sheet) xml() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+
+	if s.FreezeHeader && len(s.Rows) > 0 {
+		b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>` +
+			`<selection pane="bottomLeft" activeCell="A2" sqref="A2"/></sheetView></sheetViews>`)
+	}
+
+	if widths := s.columnWidths(); len(widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, width := range widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	for r, row := range s.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			ref := CellRef(c, r)
+			style := ""
+			if cell.Style != 0 {
+				style = fmt.Sprintf(` s="%d"`, cell.Style)
+			}
+			if cell.IsNumber {
+				fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, style, strconv.FormatFloat(cell.Number, 'f', -1, 64))
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escape(cell.Text))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+
+	if ref := s.filterRef(); ref != "" {
+		fmt.Fprintf(&b, `<autoFilter ref="%s"/>`, ref)
+	}
+	b.WriteString(`</worksheet>`)
+	return b.Bytes()
+}
+
+// filterRef returns the autofilter range over the header and all rows, or "" without autofilter
+func (s *Sheet) filterRef() string {
+	if !s.AutoFilter || len(s.Rows) == 0 || len(s.Rows[0]) == 0 {
+		return ""
+	}
+	return CellRef(0, 0) + ":" + CellRef(len(s.Rows[0])-1, len(s.Rows)-1)
+}
+
+// columnWidths fits each column to its longest line, within maxColumnWidth
+func (s *Sheet) columnWidths() []int {
+	var widths []int
+	for _, row := range s.Rows {
+		for c, cell := range row {
+			for len(widths) <= c {
+				widths = append(widths, 8)
+			}
+			text := cell.Text
+			if cell.IsNumber {
+				text = strconv.FormatFloat(cell.Number, 'f', -1, 64)
+			}
+			for _, line := range bytes.Split([]byte(text), []byte("\n")) {
+				if width := utf8.RuneCount(line) + 2; width > widths[c] {
+					widths[c] = min(width, maxColumnWidth)
+				}
+			}
+		}
+	}
+	return widths
+}
+
+// CellRef converts zero-based column and row indexes to an A1 reference, e.g. (27, 0) -> "AB1"
+func CellRef(column, row int) string {
+	name := ""
+	for column >= 0 {
+		name = string(rune('A'+column%26)) + name
+		column = column/26 - 1
+	}
+	return name + strconv.Itoa(row+1)
+}
+
+// absoluteRef turns "A1:C5" into "$A$1:$C$5" for defined names
+func absoluteRef(ref string) string {
+	var b bytes.Buffer
+	letters := false
+	for i := 0; i < len(ref); i++ {
+		c := ref[i]
+		isLetter := c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+		if isLetter && !letters || isDigit && (i == 0 || !(ref[i-1] >= '0' && ref[i-1] <= '9')) {
+			b.WriteByte('$')
+		}
+		letters = isLetter
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// quoteSheetName quotes sheet names in formulas: 'QA Comments'
+func quoteSheetName(name string) string {
+	return "'" + string(bytes.ReplaceAll([]byte(name), []byte("'"), []byte("''"))) + "'"
+}
+
+// escape escapes XML text; characters that XML 1.0 does not allow are replaced with U+FFFD
+func escape(value string) string {
+	var b bytes.Buffer
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readParts распаковывает книгу и проверяет, что каждая часть - корректный XML
+func readParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := make(map[string]string)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = rc.Close()
+
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("part %s is not well-formed XML: %v", f.Name, err)
+			}
+		}
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func TestWorkbookWrite(t *testing.T) {
+	workbook := NewWorkbook()
+	header := workbook.AddStyle(Style{Bold: true, FillColor: "D9D9D9"})
+	pass := workbook.AddStyle(Style{FontColor: "006100", FillColor: "C6EFCE"})
+	wrap := workbook.AddStyle(Style{Wrap: true})
+
+	issues := workbook.AddSheet("Issues")
+	issues.FreezeHeader = true
+	issues.AutoFilter = true
+	issues.AddRow(Text("Key", header), Text("Result", header), Text("Comments", header))
+	issues.AddRow(Text("TOS-1", 0), Text("Fixed", pass), Number(2, 0))
+	issues.AddRow(Text("TOS-2", 0), Text(`<b>"Tom & Jerry"</b>`+"\nsecond line\x01", wrap), Number(1.5, 0))
+
+	workbook.AddSheet("QA Comments").AddRow(Text("only", 0))
+
+	var buf bytes.Buffer
+	if err := workbook.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	parts := readParts(t, buf.Bytes())
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels",
+		"xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		assert.Contains(t, parts, name)
+	}
+
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Issues" sheetId="1" r:id="rId1"/><sheet name="QA Comments" sheetId="2" r:id="rId2"/>`)
+	assert.Contains(t, parts["xl/workbook.xml"], `<definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">&#39;Issues&#39;!$A$1:$C$3</definedName>`)
+	assert.Contains(t, parts["xl/_rels/workbook.xml.rels"], `Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+	assert.Contains(t, sheet, `<autoFilter ref="A1:C3"/>`)
+	assert.Contains(t, sheet, `<c r="B2" s="2" t="inlineStr"><is><t xml:space="preserve">Fixed</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="C2"><v>2</v></c>`)
+	assert.Contains(t, sheet, `<c r="C3"><v>1.5</v></c>`)
+	assert.Contains(t, sheet, `&lt;b&gt;&#34;Tom &amp; Jerry&#34;&lt;/b&gt;&#xA;second line`+"�")
+
+	// Второй лист без заголовка: нет закрепления и автофильтра
+	assert.NotContains(t, parts["xl/worksheets/sheet2.xml"], "<pane")
+	assert.NotContains(t, parts["xl/worksheets/sheet2.xml"], "<autoFilter")
+
+	styles := parts["xl/styles.xml"]
+	assert.Contains(t, styles, `<fills count="4">`)
+	assert.Contains(t, styles, `<cellXfs count="4">`)
+	assert.Contains(t, styles, `<color rgb="FF006100"/>`)
+	assert.Contains(t, styles, `<fgColor rgb="FFC6EFCE"/>`)
+	assert.Contains(t, styles, `<alignment vertical="top" wrapText="1"/>`)
+	assert.Equal(t, 1, strings.Count(styles, "<b/>"))
+}
+
+func TestWorkbookWithoutSheets(t *testing.T) {
+	assert.Error(t, NewWorkbook().Write(io.Discard))
+}
+
+func TestCellRef(t *testing.T) {
+	assert.Equal(t, "A1", CellRef(0, 0))
+	assert.Equal(t, "Z10", CellRef(25, 9))
+	assert.Equal(t, "AA1", CellRef(26, 0))
+	assert.Equal(t, "AB2", CellRef(27, 1))
+	assert.Equal(t, "ZZ1", CellRef(701, 0))
+	assert.Equal(t, "AAA1", CellRef(702, 0))
+	assert.Equal(t, "$AB$12:$C$5", absoluteRef("AB12:C5"))
+}
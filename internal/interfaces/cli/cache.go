@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/infrastructure/cache"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local issue cache",
+		Long: `Manage the local cache of JIRA issues.
+Issues are cached by key and reused while JIRA reports no updates since they were fetched.
+Use --no-cache to bypass the cache or --refresh to refetch cached issues.`,
+	}
+
+	cmd.AddCommand(newCachePruneCommand())
+	return cmd
+}
+
+func newCachePruneCommand() *cobra.Command {
+	var olderThan time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove cached issues",
+		Long: `Remove cached issues from the local cache.
+Without --older-than every cached issue is removed.
+Only cache entries are removed; other files in the cache directory are left untouched.
+Example: jira-parser cache prune
+Example: jira-parser cache prune --older-than 720h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Конфигурация необязательна: без нее используется каталог по умолчанию
+			_ = viper.ReadInConfig()
+
+			dir, err := cacheDir(viper.GetString("cache.dir"))
+			if err != nil {
+				return err
+			}
+
+			removed, err := cache.Prune(dir, olderThan)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Removed %d cached issues from %s\n", removed, dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "Only remove issues fetched longer ago than this duration (e.g., 168h)")
+	return cmd
+}
+
+// cacheDir возвращает каталог кэша из конфигурации или каталог по умолчанию
+func cacheDir(configured string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	return cache.DefaultDir()
+}
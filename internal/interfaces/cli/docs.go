@@ -51,6 +51,18 @@ Supports multiple output formats: markdown, man, rest, and yaml.`,
 func generateOfflineMarkdownDocs(outputDir string) {
 	content := `# jira-parser CLI Documentation
 
+## Global Flags
+
+      --timeout duration  Abort the command after the given duration, e.g. 30s or 5m (0 disables the timeout)
+      --source string     Where to read issues from: jira (default), or file:<dir> with XML/JSON exports
+      --no-cache          Do not read or write the on-disk issue cache
+      --refresh           Ignore cached issues and fetch them again, updating the cache
+      --strict            Accept only QA comments matching parsing.template and report why the others were rejected
+      --tz string         Time zone for displayed dates and plain date filters: UTC, Local, Europe/Moscow or +03:00
+      --output string     Output format of parse, parse-multiple and last-comment: text (default), json, jsonl, yaml or table
+
+Pressing Ctrl-C once stops in-flight requests and prints or exports the tickets finished so far.
+
 ## Commands
 
 ### parse
@@ -59,9 +71,13 @@ Parse all QA comments for an issue
 Usage: jira-parser parse <issue-key>
 
 Flags:
-  -r, --result string     Filter comments by test result (e.g., Fixed, Not Fixed, etc.)
-  -d, --date-from string  Filter comments created after specified date (format: YYYY-MM-DD)
-  -t, --date-to string    Filter comments created before specified date (format: YYYY-MM-DD)
+      --filter string     Filter expression, e.g. 'result in (Fixed, "Partially Fixed") and version >= 5.2'
+  -r, --result string     Filter comments by test result (e.g., Fixed, Not Fixed) or result category (pass, fail, partial, blocked, unknown)
+  -d, --date-from string  Filter comments created at or after the date: YYYY-MM-DD, RFC3339, 7d/2w/12h ago, or last-sprint
+  -t, --date-to string    Filter comments created at or before the date (same formats as --date-from)
+      --min-version string Filter comments tested on this software version or later (e.g. 5.4, v5.4.0-rc1)
+      --max-version string Filter comments tested on this software version or earlier
+      --template string   Go template file or template name from config.yaml (see Output templates)
 
 ### last-comment
 Get the last QA comment for an issue
@@ -69,18 +85,40 @@ Get the last QA comment for an issue
 Usage: jira-parser last-comment [issue-key...]
 
 Flags:
+      --filter string     Filter expression; the last matching QA comment is shown
+  -r, --result string     Filter comments by test result or result category
+  -d, --date-from string  Filter comments created at or after the date (same formats as parse)
+  -t, --date-to string    Filter comments created at or before the date
+      --min-version string Filter comments tested on this software version or later
+      --max-version string Filter comments tested on this software version or earlier
   -f, --tickets-file Path to the YAML file containing the list of tickets (default: ./configs/tickets.yaml)
+      --jql          JQL query used to select tickets
+      --template string   Go template file or template name from config.yaml (see Output templates)
 
 ### export
-Export all QA comments as JSON or HTML
+Export all QA comments as JSON, HTML, CSV, TSV, XLSX, Markdown, Confluence wiki or JUnit XML
 
 Usage: jira-parser export [issue-key...]
 
 Flags:
   -p, --pretty       Pretty print JSON output
-  -F, --format       Output format (json or html) (default "json")
+  -F, --format       Output format: json, html, csv, tsv, xlsx, markdown, confluence or junit (default "json")
+      --columns      CSV/TSV columns in output order (default "key,summary,assignee,qa_owner,created,author,version,result,comment");
+                     also available: latest_version, updated, category, scenarios
+      --latest-only  CSV/TSV: one row per issue with only the latest QA comment
+      --details      Markdown/Confluence: add all QA comments grouped by issue after the status table
+      --template     Go template file or template name from config.yaml; replaces --format,
+                     the file extension comes from the template name (report.md.tmpl -> .md)
   -o, --output-dir   Output directory for exported files (default "./QA_comments")
   -f, --tickets-file Path to the YAML file containing the list of tickets (default: ./configs/tickets.yaml)
+      --jql          JQL query used to select tickets
+      --concurrency  Number of tickets processed in parallel (default 1)
+      --filter string     Filter expression, e.g. 'result in (Fixed, "Partially Fixed") and version >= 5.2'
+  -r, --result string     Filter comments by test result or result category
+  -d, --date-from string  Filter comments created at or after the date (same formats as parse)
+  -t, --date-to string    Filter comments created at or before the date
+      --min-version string Filter comments tested on this software version or later
+      --max-version string Filter comments tested on this software version or earlier
 
 ### parse-multiple
 Parse QA comments for multiple tickets from tickets file or command line arguments
@@ -88,10 +126,38 @@ Parse QA comments for multiple tickets from tickets file or command line argumen
 Usage: jira-parser parse-multiple [tickets...]
 
 Flags:
-  -r, --result string     Filter comments by test result (e.g., Fixed, Not Fixed, etc.)
-  -d, --date-from string  Filter comments created after specified date (format: YYYY-MM-DD)
-  -t, --date-to string    Filter comments created before specified date (format: YYYY-MM-DD)
+      --filter string     Filter expression, e.g. 'result in (Fixed, "Partially Fixed") and version >= 5.2'
+  -r, --result string     Filter comments by test result (e.g., Fixed, Not Fixed) or result category (pass, fail, partial, blocked, unknown)
+  -d, --date-from string  Filter comments created at or after the date: YYYY-MM-DD, RFC3339, 7d/2w/12h ago, or last-sprint
+  -t, --date-to string    Filter comments created at or before the date (same formats as --date-from)
+      --min-version string Filter comments tested on this software version or later (e.g. 5.4, v5.4.0-rc1)
+      --max-version string Filter comments tested on this software version or earlier
   -f, --tickets-file      Path to the YAML file containing the list of tickets (default: ./configs/tickets.yaml)
+      --jql string        JQL query used to select tickets
+      --concurrency int   Number of tickets processed in parallel (default 1)
+      --template string   Go template file or template name from config.yaml (see Output templates)
+
+### cache prune
+Remove cached issues
+
+Usage: jira-parser cache prune
+
+Flags:
+      --older-than duration  Only remove issues fetched longer ago than this duration (e.g., 168h)
+
+### explain
+Explain why comments were or were not parsed as QA comments
+
+Usage: jira-parser explain <issue-key> [comment-id]
+       jira-parser explain --stdin [issue-or-project-key] < comment.txt
+
+Flags:
+      --stdin   Read the comment body from standard input instead of JIRA
+
+### schema
+Print the JSON Schema of exported JSON and --output json/yaml documents
+
+Usage: jira-parser schema > jira-parser-report.schema.json
 
 ### version
 Print the version number of jira-parser
@@ -120,15 +186,50 @@ Parse an issue:
 Parse with result filter:
   jira-parser parse TOS-30690 --result="Fixed"
 
+Parse with result category filter:
+  jira-parser parse TOS-30690 --result=fail
+
 Parse with date range:
   jira-parser parse TOS-30690 --date-from=2023-01-01 --date-to=2023-12-31
 
+Parse comments from the last week, showing dates in UTC:
+  jira-parser parse TOS-30690 --date-from=7d --tz UTC
+
+Print an issue as JSON for scripts (schema_version, issues, failures):
+  jira-parser parse TOS-30690 --output json
+
+Stream one JSON object per ticket:
+  jira-parser parse-multiple --jql "fixVersion = 5.4" --output jsonl
+
+Parse comments for a range of software versions:
+  jira-parser parse TOS-30690 --min-version=5.4 --max-version=5.4.0-hotfix.3
+
+Export comments matching a filter expression:
+  jira-parser export --filter 'result in (Fixed, "Partially Fixed") and author ~ "@qa.example.com" and version >= 5.2'
+
 Export as JSON:
   jira-parser export TOS-30690 --pretty
 
-Export as HTML:
+Export a self-contained interactive HTML report (sortable, filterable tables, charts, JIRA links):
   jira-parser export TOS-30690 --format html --output-dir ./reports
 
+Export the latest verdict per issue as CSV:
+  jira-parser export --format csv --columns key,summary,qa_owner,result --latest-only
+
+Export an Excel workbook with Issues, QA Comments and Summary sheets:
+  jira-parser export --jql "fixVersion = 5.4" --format xlsx
+
+Export a release notes status table with issue keys linked to JIRA:
+  jira-parser export --jql "fixVersion = 5.4" --format markdown
+  jira-parser export --jql "fixVersion = 5.4" --format confluence --details
+
+Export QA verdicts as JUnit XML for Jenkins and GitLab test reports:
+  jira-parser export --jql "fixVersion = 5.4" --format junit --output-dir ./test-reports
+
+Export or print through a custom template:
+  jira-parser export --jql "fixVersion = 5.4" --template ./templates/release-notes.md.tmpl
+  jira-parser parse-multiple --jql "fixVersion = 5.4" --template mobile
+
 Parse multiple tickets:
  jira-parser parse-multiple TOS-30690 TOS-30692
 
@@ -143,6 +244,74 @@ Get last comment for multiple issues:
 
 Get last comment from tickets file:
   jira-parser last-comment --tickets-file ./my-tickets.yaml
+
+Parse tickets selected by JQL:
+  jira-parser parse-multiple --jql "project = TOS AND fixVersion = 5.4"
+
+## Filter expressions
+
+--filter and the filter key of the tickets file accept conditions joined with and, or, not and parentheses:
+  field operator value, field in (value, ...), field not in (value, ...)
+
+Fields:
+  result    test result or its category; = ignores case, ~ matches a substring
+  category  pass, fail, partial, blocked or unknown
+  author    author email, e.g. author ~ "@qa.example.com"
+  comment   comment text
+  version   software version compared as semver: =, !=, <, <=, >, >=; ~ matches the version as written
+  created   creation date (alias: date): <, <=, >, >= with the same formats as --date-from
+  updated   last update date
+
+Operators: =, !=, ~ (contains), !~, <, <=, >, >=, in, not in. Values with spaces or special
+characters are quoted: "Partially Fixed". Comments without a recognized version or date only
+match negations (!=, !~, not in). --filter is combined with the other filter flags using and.
+
+last-sprint is the start of the previous sprint. Sprints of sprint.length (14d, 2w) follow each other
+from sprint.start in config.yaml; without the sprint section they are two weeks long, starting on Monday
+2024-01-01.
+
+## Machine-readable output
+
+--output on parse, parse-multiple and last-comment prints results to stdout as json, jsonl, yaml or table
+instead of coloured text; other commands accept only --output text. json and yaml print one document,
+the same one export --format json writes:
+
+  schema_version  version of the document schema, increased whenever a field is renamed or removed
+  generated_at    when the document was generated (RFC 3339)
+  tool            name, version, commit and build date of jira-parser
+  source          type jira with base_url, or type file with the dir of --source file:<dir>
+  filters         filters that were set: expression, result, date_from, date_to, min_version,
+                  max_version, jql and tickets_filter (the filter of the tickets file)
+  issues          key, summary, assignee_email, qa_owner_email, latest_version, comments_incomplete,
+                  comments and rejections; comments have software_version, version, test_result,
+                  category, comment, created, updated, author_email and results
+  failures        key and error of every ticket that could not be processed
+
+jsonl prints one object per ticket with schema_version and type "issue", then one object per failed
+ticket with type "failure". table prints one aligned row per QA comment. Warnings go to stderr, and
+--template works only with --output text. "jira-parser schema" prints the JSON Schema (draft 2020-12)
+of the document for validation in downstream tools.
+
+## Output templates
+
+--template on parse, parse-multiple, last-comment and export renders a Go template instead of the
+built-in layout. Templates whose file name contains .html or .htm use html/template and escape JIRA
+data; all others use text/template. The data is the issues list: .Issues (Key, Summary, AssigneeEmail,
+QaOwnerEmail, LatestVersion, Comments, Rejections) and .Failures. last-comment passes each issue with
+only its last QA comment.
+
+Functions:
+  formatDate   comment date as 2006-01-02 15:04:05 in the --tz time zone
+  resultColor  CSS color of a result category: green, red, orange, blue or gray
+  jiraURL      JIRA link for an issue key, built from base_url
+  latest       last QA comment of an issue, or nil
+  inc          1-based index for range loops
+  summary      report summary for charts: .Issues, .Comments, .Failures, .Latest, .Outcomes and .Versions
+  colorize     text templates only: text in the terminal color of a result category
+
+Teams can name their templates in config.yaml and pass the name to --template:
+  templates:
+    mobile: ./templates/mobile.html.tmpl
 `
 
 	filePath := filepath.Join(outputDir, "jira-parser.md")
@@ -166,8 +335,10 @@ USAGE:
 COMMANDS:
    parse           Parse all QA comments for an issue
    last-comment    Get the last QA comment for an issue
-   export          Export all QA comments as JSON
+   export          Export all QA comments as JSON, HTML, CSV, TSV, XLSX, Markdown, Confluence wiki or JUnit XML
    parse-multiple  Parse QA comments for multiple tickets from tickets file or command line arguments
+   cache prune     Remove cached issues (all, or older than --older-than)
+   schema          Print the JSON Schema of exported JSON and --output json/yaml documents
    version         Print the version number of jira-parser
    docs            Generate CLI documentation
    tutorial        Interactive tutorial for jira-parser
@@ -175,15 +346,25 @@ COMMANDS:
 
 GLOBAL OPTIONS:
    --help, -h  show help
+   --timeout   abort the command after the given duration, e.g. 30s or 5m (default: no timeout)
+   --source    jira (default), or file:<dir> to parse exported XML/JSON issues offline
+   --no-cache  do not read or write the on-disk issue cache
+   --refresh   ignore cached issues and fetch them again
+   --tz        time zone for displayed dates and plain date filters, e.g. UTC or Europe/Moscow
+   --output    output format of parse, parse-multiple and last-comment: text, json, jsonl, yaml or table
 
 COMMAND SPECIFICS:
 
 parse command:
   Usage: jira-parser parse <issue-key>
   Flags:
-    -r, --result string     Filter comments by test result (e.g., Fixed, Not Fixed, etc.)
-    -d, --date-from string  Filter comments created after specified date (format: YYYY-MM-DD)
-    -t, --date-to string    Filter comments created before specified date (format: YYYY-MM-DD)
+        --filter string     Filter expression, e.g. 'result in (Fixed, "Partially Fixed") and version >= 5.2'
+    -r, --result string     Filter comments by test result (e.g., Fixed, Not Fixed) or result category (pass, fail, partial, blocked, unknown)
+    -d, --date-from string  Filter comments created at or after the date: YYYY-MM-DD, RFC3339, 7d/2w/12h ago, or last-sprint
+    -t, --date-to string    Filter comments created at or before the date (same formats as --date-from)
+        --min-version string Filter comments tested on this software version or later (e.g. 5.4, v5.4.0-rc1)
+        --max-version string Filter comments tested on this software version or earlier
+        --template string   Go template file or template name from config.yaml
 
 last-comment command:
  Usage: jira-parser last-comment <issue-key>
@@ -192,22 +373,38 @@ export command:
    Usage: jira-parser export [issue-key...]
    Flags:
      --pretty, -p        Pretty print JSON output
-     --format, -F        Output format (json or html) (default: "json")
+     --format, -F        Output format (json, html, csv, tsv, xlsx, markdown, confluence or junit) (default: "json")
+     --columns           CSV/TSV columns in output order, e.g. key,summary,result,comment
+     --latest-only       CSV/TSV: one row per issue with only the latest QA comment
+     --details           Markdown/Confluence: add all QA comments grouped by issue
+     --template          Go template file or template name from config.yaml; replaces --format
      --output-dir, -o    Output directory for exported files (default: "./QA_comments")
      --tickets-file, -f  Path to the YAML file containing the list of tickets (default: ./configs/tickets.yaml)
+     --jql               JQL query used to select tickets
+     --filter            Filter expression, e.g. 'result in (Fixed, "Partially Fixed") and version >= 5.2'
+     -r, -d, -t, --min-version, --max-version  Same filters as parse
 
 last-comment command:
    Usage: jira-parser last-comment [issue-key...]
    Flags:
+     --filter string         Filter expression; the last matching QA comment is shown
+     -r, -d, -t, --min-version, --max-version  Same filters as parse
      -f, --tickets-file      Path to the YAML file containing the list of tickets (default: ./configs/tickets.yaml)
+     --jql                   JQL query used to select tickets
+     --template              Go template file or template name from config.yaml
 
 parse-multiple command:
   Usage: jira-parser parse-multiple [tickets...]
    Flags:
-     -r, --result string     Filter comments by test result (e.g., Fixed, Not Fixed, etc.)
-     -d, --date-from string  Filter comments created after specified date (format: YYYY-MM-DD)
-     -t, --date-to string    Filter comments created before specified date (format: YYYY-MM-DD)
+         --filter string     Filter expression, e.g. 'result in (Fixed, "Partially Fixed") and version >= 5.2'
+     -r, --result string     Filter comments by test result (e.g., Fixed, Not Fixed) or result category (pass, fail, partial, blocked, unknown)
+     -d, --date-from string  Filter comments created at or after the date: YYYY-MM-DD, RFC3339, 7d/2w/12h ago, or last-sprint
+     -t, --date-to string    Filter comments created at or before the date (same formats as --date-from)
+         --min-version string Filter comments tested on this software version or later (e.g. 5.4, v5.4.0-rc1)
+         --max-version string Filter comments tested on this software version or earlier
      -f, --tickets-file      Path to the YAML file containing the list of tickets (default: ./configs/tickets.yaml)
+     --jql                   JQL query used to select tickets
+     --template              Go template file or template name from config.yaml
 
 docs command:
  Usage: jira-parser docs
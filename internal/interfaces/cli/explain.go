@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/rd2w/jira-parser/internal/application"
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+func NewExplainCommand() *cobra.Command {
+	var fromStdin bool
+
+	cmd := &cobra.Command{
+		Use:   "explain <issue-key> [comment-id]",
+		Short: "Explain why comments were or were not parsed as QA comments",
+		Long: `Explain how the comment parser handles the comments of an issue.
+For every comment it shows the text after JIRA markup removal, which QA indicators matched,
+which version, result and comment patterns fired or why each of them failed,
+and which result_normalization entry was applied.
+With a comment id only that comment is explained.
+With --stdin the comment body is read from standard input; an optional issue or project key
+selects the parser configured for that project. No JIRA connection is needed in this mode.
+Example: jira-parser explain TOS-30690
+Example: jira-parser explain TOS-30690 10042
+Example: pbpaste | jira-parser explain --stdin CLOUD`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if fromStdin {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.RangeArgs(1, 2)(cmd, args)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if fromStdin {
+				body, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					log.Fatalf("Failed to read comment from stdin: %v", err)
+				}
+
+				defaultParser, parserOpts, err := offlineCommentParsers()
+				if err != nil {
+					log.Fatalf("Error: %v", err)
+				}
+				// Разбор текста не обращается к репозиторию, поэтому подключение к JIRA не создается
+				service := application.NewCommentService(nil, defaultParser, parserOpts...)
+
+				key := ""
+				if len(args) > 0 {
+					key = args[0]
+				}
+				explanation, err := service.ExplainText(key, string(body))
+				if err != nil {
+					log.Fatalf("Error: %v", err)
+				}
+				printCommentExplanation(explanation)
+				return
+			}
+
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			service, err := createCommentService(ctx)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+
+			commentID := ""
+			if len(args) > 1 {
+				commentID = args[1]
+			}
+			explanations, err := service.ExplainCommentsWithContext(ctx, args[0], commentID)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+
+			if len(explanations) == 0 {
+				fmt.Printf("%s has no comments\n", args[0])
+				return
+			}
+			for _, explanation := range explanations {
+				printCommentExplanation(explanation)
+				fmt.Println(strings.Repeat("-", 50))
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&fromStdin, "stdin", false, "Read the comment body from standard input instead of JIRA")
+	return cmd
+}
+
+// printCommentExplanation выводит шаги разбора одного комментария
+func printCommentExplanation(explanation domain.CommentExplanation) {
+	if explanation.CommentID != "" {
+		fmt.Printf("Comment %s\n", explanation.CommentID)
+	} else {
+		fmt.Println("Comment")
+	}
+
+	fmt.Println("Normalized text:")
+	for _, line := range strings.Split(explanation.NormalizedText, "\n") {
+		fmt.Printf("  | %s\n", line)
+	}
+
+	fmt.Println("QA indicators:")
+	if len(explanation.Indicators) == 0 {
+		fmt.Println("  (none configured)")
+	}
+	for _, check := range explanation.Indicators {
+		if check.Match != "" {
+			fmt.Printf("  [x] %q (%s)\n", check.Indicator, check.Match)
+		} else {
+			fmt.Printf("  [ ] %q\n", check.Indicator)
+		}
+	}
+
+	if !explanation.IsQAComment {
+		fmt.Println("Verdict: not a QA comment, no indicator matched")
+		return
+	}
+
+	for _, field := range explanation.Fields {
+		printFieldExplanation(field)
+	}
+
+	switch {
+	case explanation.Rejection != "":
+		fmt.Printf("Verdict: rejected by the comment template: %s\n", explanation.Rejection)
+	case explanation.Comment == nil:
+		fmt.Println("Verdict: skipped, no version, result or comment found")
+	default:
+		fmt.Printf("Verdict: parsed as version %q, result %q\n", explanation.Comment.SoftwareVersion, explanation.Comment.TestResult)
+		printTestCaseResults(explanation.Comment.Results, "  ")
+	}
+}
+
+func printFieldExplanation(field domain.FieldExplanation) {
+	value := field.Value
+	if value == "" {
+		value = "(not found)"
+	}
+	if field.Source != "" {
+		fmt.Printf("%s: %s (from %s)\n", field.Name, value, field.Source)
+	} else {
+		fmt.Printf("%s: %s\n", field.Name, value)
+	}
+
+	for _, attempt := range field.Attempts {
+		if attempt.Failure == "" {
+			fmt.Printf("  [x] %s => %q\n", attempt.Pattern, attempt.Value)
+		} else {
+			fmt.Printf("  [ ] %s: %s\n", attempt.Pattern, attempt.Failure)
+		}
+	}
+	if field.Normalization != "" {
+		fmt.Printf("  normalized: %s\n", field.Normalization)
+	}
+}
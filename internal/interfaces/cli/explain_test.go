@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintCommentExplanation(t *testing.T) {
+	p, err := parser.NewRegexParser(domain.ParsingConfig{
+		QAIndicators:        []string{"qa comment", "tested on"},
+		VersionPatterns:     []string{`(?i)Tested on (v?[\d.]+)`},
+		ResultPatterns:      []string{`(?i)Status:\s*(\S+)`, `(?i)Result:\s*(\S+)`},
+		ResultNormalization: map[string]string{"passed": "Fixed"},
+	})
+	assert.NoError(t, err)
+
+	explanation := p.ExplainComment(domain.RawComment{ID: "10042", Body: "*Tested on* v1.2.3\nResult: Passed"})
+
+	// Захватываем вывод
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printCommentExplanation(explanation)
+
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+	os.Stdout = old
+
+	output := string(out)
+
+	assert.Contains(t, output, "Comment 10042\n")
+	assert.Contains(t, output, "  | Tested on v1.2.3\n  | Result: Passed\n")
+	assert.Contains(t, output, "  [ ] \"qa comment\"\n")
+	assert.Contains(t, output, "  [x] \"tested on\" (substring)\n")
+	assert.Contains(t, output, "version: v1.2.3 (from version_patterns)\n")
+	assert.Contains(t, output, "  [ ] (?i)Status:\\s*(\\S+): no match\n")
+	assert.Contains(t, output, "  [x] (?i)Result:\\s*(\\S+) => \"Passed\"\n")
+	assert.Contains(t, output, "  normalized: Passed -> Fixed\n")
+	assert.Contains(t, output, "comment: (not found)\n")
+	assert.Contains(t, output, "Verdict: parsed as version \"v1.2.3\", result \"Fixed\"\n")
+}
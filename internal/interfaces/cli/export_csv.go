@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// csvColumn - столбец CSV/TSV экспорта. comment равен nil для тикета без QA комментариев.
+type csvColumn struct {
+	name  string
+	value func(issue domain.Issue, comment *domain.QAComment) string
+}
+
+// csvColumns - столбцы, доступные в --columns, в порядке вывода в подсказке
+var csvColumns = []csvColumn{
+	{"key", func(issue domain.Issue, _ *domain.QAComment) string { return issue.Key }},
+	{"summary", func(issue domain.Issue, _ *domain.QAComment) string { return issue.Summary }},
+	{"assignee", func(issue domain.Issue, _ *domain.QAComment) string { return issue.AssigneeEmail }},
+	{"qa_owner", func(issue domain.Issue, _ *domain.QAComment) string { return issue.QaOwnerEmail }},
+	{"latest_version", func(issue domain.Issue, _ *domain.QAComment) string { return issue.LatestVersion.String() }},
+	{"created", commentValue(func(c *domain.QAComment) string { return formatTime(c.Created) })},
+	{"updated", commentValue(func(c *domain.QAComment) string { return formatTime(c.Updated) })},
+	{"author", commentValue(func(c *domain.QAComment) string { return c.AuthorEmail })},
+	{"version", commentValue(func(c *domain.QAComment) string { return c.SoftwareVersion })},
+	{"result", commentValue(func(c *domain.QAComment) string { return string(c.TestResult) })},
+	{"category", commentValue(func(c *domain.QAComment) string { return string(c.Category) })},
+	{"comment", commentValue(func(c *domain.QAComment) string { return c.Comment })},
+	{"scenarios", commentValue(func(c *domain.QAComment) string { return formatScenarios(c.Results) })},
+}
+
+// defaultCSVColumns - столбцы CSV/TSV экспорта без флага --columns
+const defaultCSVColumns = "key,summary,assignee,qa_owner,created,author,version,result,comment"
+
+// commentValue оставляет столбец пустым для тикета без QA комментариев
+func commentValue(value func(comment *domain.QAComment) string) func(domain.Issue, *domain.QAComment) string {
+	return func(_ domain.Issue, comment *domain.QAComment) string {
+		if comment == nil {
+			return ""
+		}
+		return value(comment)
+	}
+}
+
+// formatScenarios записывает результаты сценариев в одну ячейку: "Login: Fixed; Logout: Not Fixed (session is kept)"
+func formatScenarios(results []domain.TestCaseResult) string {
+	parts := make([]string, 0, len(results))
+	for _, result := range results {
+		part := fmt.Sprintf("%s: %s", result.Scenario, result.Result)
+		if result.Note != "" {
+			part += fmt.Sprintf(" (%s)", result.Note)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// parseCSVColumns разбирает значение --columns: имена столбцов через запятую в нужном порядке
+func parseCSVColumns(spec string) ([]csvColumn, error) {
+	var columns []csvColumn
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		column, ok := findCSVColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q, available columns: %s", name, csvColumnNames())
+		}
+		columns = append(columns, column)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns selected, available columns: %s", csvColumnNames())
+	}
+	return columns, nil
+}
+
+func findCSVColumn(name string) (csvColumn, bool) {
+	for _, column := range csvColumns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return csvColumn{}, false
+}
+
+func csvColumnNames() string {
+	names := make([]string, len(csvColumns))
+	for i, column := range csvColumns {
+		names[i] = column.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// writeCSV записывает строку заголовков и по строке на каждый QA комментарий; столбцы тикета
+// повторяются в каждой строке. latestOnly оставляет одну строку на тикет с последним комментарием.
+// Тикет без QA комментариев выводится одной строкой с пустыми столбцами комментария.
+// Поля с разделителем, кавычками или переводами строк берутся в кавычки по RFC 4180.
+func writeCSV(w io.Writer, issuesList *domain.IssuesList, columns []csvColumn, comma rune, latestOnly bool) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	writer.UseCRLF = comma == ','
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	err := forEachCommentRow(issuesList, latestOnly, func(issue domain.Issue, comment *domain.QAComment) error {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = column.value(issue, comment)
+		}
+		return writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// forEachCommentRow вызывает row для каждого QA комментария; тикет без QA комментариев дает одну строку
+// с comment == nil. latestOnly оставляет только последний комментарий тикета.
+func forEachCommentRow(issuesList *domain.IssuesList, latestOnly bool, row func(issue domain.Issue, comment *domain.QAComment) error) error {
+	for _, issue := range issuesList.Issues {
+		comments := issue.Comments
+		if latestOnly && len(comments) > 0 {
+			comments = comments[len(comments)-1:]
+		}
+		if len(comments) == 0 {
+			if err := row(issue, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		for i := range comments {
+			if err := row(issue, &comments[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// exportToCSV записывает CSV (comma == ',') или TSV (comma == '\t') файл
+func exportToCSV(issuesList *domain.IssuesList, fileName string, comma rune, columns []csvColumn, latestOnly bool) {
+	fileNameWithExt := fileName + ".csv"
+	if comma == '\t' {
+		fileNameWithExt = fileName + ".tsv"
+	}
+
+	file, err := os.Create(fileNameWithExt)
+	if err != nil {
+		log.Fatalf("Error creating export file: %v", err)
+	}
+	if err := writeCSV(file, issuesList, columns, comma, latestOnly); err != nil {
+		_ = file.Close()
+		log.Fatalf("Error writing export file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		log.Fatalf("Error writing export file: %v", err)
+	}
+
+	fmt.Printf("Exported results to %s\n", fileNameWithExt)
+	// В таблице нет места для ошибок, поэтому необработанные тикеты выводятся в консоль
+	printFailures(issuesList.Failures)
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func csvTestIssues(t *testing.T) *domain.IssuesList {
+	return &domain.IssuesList{
+		Issues: []domain.Issue{
+			{
+				Key:           "TOS-1",
+				Summary:       `Login fails with "special" chars, sometimes`,
+				AssigneeEmail: "dev@example.com",
+				QaOwnerEmail:  "qa@example.com",
+				LatestVersion: mustVersion(t, "1.0.1"),
+				Comments: []domain.QAComment{
+					{
+						SoftwareVersion: "v1.0.0",
+						TestResult:      domain.OutcomeNotFixed,
+						Category:        domain.CategoryFail,
+						Comment:         "Still broken:\nstep 3 fails",
+						AuthorEmail:     "qa@example.com",
+						Created:         time.Date(2025, 8, 12, 16, 35, 38, 0, time.UTC),
+						Results: []domain.TestCaseResult{
+							{Scenario: "Login", Result: domain.OutcomeFixed},
+							{Scenario: "Logout", Result: domain.OutcomeNotFixed, Note: "session is kept"},
+						},
+					},
+					{
+						SoftwareVersion: "v1.0.1",
+						TestResult:      domain.OutcomeFixed,
+						Category:        domain.CategoryPass,
+						AuthorEmail:     "qa@example.com",
+						Created:         time.Date(2025, 8, 14, 9, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+			{Key: "TOS-2", Summary: "No QA yet"},
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	columns, err := parseCSVColumns(defaultCSVColumns)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeCSV(&buf, csvTestIssues(t), columns, ',', false))
+
+	expected := "key,summary,assignee,qa_owner,created,author,version,result,comment\r\n" +
+		`TOS-1,"Login fails with ""special"" chars, sometimes",dev@example.com,qa@example.com,2025-08-12 16:35:38,qa@example.com,v1.0.0,Not Fixed,"Still broken:` + "\r\n" + `step 3 fails"` + "\r\n" +
+		`TOS-1,"Login fails with ""special"" chars, sometimes",dev@example.com,qa@example.com,2025-08-14 09:00:00,qa@example.com,v1.0.1,Fixed,` + "\r\n" +
+		"TOS-2,No QA yet,,,,,,,\r\n"
+	assert.Equal(t, expected, buf.String())
+
+	// Многострочный комментарий читается обратно как одно поле
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 4)
+	assert.Equal(t, "Still broken:\nstep 3 fails", records[1][8])
+}
+
+func TestWriteCSVLatestOnlyTSV(t *testing.T) {
+	columns, err := parseCSVColumns(" KEY, result ,latest_version,scenarios")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeCSV(&buf, csvTestIssues(t), columns, '\t', true))
+	assert.Equal(t, "key\tresult\tlatest_version\tscenarios\nTOS-1\tFixed\t1.0.1\t\nTOS-2\t\t\t\n", buf.String())
+
+	columns, err = parseCSVColumns("key,scenarios")
+	assert.NoError(t, err)
+	buf.Reset()
+	assert.NoError(t, writeCSV(&buf, csvTestIssues(t), columns, '\t', false))
+	assert.Contains(t, buf.String(), "TOS-1\tLogin: Fixed; Logout: Not Fixed (session is kept)\n")
+}
+
+func TestParseCSVColumnsErrors(t *testing.T) {
+	_, err := parseCSVColumns("key,status")
+	assert.ErrorContains(t, err, `unknown column "status", available columns: key, summary, assignee, qa_owner`)
+
+	_, err = parseCSVColumns(" , ")
+	assert.ErrorContains(t, err, "no columns selected")
+}
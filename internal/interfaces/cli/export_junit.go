@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// Элементы JUnit XML в том виде, в котором его читают Jenkins (JUnit plugin) и GitLab (artifacts:reports:junit)
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// buildJUnitReport строит отчет: testsuite на тикет с одним testcase по последнему QA комментарию.
+// Категории fail и partial дают failure, blocked и unknown - skipped, тикет без QA комментариев
+// тоже пропускается. Вся история комментариев записывается в system-out, а тикеты,
+// которые не удалось обработать, попадают в отчет как error.
+func buildJUnitReport(issuesList *domain.IssuesList, baseURL string) junitTestSuites {
+	report := junitTestSuites{Name: "QA verdicts"}
+
+	for _, issue := range issuesList.Issues {
+		suite := junitTestSuite{Name: issue.Key, Tests: 1, Properties: junitProperties(issue, baseURL)}
+		testCase := junitTestCase{Name: issue.Key, ClassName: issue.Key, Time: "0", SystemOut: junitHistory(issue)}
+		if issue.Summary != "" {
+			testCase.Name = issue.Summary
+		}
+
+		if n := len(issue.Comments); n == 0 {
+			testCase.Skipped = &junitMessage{Message: "No QA comments"}
+		} else {
+			latest := issue.Comments[n-1]
+			if !latest.Created.IsZero() {
+				suite.Timestamp = latest.Created.Format(time.RFC3339)
+			}
+			verdict := &junitMessage{Message: junitVerdict(latest), Type: string(latest.TestResult), Text: latest.Comment}
+			switch latest.Category {
+			case domain.CategoryPass:
+				// Пройденный testcase не содержит вложенных элементов
+			case domain.CategoryFail, domain.CategoryPartial:
+				testCase.Failure = verdict
+				suite.Failures = 1
+			default:
+				testCase.Skipped = verdict
+			}
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped = 1
+		}
+
+		suite.TestCases = []junitTestCase{testCase}
+		report.Suites = append(report.Suites, suite)
+	}
+
+	for _, failure := range issuesList.Failures {
+		report.Suites = append(report.Suites, junitTestSuite{
+			Name:   failure.Key,
+			Tests:  1,
+			Errors: 1,
+			TestCases: []junitTestCase{{
+				Name:      failure.Key,
+				ClassName: failure.Key,
+				Time:      "0",
+				Error:     &junitMessage{Message: "Failed to process ticket", Text: failure.Error},
+			}},
+		})
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+	}
+	return report
+}
+
+// junitVerdict кратко описывает последний вердикт: "Not Fixed on v1.0.0 by qa@example.com"
+func junitVerdict(comment domain.QAComment) string {
+	verdict := string(comment.TestResult)
+	if verdict == "" {
+		verdict = "No result"
+	}
+	if comment.SoftwareVersion != "" {
+		verdict += " on " + comment.SoftwareVersion
+	}
+	if comment.AuthorEmail != "" {
+		verdict += " by " + comment.AuthorEmail
+	}
+	return verdict
+}
+
+// junitProperties записывает поля тикета в properties; пустые поля пропускаются
+func junitProperties(issue domain.Issue, baseURL string) []junitProperty {
+	var properties []junitProperty
+	add := func(name, value string) {
+		if value != "" {
+			properties = append(properties, junitProperty{Name: name, Value: value})
+		}
+	}
+	add("summary", issue.Summary)
+	add("assignee", issue.AssigneeEmail)
+	add("qa_owner", issue.QaOwnerEmail)
+	add("latest_version", issue.LatestVersion.String())
+	add("url", jiraBrowseURL(baseURL, issue.Key))
+	return properties
+}
+
+// junitHistory выводит все QA комментарии тикета в том же виде, что и команда parse, без цветов
+func junitHistory(issue domain.Issue) string {
+	var b strings.Builder
+	if issue.CommentsIncomplete {
+		b.WriteString("Warning: not all comments could be loaded, QA comments may be incomplete\n")
+	}
+	fmt.Fprintf(&b, "Found %d QA comments:\n", len(issue.Comments))
+	for i, comment := range issue.Comments {
+		b.WriteString("\n")
+		createdTime := formatTime(comment.Created)
+		switch {
+		case createdTime != "" && comment.AuthorEmail != "":
+			fmt.Fprintf(&b, "Comment #%d (%s) from %s:\n", i+1, createdTime, comment.AuthorEmail)
+		case createdTime != "":
+			fmt.Fprintf(&b, "Comment #%d (%s):\n", i+1, createdTime)
+		case comment.AuthorEmail != "":
+			fmt.Fprintf(&b, "Comment #%d from %s:\n", i+1, comment.AuthorEmail)
+		default:
+			fmt.Fprintf(&b, "Comment #%d:\n", i+1)
+		}
+		fmt.Fprintf(&b, "  Version: %s\n", comment.SoftwareVersion)
+		fmt.Fprintf(&b, "  Result: %s\n", comment.TestResult)
+		if comment.Comment != "" {
+			fmt.Fprintf(&b, "  Info: %s\n", comment.Comment)
+		}
+		if len(comment.Results) > 0 {
+			b.WriteString("  Scenarios:\n")
+			for _, result := range comment.Results {
+				fmt.Fprintf(&b, "    - %s: %s", result.Scenario, result.Result)
+				if result.Note != "" {
+					fmt.Fprintf(&b, " (%s)", result.Note)
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+	if len(issue.Rejections) > 0 {
+		fmt.Fprintf(&b, "\nRejected %d QA comments:\n", len(issue.Rejections))
+		for _, rejection := range issue.Rejections {
+			fmt.Fprintf(&b, "  Comment %s: %s\n", rejection.CommentID, rejection.Reason)
+		}
+	}
+	return b.String()
+}
+
+// writeJUnit записывает отчет с XML заголовком
+func writeJUnit(w io.Writer, issuesList *domain.IssuesList, baseURL string) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(buildJUnitReport(issuesList, baseURL)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func exportToJUnit(issuesList *domain.IssuesList, fileName, baseURL string) {
+	fileNameWithExt := fileName + ".xml"
+	file, err := os.Create(fileNameWithExt)
+	if err != nil {
+		log.Fatalf("Error creating JUnit file: %v", err)
+	}
+	if err := writeJUnit(file, issuesList, baseURL); err != nil {
+		_ = file.Close()
+		log.Fatalf("Error writing JUnit file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		log.Fatalf("Error writing JUnit file: %v", err)
+	}
+
+	fmt.Printf("Exported results to %s\n", fileNameWithExt)
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildJUnitReport(t *testing.T) {
+	issuesList := csvTestIssues(t)
+	issuesList.Issues = append(issuesList.Issues, domain.Issue{
+		Key:      "TOS-3",
+		Summary:  "Modem does not start",
+		Comments: []domain.QAComment{{SoftwareVersion: "v2.0", TestResult: domain.OutcomeNotFixed, Category: domain.CategoryFail, Comment: "Crash on boot"}},
+	}, domain.Issue{
+		Key:      "TOS-4",
+		Comments: []domain.QAComment{{TestResult: domain.OutcomeCouldNotTest, Category: domain.CategoryBlocked, AuthorEmail: "qa@example.com"}},
+	})
+	issuesList.Failures = []domain.TicketFailure{{Key: "TOS-9", Error: "issue does not exist"}}
+
+	report := buildJUnitReport(issuesList, "https://jira.example.com")
+	assert.Equal(t, 5, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 1, report.Errors)
+	assert.Len(t, report.Suites, 5)
+
+	// Последний комментарий TOS-1 - Fixed, поэтому testcase проходит, а история есть в system-out
+	fixed := report.Suites[0]
+	assert.Equal(t, "TOS-1", fixed.Name)
+	assert.Equal(t, "2025-08-14T09:00:00Z", fixed.Timestamp)
+	assert.Contains(t, fixed.Properties, junitProperty{Name: "url", Value: "https://jira.example.com/browse/TOS-1"})
+	testCase := fixed.TestCases[0]
+	assert.Equal(t, "TOS-1", testCase.ClassName)
+	assert.Nil(t, testCase.Failure)
+	assert.Nil(t, testCase.Skipped)
+	assert.Contains(t, testCase.SystemOut, "Found 2 QA comments:\n\nComment #1 (2025-08-12 16:35:38) from qa@example.com:\n  Version: v1.0.0\n  Result: Not Fixed\n")
+	assert.Contains(t, testCase.SystemOut, "    - Logout: Not Fixed (session is kept)\n")
+	assert.Contains(t, testCase.SystemOut, "Comment #2 (2025-08-14 09:00:00) from qa@example.com:\n  Version: v1.0.1\n  Result: Fixed\n")
+
+	assert.Equal(t, &junitMessage{Message: "No QA comments"}, report.Suites[1].TestCases[0].Skipped)
+	assert.Equal(t, &junitMessage{Message: "Not Fixed on v2.0", Type: "Not Fixed", Text: "Crash on boot"}, report.Suites[2].TestCases[0].Failure)
+	assert.Equal(t, 1, report.Suites[2].Failures)
+	assert.Equal(t, &junitMessage{Message: "Could not test by qa@example.com", Type: "Could not test"}, report.Suites[3].TestCases[0].Skipped)
+	assert.Equal(t, &junitMessage{Message: "Failed to process ticket", Text: "issue does not exist"}, report.Suites[4].TestCases[0].Error)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeJUnit(&buf, csvTestIssues(t), ""))
+
+	out := buf.String()
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte(xml.Header)))
+	assert.Contains(t, out, `<testsuites name="QA verdicts" tests="2" failures="0" errors="0" skipped="1">`)
+	assert.Contains(t, out, `<testcase name="Login fails with &#34;special&#34; chars, sometimes" classname="TOS-1" time="0">`)
+	assert.Contains(t, out, `<skipped message="No QA comments"></skipped>`)
+	assert.NotContains(t, out, `name="url"`)
+
+	// Отчет читается обратно как корректный XML
+	var parsed junitTestSuites
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &parsed))
+	assert.Equal(t, buildJUnitReport(csvTestIssues(t), "").Suites, parsed.Suites)
+}
@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// markup - синтаксис текстового отчета: Markdown (GitHub, release notes) или Confluence wiki
+type markup interface {
+	heading(level int, text string) string
+	link(text, url string) string
+	strong(text string) string
+	// text экранирует разметку в произвольном тексте; переводы строк не разрывают ячейку таблицы
+	text(s string) string
+	result(outcome domain.TestOutcome, category domain.OutcomeCategory) string
+	listItem(text string) string
+	table(header []string, rows [][]string) string
+}
+
+// markdownMarkup - GitHub Flavored Markdown
+type markdownMarkup struct{}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `<`, `&lt;`, `>`, `&gt;`, `|`, `\|`,
+	"\r\n", "<br>", "\n", "<br>",
+)
+
+func (markdownMarkup) heading(level int, text string) string {
+	return strings.Repeat("#", level) + " " + text + "\n\n"
+}
+
+func (markdownMarkup) link(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+func (markdownMarkup) strong(text string) string { return "**" + text + "**" }
+
+func (markdownMarkup) text(s string) string { return markdownEscaper.Replace(s) }
+
+func (m markdownMarkup) result(outcome domain.TestOutcome, _ domain.OutcomeCategory) string {
+	return m.text(string(outcome))
+}
+
+func (markdownMarkup) listItem(text string) string { return "- " + text + "\n" }
+
+func (markdownMarkup) table(header []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range rows {
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// confluenceMarkup - Confluence wiki markup (вставка через Insert > Markup)
+type confluenceMarkup struct{}
+
+var confluenceEscaper = strings.NewReplacer(
+	`\`, `\\`, `[`, `\[`, `]`, `\]`, `{`, `\{`, `}`, `\}`, `|`, `\|`, `*`, `\*`, `_`, `\_`,
+	`^`, `\^`, `~`, `\~`, `+`, `\+`, `!`, `\!`,
+	"\r\n", ` \\ `, "\n", ` \\ `,
+)
+
+// resultColors - цвета категорий результатов HTML отчета; их же используют Confluence и resultColor в шаблонах
+var resultColors = map[domain.OutcomeCategory]string{
+	domain.CategoryPass:    "green",
+	domain.CategoryFail:    "red",
+	domain.CategoryPartial: "orange",
+	domain.CategoryBlocked: "blue",
+	domain.CategoryUnknown: "gray",
+}
+
+func (confluenceMarkup) heading(level int, text string) string {
+	return fmt.Sprintf("h%d. %s\n\n", level, text)
+}
+
+func (confluenceMarkup) link(text, url string) string {
+	if url == "" {
+		return text
+	}
+	return fmt.Sprintf("[%s|%s]", text, url)
+}
+
+func (confluenceMarkup) strong(text string) string { return "*" + text + "*" }
+
+func (confluenceMarkup) text(s string) string { return confluenceEscaper.Replace(s) }
+
+func (c confluenceMarkup) result(outcome domain.TestOutcome, category domain.OutcomeCategory) string {
+	if outcome == "" {
+		return ""
+	}
+	if category == "" {
+		category = domain.CategoryUnknown
+	}
+	return fmt.Sprintf("{color:%s}%s{color}", resultColors[category], c.text(string(outcome)))
+}
+
+func (confluenceMarkup) listItem(text string) string { return "* " + text + "\n" }
+
+func (confluenceMarkup) table(header []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString("||" + strings.Join(header, "||") + "||\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			// Пустая ячейка "||" была бы прочитана как ячейка заголовка
+			if cell == "" {
+				cell = " "
+			}
+			cells[i] = cell
+		}
+		b.WriteString("|" + strings.Join(cells, "|") + "|\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// jiraBrowseURL возвращает ссылку на тикет в JIRA; без base_url (офлайн-режим) ссылки нет
+func jiraBrowseURL(baseURL, key string) string {
+	if baseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(baseURL, "/") + "/browse/" + key
+}
+
+// markupReport собирает текстовый отчет: таблицу статусов, подробный раздел с QA комментариями
+// и список тикетов, которые не удалось обработать
+type markupReport struct {
+	m       markup
+	baseURL string
+	out     strings.Builder
+}
+
+// statusTable выводит строку на тикет с последним вердиктом QA
+func (r *markupReport) statusTable(issues []domain.Issue) {
+	rows := make([][]string, 0, len(issues))
+	for _, issue := range issues {
+		var result string
+		if n := len(issue.Comments); n > 0 {
+			latest := issue.Comments[n-1]
+			result = r.m.result(latest.TestResult, latest.Category)
+		}
+		rows = append(rows, []string{
+			r.m.link(r.m.text(issue.Key), jiraBrowseURL(r.baseURL, issue.Key)),
+			r.m.text(issue.Summary),
+			r.m.text(issue.LatestVersion.String()),
+			result,
+			r.m.text(issue.QaOwnerEmail),
+		})
+	}
+	r.out.WriteString(r.m.table([]string{"Issue", "Summary", "Latest Version", "Latest Result", "QA Owner"}, rows))
+}
+
+// issues выводит подробный раздел: заголовок тикета, его QA комментарии по порядку,
+// затем отклоненные комментарии
+func (r *markupReport) issues(issues []domain.Issue) {
+	for _, issue := range issues {
+		r.issueHeader(issue)
+		for j, comment := range issue.Comments {
+			r.comment(j+1, comment)
+		}
+		if len(issue.Rejections) > 0 {
+			r.rejections(issue.Rejections)
+		}
+	}
+}
+
+func (r *markupReport) issueHeader(issue domain.Issue) {
+	title := r.m.link(r.m.text(issue.Key), jiraBrowseURL(r.baseURL, issue.Key))
+	if issue.Summary != "" {
+		title += ": " + r.m.text(issue.Summary)
+	}
+	r.out.WriteString(r.m.heading(3, title))
+
+	var info string
+	if issue.AssigneeEmail != "" {
+		info += r.m.listItem(r.m.strong("Assigned:") + " " + r.m.text(issue.AssigneeEmail))
+	}
+	if issue.QaOwnerEmail != "" {
+		info += r.m.listItem(r.m.strong("QA Owner:") + " " + r.m.text(issue.QaOwnerEmail))
+	}
+	if !issue.LatestVersion.IsZero() {
+		info += r.m.listItem(r.m.strong("Latest version tested:") + " " + r.m.text(issue.LatestVersion.String()))
+	}
+	if info != "" {
+		r.out.WriteString(info + "\n")
+	}
+
+	if issue.CommentsIncomplete {
+		r.out.WriteString(r.m.strong("Warning:") + " not all comments could be loaded, QA comments may be incomplete\n\n")
+	}
+	r.out.WriteString(fmt.Sprintf("Found %d QA comments:\n\n", len(issue.Comments)))
+}
+
+func (r *markupReport) comment(number int, comment domain.QAComment) {
+	header := fmt.Sprintf("Comment #%d", number)
+	if created := formatTime(comment.Created); created != "" {
+		header += fmt.Sprintf(" (%s)", created)
+	}
+	if comment.AuthorEmail != "" {
+		header += " from " + r.m.text(comment.AuthorEmail)
+	}
+	r.out.WriteString(r.m.heading(4, header))
+
+	r.out.WriteString(r.m.listItem(r.m.strong("Version:") + " " + r.m.text(comment.SoftwareVersion)))
+	r.out.WriteString(r.m.listItem(r.m.strong("Result:") + " " + r.m.result(comment.TestResult, comment.Category)))
+	if comment.Comment != "" {
+		r.out.WriteString(r.m.listItem(r.m.strong("Note:") + " " + r.m.text(comment.Comment)))
+	}
+	r.out.WriteString("\n")
+
+	if len(comment.Results) > 0 {
+		rows := make([][]string, len(comment.Results))
+		for i, result := range comment.Results {
+			rows[i] = []string{r.m.text(result.Scenario), r.m.result(result.Result, result.Category), r.m.text(result.Note)}
+		}
+		r.out.WriteString(r.m.table([]string{"Scenario", "Result", "Note"}, rows))
+	}
+}
+
+func (r *markupReport) rejections(rejections []domain.CommentRejection) {
+	r.out.WriteString(r.m.strong(fmt.Sprintf("Rejected %d QA comments:", len(rejections))) + "\n\n")
+	for _, rejection := range rejections {
+		r.out.WriteString(r.m.listItem(r.m.text(rejection.CommentID) + ": " + r.m.text(rejection.Reason)))
+	}
+	r.out.WriteString("\n")
+}
+
+func (r *markupReport) failures(failures []domain.TicketFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	r.out.WriteString(r.m.heading(2, fmt.Sprintf("Failed to process %d tickets", len(failures))))
+	for _, failure := range failures {
+		r.out.WriteString(r.m.listItem(r.m.text(failure.Key) + ": " + r.m.text(failure.Error)))
+	}
+	r.out.WriteString("\n")
+}
+
+// generateMarkupReport строит отчет для вставки в release notes или страницу Confluence.
+// Ключи тикетов ссылаются на JIRA (baseURL/browse/KEY); details добавляет все QA комментарии,
+// сгруппированные по тикетам.
+func generateMarkupReport(issuesList *domain.IssuesList, m markup, baseURL string, details bool) string {
+	r := &markupReport{m: m, baseURL: baseURL}
+	r.out.WriteString(m.heading(1, "QA Status Report"))
+	r.statusTable(issuesList.Issues)
+	if details {
+		r.out.WriteString(m.heading(2, "QA Comments"))
+		r.issues(issuesList.Issues)
+	}
+	r.failures(issuesList.Failures)
+	return strings.TrimRight(r.out.String(), "\n") + "\n"
+}
+
+// exportToMarkup записывает Markdown (.md) или Confluence wiki (.wiki) отчет
+func exportToMarkup(issuesList *domain.IssuesList, fileName string, m markup, ext, baseURL string, details bool) {
+	fileNameWithExt := fileName + ext
+	err := os.WriteFile(fileNameWithExt, []byte(generateMarkupReport(issuesList, m, baseURL, details)), 0644)
+	if err != nil {
+		log.Fatalf("Error writing export file: %v", err)
+	}
+
+	fmt.Printf("Exported results to %s\n", fileNameWithExt)
+}
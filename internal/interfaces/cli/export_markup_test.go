@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateMarkdownReport(t *testing.T) {
+	issuesList := csvTestIssues(t)
+	issuesList.Failures = []domain.TicketFailure{{Key: "TOS-9", Error: "issue does not exist"}}
+
+	report := generateMarkupReport(issuesList, markdownMarkup{}, "https://jira.example.com/", false)
+	assert.Equal(t, `# QA Status Report
+
+| Issue | Summary | Latest Version | Latest Result | QA Owner |
+| --- | --- | --- | --- | --- |
+| [TOS-1](https://jira.example.com/browse/TOS-1) | Login fails with "special" chars, sometimes | 1.0.1 | Fixed | qa@example.com |
+| [TOS-2](https://jira.example.com/browse/TOS-2) | No QA yet |  |  |  |
+
+## Failed to process 1 tickets
+
+- TOS-9: issue does not exist
+`, report)
+
+	details := generateMarkupReport(issuesList, markdownMarkup{}, "", true)
+	assert.Contains(t, details, "| TOS-1 | Login fails")
+	assert.Contains(t, details, "## QA Comments\n\n### TOS-1: Login fails")
+	assert.Contains(t, details, "#### Comment #1 (2025-08-12 16:35:38) from qa@example.com\n\n")
+	assert.Contains(t, details, "- **Note:** Still broken:<br>step 3 fails\n")
+	assert.Contains(t, details, "| Logout | Not Fixed | session is kept |\n")
+	assert.Contains(t, details, "### TOS-2: No QA yet\n\nFound 0 QA comments:")
+}
+
+func TestGenerateConfluenceReport(t *testing.T) {
+	issuesList := csvTestIssues(t)
+	issuesList.Issues[0].Summary = "[Modem] Replace {vendor} property | RIL"
+	issuesList.Issues[0].Rejections = []domain.CommentRejection{{CommentID: "10042", Reason: "missing Result"}}
+
+	report := generateMarkupReport(issuesList, confluenceMarkup{}, "https://jira.example.com", true)
+	assert.Contains(t, report, "h1. QA Status Report\n\n||Issue||Summary||Latest Version||Latest Result||QA Owner||\n")
+	assert.Contains(t, report, `|[TOS-1|https://jira.example.com/browse/TOS-1]|\[Modem\] Replace \{vendor\} property \| RIL|1.0.1|{color:green}Fixed{color}|qa@example.com|`)
+	// Пустые ячейки не должны превращаться в ячейки заголовка
+	assert.Contains(t, report, "|[TOS-2|https://jira.example.com/browse/TOS-2]|No QA yet| | | |\n")
+	assert.Contains(t, report, "* *Result:* {color:red}Not Fixed{color}\n")
+	assert.Contains(t, report, `* *Note:* Still broken: \\ step 3 fails`)
+	assert.Contains(t, report, "||Scenario||Result||Note||\n|Login|{color:gray}Fixed{color}| |\n")
+	assert.Contains(t, report, "*Rejected 1 QA comments:*\n\n* 10042: missing Result\n")
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"sort"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+)
+
+// Подписи сводки для комментариев без версии или результата
+const (
+	summaryNoVersion  = "(no version)"
+	summaryNoResult   = "(no result)"
+	summaryNoComments = "No QA comments"
+)
+
+// summaryCount - число тикетов или комментариев с одним результатом и его доля в процентах
+type summaryCount struct {
+	Label    string
+	Category domain.OutcomeCategory
+	Count    int
+	Percent  float64
+}
+
+// summaryVersion - строка сводки по версии: число комментариев по результатам в порядке reportSummary.Outcomes
+type summaryVersion struct {
+	Label   string
+	Version domain.Version
+	Counts  []summaryCount
+	Total   int
+}
+
+// reportSummary - сводка для диаграмм HTML отчета и листа Summary книги XLSX
+type reportSummary struct {
+	Issues   int
+	Comments int
+	Failures int
+	// Latest - последние вердикты тикетов по категориям; тикеты без QA комментариев считаются отдельно
+	Latest []summaryCount
+	// Outcomes - результаты всех комментариев в порядке категорий pass, fail, partial, blocked, unknown
+	Outcomes []summaryCount
+	// Versions - версии по возрастанию: нераспознанные версии после распознанных, комментарии без версии в конце
+	Versions []summaryVersion
+}
+
+// buildReportSummary считает результаты QA комментариев по тикетам и версиям
+func buildReportSummary(issuesList *domain.IssuesList) reportSummary {
+	summary := reportSummary{Issues: len(issuesList.Issues), Failures: len(issuesList.Failures)}
+
+	latest := make(map[domain.OutcomeCategory]int)
+	noComments := 0
+	versions := make(map[string]*summaryVersion)
+	versionCounts := make(map[string]map[domain.TestOutcome]int)
+	outcomeCategory := make(map[domain.TestOutcome]domain.OutcomeCategory)
+	outcomeCounts := make(map[domain.TestOutcome]int)
+	var outcomes []domain.TestOutcome
+
+	for _, issue := range issuesList.Issues {
+		if comment := latestComment(issue); comment != nil {
+			latest[categoryOrUnknown(comment.Category)]++
+		} else {
+			noComments++
+		}
+
+		for _, comment := range issue.Comments {
+			label := comment.Version.String()
+			if label == "" {
+				label = comment.SoftwareVersion
+			}
+			if label == "" {
+				label = summaryNoVersion
+			}
+			row, ok := versions[label]
+			if !ok {
+				row = &summaryVersion{Label: label, Version: comment.Version}
+				versions[label] = row
+				versionCounts[label] = make(map[domain.TestOutcome]int)
+			}
+
+			outcome := comment.TestResult
+			if outcome == "" {
+				outcome = summaryNoResult
+			}
+			if _, seen := outcomeCategory[outcome]; !seen {
+				outcomeCategory[outcome] = categoryOrUnknown(comment.Category)
+				outcomes = append(outcomes, outcome)
+			}
+			versionCounts[label][outcome]++
+			outcomeCounts[outcome]++
+			row.Total++
+			summary.Comments++
+		}
+	}
+
+	for _, category := range domain.OutcomeCategories {
+		if latest[category] > 0 {
+			summary.Latest = append(summary.Latest, newSummaryCount(string(category), category, latest[category], summary.Issues))
+		}
+	}
+	if noComments > 0 {
+		summary.Latest = append(summary.Latest, newSummaryCount(summaryNoComments, domain.CategoryUnknown, noComments, summary.Issues))
+	}
+
+	categoryOrder := make(map[domain.OutcomeCategory]int)
+	for i, category := range domain.OutcomeCategories {
+		categoryOrder[category] = i
+	}
+	sort.SliceStable(outcomes, func(i, j int) bool {
+		return categoryOrder[outcomeCategory[outcomes[i]]] < categoryOrder[outcomeCategory[outcomes[j]]]
+	})
+	for _, outcome := range outcomes {
+		summary.Outcomes = append(summary.Outcomes, newSummaryCount(string(outcome), outcomeCategory[outcome], outcomeCounts[outcome], summary.Comments))
+	}
+
+	for label, row := range versions {
+		for _, outcome := range outcomes {
+			row.Counts = append(row.Counts, newSummaryCount(string(outcome), outcomeCategory[outcome], versionCounts[label][outcome], row.Total))
+		}
+		summary.Versions = append(summary.Versions, *row)
+	}
+	sort.Slice(summary.Versions, func(i, j int) bool {
+		a, b := summary.Versions[i], summary.Versions[j]
+		if (a.Label == summaryNoVersion) != (b.Label == summaryNoVersion) {
+			return b.Label == summaryNoVersion
+		}
+		if a.Version.IsZero() != b.Version.IsZero() {
+			return !a.Version.IsZero()
+		}
+		if c := a.Version.Compare(b.Version); c != 0 {
+			return c < 0
+		}
+		return a.Label < b.Label
+	})
+	return summary
+}
+
+func newSummaryCount(label string, category domain.OutcomeCategory, count, total int) summaryCount {
+	c := summaryCount{Label: label, Category: category, Count: count}
+	if total > 0 {
+		c.Percent = float64(count) * 100 / float64(total)
+	}
+	return c
+}
+
+// categoryOrUnknown возвращает unknown для комментариев без категории
+func categoryOrUnknown(category domain.OutcomeCategory) domain.OutcomeCategory {
+	if category == "" {
+		return domain.CategoryUnknown
+	}
+	return category
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildReportSummary(t *testing.T) {
+	issuesList := csvTestIssues(t)
+	issuesList.Failures = []domain.TicketFailure{{Key: "TOS-9", Error: "not found"}}
+
+	summary := buildReportSummary(issuesList)
+	assert.Equal(t, 2, summary.Issues)
+	assert.Equal(t, 2, summary.Comments)
+	assert.Equal(t, 1, summary.Failures)
+	assert.Equal(t, []summaryCount{
+		{Label: "pass", Category: domain.CategoryPass, Count: 1, Percent: 50},
+		{Label: summaryNoComments, Category: domain.CategoryUnknown, Count: 1, Percent: 50},
+	}, summary.Latest)
+	assert.Equal(t, []summaryCount{
+		{Label: "Fixed", Category: domain.CategoryPass, Count: 1, Percent: 50},
+		{Label: "Not Fixed", Category: domain.CategoryFail, Count: 1, Percent: 50},
+	}, summary.Outcomes)
+
+	assert.Len(t, summary.Versions, 2)
+	assert.Equal(t, "v1.0.0", summary.Versions[0].Label)
+	assert.Equal(t, []summaryCount{
+		{Label: "Fixed", Category: domain.CategoryPass},
+		{Label: "Not Fixed", Category: domain.CategoryFail, Count: 1, Percent: 100},
+	}, summary.Versions[0].Counts)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// mustVersion разбирает заведомо корректную версию из тестовых данных
+func mustVersion(t *testing.T, value string) domain.Version {
+	t.Helper()
+
+	v, err := domain.ParseVersion(value)
+	assert.NoError(t, err)
+	return v
+}
+
+func TestGenerateHTMLReportScenarios(t *testing.T) {
+	issuesList := &domain.IssuesList{
+		Issues: []domain.Issue{
+			{
+				Key:           "TOS-30690",
+				LatestVersion: mustVersion(t, "1.0.1"),
+				Comments: []domain.QAComment{
+					{
+						SoftwareVersion: "v1.0.0",
+						TestResult:      domain.OutcomePartiallyFixed,
+						Category:        domain.CategoryPartial,
+						Results: []domain.TestCaseResult{
+							{Scenario: "Login", Result: domain.OutcomeFixed, Category: domain.CategoryPass},
+							{Scenario: "Logout", Result: domain.OutcomeNotFixed, Category: domain.CategoryFail, Note: "session is kept"},
+						},
+					},
+					{
+						SoftwareVersion: "v1.0.1",
+						TestResult:      domain.OutcomeFixed,
+						Category:        domain.CategoryPass,
+					},
+				},
+			},
+		},
+	}
+
+	html, err := generateHTMLReport(issuesList)
+	assert.NoError(t, err)
+
+	assert.Contains(t, html, `<span class="comment-value result-partial">Partially Fixed</span>`)
+	assert.Contains(t, html, "<tr><th>Scenario</th><th>Result</th><th>Note</th></tr>")
+	assert.Contains(t, html, `<tr><td>Login</td><td class="result-pass">Fixed</td><td></td></tr>`)
+	assert.Contains(t, html, `<tr><td>Logout</td><td class="result-fail">Not Fixed</td><td>session is kept</td></tr>`)
+	assert.Contains(t, html, "<div><strong>Latest version tested:</strong> 1.0.1</div>")
+	// Таблица выводится только для комментариев с результатами сценариев
+	assert.Equal(t, 1, strings.Count(html, `<table class="scenarios">`))
+}
+
+func TestGenerateHTMLReportInteractive(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(configPath, []byte("jira:\n  base_url: https://jira.example.com\n"), 0644))
+	viper.SetConfigFile(configPath)
+	assert.NoError(t, viper.ReadInConfig())
+
+	html, err := generateHTMLReport(csvTestIssues(t))
+	assert.NoError(t, err)
+
+	// Сводка: последний вердикт TOS-1 - pass, у TOS-2 нет QA комментариев
+	assert.Contains(t, html, `<div class="stat"><div class="stat-value">2</div>issues</div>`)
+	assert.Contains(t, html, `<span class="chart-label">pass</span>
+					<span class="chart-bar"><span style="width: 50.0%; background-color: green"></span></span>`)
+	assert.Contains(t, html, `<span class="chart-label">No QA comments</span>`)
+	assert.Contains(t, html, `<span title="Not Fixed: 1" style="width: 100.0%; background-color: red"></span>`)
+
+	// Строки таблиц несут значения фильтров, ключи ссылаются на JIRA
+	assert.Contains(t, html, `<tr class="filterable" data-result="Fixed" data-version="v1.0.1" data-author="qa@example.com" data-date="2025-08-14 09:00:00">
+				<td><a href="https://jira.example.com/browse/TOS-1" target="_blank" rel="noopener">TOS-1</a> <a href="#issue-TOS-1" title="Show QA comments">&#8595;</a></td>`)
+	assert.Contains(t, html, `<td class="result-unknown">No QA comments</td>`)
+	assert.Equal(t, 2, strings.Count(html, `<tr class="filterable" data-result="Not Fixed"`)+strings.Count(html, `<div class="comment filterable" data-result="Not Fixed"`))
+
+	// Тикеты сворачиваются, а сворачивание и фильтры работают без внешних файлов
+	assert.Contains(t, html, `<details class="issue" id="issue-TOS-2" open>`)
+	assert.Contains(t, html, `<summary class="issue-key"><a href="https://jira.example.com/browse/TOS-1" target="_blank" rel="noopener">TOS-1</a><span class="badge result-pass">Fixed</span></summary>`)
+	assert.Contains(t, html, "<script>")
+	assert.NotContains(t, html, "<script src")
+	assert.NotContains(t, html, `<link rel="stylesheet"`)
+
+	// Без base_url ключи выводятся без ссылок
+	assert.NoError(t, os.WriteFile(configPath, []byte("jira:\n  token: offline\n"), 0644))
+	assert.NoError(t, viper.ReadInConfig())
+	html, err = generateHTMLReport(csvTestIssues(t))
+	assert.NoError(t, err)
+	assert.Contains(t, html, `<summary class="issue-key">TOS-1<span class="badge result-pass">Fixed</span></summary>`)
+	assert.NotContains(t, html, "/browse/")
+}
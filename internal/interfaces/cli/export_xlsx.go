@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/xlsx"
+)
+
+// xlsxCategoryStyles окрашивают ячейки результатов в те же цвета, что и getColorForCategory в терминале:
+// pass - зеленый, fail - красный, partial - желтый, blocked - синий
+var xlsxCategoryStyles = map[domain.OutcomeCategory]xlsx.Style{
+	domain.CategoryPass:    {FontColor: "006100", FillColor: "C6EFCE"},
+	domain.CategoryFail:    {FontColor: "9C0006", FillColor: "FFC7CE"},
+	domain.CategoryPartial: {FontColor: "9C5700", FillColor: "FFEB9C"},
+	domain.CategoryBlocked: {FontColor: "1F4E78", FillColor: "DDEBF7"},
+}
+
+// xlsxStyles - стили книги, зарегистрированные в Workbook
+type xlsxStyles struct {
+	header     xlsx.StyleID
+	wrap       xlsx.StyleID
+	total      xlsx.StyleID
+	categories map[domain.OutcomeCategory]xlsx.StyleID
+}
+
+func newXLSXStyles(workbook *xlsx.Workbook) xlsxStyles {
+	styles := xlsxStyles{
+		header:     workbook.AddStyle(xlsx.Style{Bold: true, FillColor: "D9D9D9"}),
+		wrap:       workbook.AddStyle(xlsx.Style{Wrap: true}),
+		total:      workbook.AddStyle(xlsx.Style{Bold: true}),
+		categories: make(map[domain.OutcomeCategory]xlsx.StyleID),
+	}
+	for _, category := range domain.OutcomeCategories {
+		if style, ok := xlsxCategoryStyles[category]; ok {
+			styles.categories[category] = workbook.AddStyle(style)
+		}
+	}
+	return styles
+}
+
+// result возвращает ячейку результата, окрашенную по категории
+func (s xlsxStyles) result(outcome domain.TestOutcome, category domain.OutcomeCategory) xlsx.Cell {
+	return xlsx.Text(string(outcome), s.categories[category])
+}
+
+func (s xlsxStyles) headerRow(sheet *xlsx.Sheet, titles ...string) {
+	cells := make([]xlsx.Cell, len(titles))
+	for i, title := range titles {
+		cells[i] = xlsx.Text(title, s.header)
+	}
+	sheet.AddRow(cells...)
+	sheet.FreezeHeader = true
+	sheet.AutoFilter = true
+}
+
+// buildXLSXWorkbook строит книгу из тех же данных, что экспортируются в JSON: лист тикетов
+// с последним вердиктом, лист всех QA комментариев и сводку результатов по версиям
+func buildXLSXWorkbook(issuesList *domain.IssuesList) *xlsx.Workbook {
+	workbook := xlsx.NewWorkbook()
+	styles := newXLSXStyles(workbook)
+
+	issues := workbook.AddSheet("Issues")
+	styles.headerRow(issues, "Key", "Summary", "Assignee", "QA Owner", "Latest Version", "QA Comments", "Latest Result", "Latest Comment Date")
+	for _, issue := range issuesList.Issues {
+		row := []xlsx.Cell{
+			xlsx.Text(issue.Key, 0),
+			xlsx.Text(issue.Summary, 0),
+			xlsx.Text(issue.AssigneeEmail, 0),
+			xlsx.Text(issue.QaOwnerEmail, 0),
+			xlsx.Text(issue.LatestVersion.String(), 0),
+			xlsx.Number(float64(len(issue.Comments)), 0),
+		}
+		if n := len(issue.Comments); n > 0 {
+			latest := issue.Comments[n-1]
+			row = append(row, styles.result(latest.TestResult, latest.Category), xlsx.Text(formatTime(latest.Created), 0))
+		} else {
+			row = append(row, xlsx.Text("", 0), xlsx.Text("", 0))
+		}
+		issues.AddRow(row...)
+	}
+
+	comments := workbook.AddSheet("QA Comments")
+	styles.headerRow(comments, "Key", "Created", "Updated", "Author", "Version", "Result", "Category", "Comment", "Scenarios")
+	for _, issue := range issuesList.Issues {
+		for _, comment := range issue.Comments {
+			comments.AddRow(
+				xlsx.Text(issue.Key, 0),
+				xlsx.Text(formatTime(comment.Created), 0),
+				xlsx.Text(formatTime(comment.Updated), 0),
+				xlsx.Text(comment.AuthorEmail, 0),
+				xlsx.Text(comment.SoftwareVersion, 0),
+				styles.result(comment.TestResult, comment.Category),
+				xlsx.Text(string(comment.Category), 0),
+				xlsx.Text(comment.Comment, styles.wrap),
+				xlsx.Text(formatScenarios(comment.Results), styles.wrap),
+			)
+		}
+	}
+
+	addXLSXSummary(workbook.AddSheet("Summary"), styles, issuesList)
+	return workbook
+}
+
+// addXLSXSummary заполняет сводную таблицу по buildReportSummary: строки - версии, столбцы - результаты
+func addXLSXSummary(sheet *xlsx.Sheet, styles xlsxStyles, issuesList *domain.IssuesList) {
+	summary := buildReportSummary(issuesList)
+
+	header := []xlsx.Cell{xlsx.Text("Version", styles.header)}
+	for _, outcome := range summary.Outcomes {
+		style := styles.header
+		if categoryStyle, ok := styles.categories[outcome.Category]; ok {
+			style = categoryStyle
+		}
+		header = append(header, xlsx.Text(outcome.Label, style))
+	}
+	header = append(header, xlsx.Text("Total", styles.header))
+	sheet.AddRow(header...)
+	sheet.FreezeHeader = true
+	sheet.AutoFilter = true
+
+	for _, row := range summary.Versions {
+		cells := []xlsx.Cell{xlsx.Text(row.Label, 0)}
+		for _, count := range row.Counts {
+			cells = append(cells, xlsx.Number(float64(count.Count), 0))
+		}
+		cells = append(cells, xlsx.Number(float64(row.Total), styles.total))
+		sheet.AddRow(cells...)
+	}
+
+	cells := []xlsx.Cell{xlsx.Text("Total", styles.total)}
+	for _, outcome := range summary.Outcomes {
+		cells = append(cells, xlsx.Number(float64(outcome.Count), styles.total))
+	}
+	cells = append(cells, xlsx.Number(float64(summary.Comments), styles.total))
+	sheet.AddRow(cells...)
+}
+
+func exportToXLSX(issuesList *domain.IssuesList, fileName string) {
+	fileNameWithExt := fileName + ".xlsx"
+	file, err := os.Create(fileNameWithExt)
+	if err != nil {
+		log.Fatalf("Error creating XLSX file: %v", err)
+	}
+	if err := buildXLSXWorkbook(issuesList).Write(file); err != nil {
+		_ = file.Close()
+		log.Fatalf("Error writing XLSX file: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		log.Fatalf("Error writing XLSX file: %v", err)
+	}
+
+	fmt.Printf("Exported results to %s\n", fileNameWithExt)
+	printFailures(issuesList.Failures)
+}
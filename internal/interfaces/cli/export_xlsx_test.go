@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/xlsx"
+	"github.com/stretchr/testify/assert"
+)
+
+// sheetText возвращает значения ячеек листа: текст или число
+func sheetText(sheet *xlsx.Sheet) [][]any {
+	rows := make([][]any, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		for _, cell := range row {
+			if cell.IsNumber {
+				rows[i] = append(rows[i], cell.Number)
+			} else {
+				rows[i] = append(rows[i], cell.Text)
+			}
+		}
+	}
+	return rows
+}
+
+func TestBuildXLSXWorkbook(t *testing.T) {
+	issuesList := csvTestIssues(t)
+	issuesList.Issues = append(issuesList.Issues, domain.Issue{
+		Key: "TOS-3",
+		Comments: []domain.QAComment{
+			{SoftwareVersion: "v1.0.0", Version: mustVersion(t, "1.0.0"), TestResult: domain.OutcomeFixed, Category: domain.CategoryPass},
+			{SoftwareVersion: "nightly", TestResult: domain.OutcomeCouldNotTest, Category: domain.CategoryBlocked},
+			{Comment: "looks fine"},
+		},
+	})
+	issuesList.Issues[0].Comments[0].Version = mustVersion(t, "1.0.0")
+	issuesList.Issues[0].Comments[1].Version = mustVersion(t, "1.0.1")
+
+	workbook := buildXLSXWorkbook(issuesList)
+	assert.Len(t, workbook.Sheets, 3)
+
+	issues := workbook.Sheets[0]
+	assert.Equal(t, "Issues", issues.Name)
+	assert.True(t, issues.FreezeHeader)
+	assert.True(t, issues.AutoFilter)
+	assert.Equal(t, []any{"TOS-1", `Login fails with "special" chars, sometimes`, "dev@example.com", "qa@example.com", "1.0.1", 2.0, "Fixed", "2025-08-14 09:00:00"}, sheetText(issues)[1])
+	assert.Equal(t, []any{"TOS-2", "No QA yet", "", "", "", 0.0, "", ""}, sheetText(issues)[2])
+
+	comments := workbook.Sheets[1]
+	assert.Equal(t, "QA Comments", comments.Name)
+	assert.Len(t, comments.Rows, 6)
+	assert.Equal(t, []any{"TOS-1", "2025-08-12 16:35:38", "", "qa@example.com", "v1.0.0", "Not Fixed", "fail", "Still broken:\nstep 3 fails", "Login: Fixed; Logout: Not Fixed (session is kept)"}, sheetText(comments)[1])
+
+	// Ячейки результатов окрашены по категории, как в терминале
+	fixed := issues.Rows[1][6]
+	notFixed := comments.Rows[1][5]
+	assert.NotZero(t, fixed.Style)
+	assert.NotEqual(t, fixed.Style, notFixed.Style)
+	assert.Equal(t, fixed.Style, comments.Rows[2][5].Style)
+
+	summary := workbook.Sheets[2]
+	assert.Equal(t, "Summary", summary.Name)
+	assert.Equal(t, [][]any{
+		{"Version", "Fixed", "Not Fixed", "Could not test", summaryNoResult, "Total"},
+		{"1.0.0", 1.0, 1.0, 0.0, 0.0, 2.0},
+		{"1.0.1", 1.0, 0.0, 0.0, 0.0, 1.0},
+		{"nightly", 0.0, 0.0, 1.0, 0.0, 1.0},
+		{summaryNoVersion, 0.0, 0.0, 0.0, 1.0, 1.0},
+		{"Total", 2.0, 1.0, 1.0, 1.0, 5.0},
+	}, sheetText(summary))
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/application"
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/filter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// filterFlags - флаги фильтрации комментариев, общие для всех команд, выводящих комментарии
+type filterFlags struct {
+	expr       string
+	result     string
+	dateFrom   string
+	dateTo     string
+	minVersion string
+	maxVersion string
+}
+
+// register добавляет флаги фильтрации к команде
+func (f *filterFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.expr, "filter", "", `Filter expression, e.g. 'result in (Fixed, "Partially Fixed") and author ~ "@qa.example.com" and version >= 5.2'`)
+	cmd.Flags().StringVarP(&f.result, "result", "r", "", "Filter comments by test result (e.g., Fixed, Not Fixed) or result category (pass, fail, partial, blocked, unknown)")
+	cmd.Flags().StringVarP(&f.dateFrom, "date-from", "d", "", "Filter comments created at or after the given date: YYYY-MM-DD, RFC3339, a relative value like 7d, or last-sprint (start of the previous sprint)")
+	cmd.Flags().StringVarP(&f.dateTo, "date-to", "t", "", "Filter comments created at or before the given date: YYYY-MM-DD, RFC3339, a relative value like 7d, or last-sprint (start of the previous sprint)")
+	cmd.Flags().StringVar(&f.minVersion, "min-version", "", "Keep comments tested on this version or later, e.g. 5.4 (includes 5.4.0-hotfix.N, excludes 5.4.0-rc1)")
+	cmd.Flags().StringVar(&f.maxVersion, "max-version", "", "Keep comments tested on this version or earlier, e.g. 5.4.2")
+}
+
+// filter компилирует флаги и выражение из файла тикетов в один фильтр; комментарий должен пройти
+// все условия. Даты без часового пояса берутся в поясе --tz. Без фильтров возвращается nil.
+func (f filterFlags) filter(ticketsFilter string) (*filter.Filter, error) {
+	now := time.Now()
+	sprints, err := configuredSprints()
+	if err != nil {
+		return nil, err
+	}
+	dates, err := domain.ParseDateRange(f.dateFrom, f.dateTo, now, displayLocation, sprints)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := domain.ParseVersionRange(f.minVersion, f.maxVersion)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := filter.Compile(f.expr, now, displayLocation, sprints)
+	if err != nil {
+		return nil, err
+	}
+	fileExpr, err := filter.Compile(ticketsFilter, now, displayLocation, sprints)
+	if err != nil {
+		return nil, fmt.Errorf("tickets file: %w", err)
+	}
+	return filter.And(fileExpr, expr, filter.Result(f.result), filter.Dates(dates), filter.Versions(versions)), nil
+}
+
+// configuredSprints читает календарь спринтов для даты last-sprint из секции sprint файла config.yaml:
+//
+//	sprint:
+//	  start: 2025-01-06 # начало любого спринта
+//	  length: 2w
+//
+// Без файла конфигурации или секции используются двухнедельные спринты domain.DefaultSprintCalendar.
+func configuredSprints() (domain.SprintCalendar, error) {
+	if err := viper.ReadInConfig(); err != nil {
+		return domain.DefaultSprintCalendar(displayLocation), nil
+	}
+	start := viper.GetString("sprint.start")
+	// YAML читает дату без кавычек как время в UTC
+	if t, ok := viper.Get("sprint.start").(time.Time); ok {
+		start = t.Format("2006-01-02")
+	}
+	sprints, err := domain.NewSprintCalendar(start, viper.GetString("sprint.length"), displayLocation)
+	if err != nil {
+		return domain.SprintCalendar{}, fmt.Errorf("failed to load config: %w", err)
+	}
+	return sprints, nil
+}
+
+// used описывает фильтры команды и выбор тикетов для поля filters документа JSON и YAML
+func (f filterFlags) used(selection ticketSelection) reportFilters {
+	return reportFilters{
+		Expression:    f.expr,
+		Result:        f.result,
+		DateFrom:      f.dateFrom,
+		DateTo:        f.dateTo,
+		MinVersion:    f.minVersion,
+		MaxVersion:    f.maxVersion,
+		JQL:           selection.jql,
+		TicketsFilter: selection.filter,
+	}
+}
+
+// withCommentFilter передает фильтр сервису; без фильтров сервис возвращает все комментарии
+func withCommentFilter(f *filter.Filter) application.Option {
+	if f == nil {
+		return application.WithCommentFilter(nil)
+	}
+	return application.WithCommentFilter(f)
+}
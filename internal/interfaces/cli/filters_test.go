@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentFilterVersions(t *testing.T) {
+	comments := []domain.QAComment{
+		{SoftwareVersion: "v5.3.9", Version: mustVersion(t, "5.3.9")},
+		{SoftwareVersion: "5.4.0-rc1", Version: mustVersion(t, "5.4.0-rc1")},
+		{SoftwareVersion: "5.4", Version: mustVersion(t, "5.4")},
+		{SoftwareVersion: "5.4.0-hotfix.2", Version: mustVersion(t, "5.4.0-hotfix.2")},
+		{SoftwareVersion: "v5.5.1", Version: mustVersion(t, "5.5.1")},
+		{SoftwareVersion: "nightly"},
+	}
+
+	versions := func(filtered []domain.QAComment) []string {
+		var result []string
+		for _, comment := range filtered {
+			result = append(result, comment.SoftwareVersion)
+		}
+		return result
+	}
+
+	filter, err := filterFlags{minVersion: "5.4"}.filter("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5.4", "5.4.0-hotfix.2", "v5.5.1"}, versions(filter.Apply(comments)))
+
+	filter, err = filterFlags{maxVersion: "v5.4.0"}.filter("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v5.3.9", "5.4.0-rc1", "5.4"}, versions(filter.Apply(comments)))
+
+	filter, err = filterFlags{minVersion: "5.4.0-rc1", maxVersion: "5.4.0-hotfix.2"}.filter("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5.4.0-rc1", "5.4", "5.4.0-hotfix.2"}, versions(filter.Apply(comments)))
+
+	// Без фильтров по версии остаются и комментарии с нераспознанной версией
+	filter, err = filterFlags{}.filter("")
+	assert.NoError(t, err)
+	assert.Len(t, filter.Apply(comments), len(comments))
+
+	_, err = filterFlags{minVersion: "latest"}.filter("")
+	assert.EqualError(t, err, `min-version: invalid version "latest"`)
+}
+
+func TestConfiguredSprints(t *testing.T) {
+	oldLocation := displayLocation
+	t.Cleanup(func() { displayLocation = oldLocation })
+	displayLocation = time.UTC
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	viper.SetConfigFile(configPath)
+
+	// Без файла конфигурации используются спринты по умолчанию
+	sprints, err := configuredSprints()
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DefaultSprintCalendar(time.UTC), sprints)
+
+	// Дата без кавычек в YAML тоже принимается
+	assert.NoError(t, os.WriteFile(configPath, []byte("sprint:\n  start: 2025-01-06\n  length: 3w\n"), 0644))
+	sprints, err = configuredSprints()
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SprintCalendar{Start: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), Days: 21}, sprints)
+
+	assert.NoError(t, os.WriteFile(configPath, []byte("sprint:\n  length: 2 weeks\n"), 0644))
+	_, err = filterFlags{dateFrom: domain.LastSprint}.filter("")
+	assert.EqualError(t, err, `failed to load config: invalid sprint length "2 weeks", use days or weeks like 14d or 2w`)
+}
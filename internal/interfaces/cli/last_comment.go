@@ -3,125 +3,118 @@ package cli
 import (
 	"fmt"
 	"log"
+	"os"
 	"strings"
-	"time"
 
 	"github.com/rd2w/jira-parser/internal/domain"
-	"github.com/rd2w/jira-parser/internal/infrastructure/config"
 	"github.com/spf13/cobra"
 )
 
 func NewLastCommentCommand() *cobra.Command {
 	var ticketsFile string
+	var jql string
+	var filters filterFlags
+	var templateSpec string
 
 	cmd := &cobra.Command{
 		Use:   "last-comment [issue-keys...]",
 		Short: "Get the last QA comment for issue(s)",
 		Long: `Get the last QA comment for issue(s).
+If --jql is provided, tickets are resolved through the JIRA search API.
 If no issue keys are provided, reads tickets from the specified file or from configs/tickets.yaml by default.
 Example: jira-parser last-comment TOS-30690 TOS-30692
-Example: jira-parser last-comment --tickets-file ./my-tickets.yaml`,
-		Args: cobra.ArbitraryArgs,
+Example: jira-parser last-comment --tickets-file ./my-tickets.yaml
+Example: jira-parser last-comment --jql "assignee = currentUser()"
+Example: jira-parser last-comment TOS-30690 --output json`,
+		Args:        cobra.ArbitraryArgs,
+		Annotations: map[string]string{outputAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
-			service, err := createCommentService()
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			// Аргументы имеют приоритет над --jql и файлом тикетов
+			selection, err := selectTickets(args, ticketsFile, jql)
 			if err != nil {
-				log.Fatalf("Error: %v", err)
+				log.Fatalf("Failed to resolve tickets: %v", err)
 			}
 
-			var ticketKeys []string
+			// С фильтрами выводится последний из подходящих комментариев
+			commentFilter, err := filters.filter(selection.filter)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
 
-			// If arguments are provided, use them as ticket keys
-			if len(args) > 0 {
-				ticketKeys = args
-			} else {
-				// Otherwise, load ticket list from the specified file or default
-				ticketsFilePath := ticketsFile
-				if ticketsFilePath == "" {
-					ticketsFilePath = "./configs/tickets.yaml"
-				}
+			tmpl, err := commandOutput(templateSpec, lastCommentTemplate, filters.used(selection))
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
 
-				ticketsConfig, err := config.LoadTickets(ticketsFilePath)
-				if err != nil {
-					log.Fatalf("Failed to read tickets file: %v", err)
-				}
+			service, err := createCommentService(ctx, withCommentFilter(commentFilter))
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
 
-				ticketKeys = ticketsConfig.Tickets
+			ticketKeys, err := selection.resolve(ctx, service)
+			if err != nil {
+				log.Fatalf("Failed to resolve tickets: %v", err)
 			}
 
 			if len(ticketKeys) == 0 {
-				log.Fatalf("No tickets provided either as arguments or in tickets file")
+				log.Fatalf("No tickets provided as arguments, by JQL query or in tickets file")
 			}
 
-			// Process each ticket and get the last comment
-			for _, ticketKey := range ticketKeys {
-				comment, err := service.GetLastComment(ticketKey)
+			// Текст выводится по мере обработки тикетов, остальные форматы - одним документом в конце
+			var lastComments domain.IssuesList
+			for i, ticketKey := range ticketKeys {
+				if err := ctx.Err(); err != nil {
+					reportInterruption(fmt.Errorf("stopped after %d of %d tickets: %w", i, len(ticketKeys), err))
+					break
+				}
+
+				comment, err := service.GetLastCommentWithContext(ctx, ticketKey)
 				if err != nil {
 					log.Printf("Failed to get last comment for %s: %v", ticketKey, err)
+					lastComments.Failures = append(lastComments.Failures, domain.TicketFailure{Key: ticketKey, Error: err.Error()})
 					continue
 				}
 
-				printLastComment(ticketKey, comment)
+				if machineOutput() {
+					lastComments.Issues = append(lastComments.Issues, lastCommentIssue(ticketKey, comment))
+					continue
+				}
+				if err := printLastComment(tmpl, ticketKey, comment); err != nil {
+					log.Fatalf("Error: %v", err)
+				}
 				fmt.Println(strings.Repeat("-", 30)) // separator between tickets
 			}
+
+			if machineOutput() {
+				if err := tmpl.render(os.Stdout, &lastComments); err != nil {
+					log.Fatalf("Error: %v", err)
+				}
+			}
 		},
 	}
 
+	filters.register(cmd)
 	cmd.Flags().StringVarP(&ticketsFile, "tickets-file", "f", "", "Path to the YAML file containing the list of tickets (default: ./configs/tickets.yaml)")
+	cmd.Flags().StringVar(&jql, "jql", "", "JQL query used to select tickets (e.g., 'filter = 12345')")
+	cmd.Flags().StringVar(&templateSpec, "template", "", templateFlagUsage)
 
 	return cmd
 }
 
-func printLastComment(issueKey string, comment *domain.QAComment) {
-	if comment == nil {
-		fmt.Printf("Last QA Comment for %s:\n", issueKey)
-		fmt.Println("No QA comments found")
-		return
-	}
-
-	// Format the creation date for display
-	createdTime := ""
-	if comment.Created != "" {
-		// Parse the timestamp and format it as "YYYY-MM-DD HH:MM:SS"
-		// Try multiple formats since JIRA can return different timestamp formats
-		var t time.Time
-		var err error
-
-		// Try the JIRA format with milliseconds and timezone offset: 2025-08-12T16:35:38.514+0300
-		t, err = time.Parse("2006-01-02T15:04:05.000-0700", comment.Created)
-		if err != nil {
-			// Try standard RFC3339 format
-			t, err = time.Parse(time.RFC3339, comment.Created)
-		}
-		if err != nil {
-			// Try another common format
-			t, err = time.Parse("2006-01-02T15:04:05-0700", comment.Created)
-		}
-
-		if err == nil {
-			createdTime = t.Format("2006-01-02 15:04:05")
-		}
-	}
-
-	// Print header with author and date
-	if comment.AuthorEmail != "" && createdTime != "" {
-		fmt.Printf("Last QA comment on %s by %s (%s)\n", issueKey, comment.AuthorEmail, createdTime)
-	} else if comment.AuthorEmail != "" {
-		fmt.Printf("Last QA comment on %s by %s\n", issueKey, comment.AuthorEmail)
-	} else if createdTime != "" {
-		fmt.Printf("Last QA comment on %s (%s)\n", issueKey, createdTime)
-	} else {
-		fmt.Printf("Last QA comment on %s\n", issueKey)
-	}
-
-	if comment.SoftwareVersion != "" {
-		fmt.Printf("Version: %s\n", comment.SoftwareVersion)
-	}
-
-	// Use colored output for test result
-	resultColor := getColorForStatus(comment.TestResult)
-	_, _ = resultColor.Printf("Result: %s\n", comment.TestResult)
+// printLastComment выводит последний QA комментарий тикета шаблоном команды last-comment;
+// тикет без QA комментариев передается в шаблон без комментариев
+func printLastComment(tmpl *outputTemplate, issueKey string, comment *domain.QAComment) error {
+	return tmpl.render(os.Stdout, &domain.IssuesList{Issues: []domain.Issue{lastCommentIssue(issueKey, comment)}})
+}
 
-	if comment.Comment != "" {
-		fmt.Printf("Comment: %s\n", comment.Comment)
+// lastCommentIssue возвращает тикет только с последним QA комментарием
+func lastCommentIssue(issueKey string, comment *domain.QAComment) domain.Issue {
+	issue := domain.Issue{Key: issueKey}
+	if comment != nil {
+		issue.Comments = []domain.QAComment{*comment}
 	}
+	return issue
 }
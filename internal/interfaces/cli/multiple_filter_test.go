@@ -18,13 +18,13 @@ func TestFilterMultipleIssuesByResult(t *testing.T) {
 						SoftwareVersion: "v1.0.0",
 						TestResult:      "Fixed",
 						Comment:         "All tests passed",
-						Created:         time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+						Created:         time.Now().Add(-24 * time.Hour),
 					},
 					{
 						SoftwareVersion: "v1.0.1",
 						TestResult:      "Not Fixed",
 						Comment:         "Issue still exists",
-						Created:         time.Now().Add(-12 * time.Hour).Format(time.RFC3339),
+						Created:         time.Now().Add(-12 * time.Hour),
 					},
 				},
 			},
@@ -35,7 +35,7 @@ func TestFilterMultipleIssuesByResult(t *testing.T) {
 						SoftwareVersion: "v1.0.2",
 						TestResult:      "Fixed",
 						Comment:         "Fixed in this version",
-						Created:         time.Now().Format(time.RFC3339),
+						Created:         time.Now(),
 					},
 				},
 			},
@@ -43,22 +43,17 @@ func TestFilterMultipleIssuesByResult(t *testing.T) {
 	}
 
 	// Тестируем фильтрацию по результату "Fixed"
-	resultFilter := "Fixed"
+	filter, err := filterFlags{result: "Fixed"}.filter("")
+	assert.NoError(t, err)
 	for i := range issuesList.Issues {
-		var filteredComments []domain.QAComment
-		for _, comment := range issuesList.Issues[i].Comments {
-			if comment.TestResult == resultFilter {
-				filteredComments = append(filteredComments, comment)
-			}
-		}
-		issuesList.Issues[i].Comments = filteredComments
+		issuesList.Issues[i].Comments = filter.Apply(issuesList.Issues[i].Comments)
 	}
 
 	// Проверяем, что остались только комментарии с результатом "Fixed"
 	assert.Len(t, issuesList.Issues[0].Comments, 1)
-	assert.Equal(t, "Fixed", issuesList.Issues[0].Comments[0].TestResult)
+	assert.Equal(t, domain.OutcomeFixed, issuesList.Issues[0].Comments[0].TestResult)
 	assert.Len(t, issuesList.Issues[1].Comments, 1)
-	assert.Equal(t, "Fixed", issuesList.Issues[1].Comments[0].TestResult)
+	assert.Equal(t, domain.OutcomeFixed, issuesList.Issues[1].Comments[0].TestResult)
 
 	// Тестируем фильтрацию по результату "Not Fixed"
 	// Создаем новый issuesList для второго теста, чтобы не модифицировать исходные данные
@@ -71,13 +66,13 @@ func TestFilterMultipleIssuesByResult(t *testing.T) {
 						SoftwareVersion: "v1.0.0",
 						TestResult:      "Fixed",
 						Comment:         "All tests passed",
-						Created:         time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+						Created:         time.Now().Add(-24 * time.Hour),
 					},
 					{
 						SoftwareVersion: "v1.0.1",
 						TestResult:      "Not Fixed",
 						Comment:         "Issue still exists",
-						Created:         time.Now().Add(-12 * time.Hour).Format(time.RFC3339),
+						Created:         time.Now().Add(-12 * time.Hour),
 					},
 				},
 			},
@@ -88,27 +83,22 @@ func TestFilterMultipleIssuesByResult(t *testing.T) {
 						SoftwareVersion: "v1.0.2",
 						TestResult:      "Fixed",
 						Comment:         "Fixed in this version",
-						Created:         time.Now().Format(time.RFC3339),
+						Created:         time.Now(),
 					},
 				},
 			},
 		},
 	}
 
-	resultFilter = "Not Fixed"
+	filter, err = filterFlags{result: "Not Fixed"}.filter("")
+	assert.NoError(t, err)
 	for i := range issuesList2.Issues {
-		var filteredComments []domain.QAComment
-		for _, comment := range issuesList2.Issues[i].Comments {
-			if comment.TestResult == resultFilter {
-				filteredComments = append(filteredComments, comment)
-			}
-		}
-		issuesList2.Issues[i].Comments = filteredComments
+		issuesList2.Issues[i].Comments = filter.Apply(issuesList2.Issues[i].Comments)
 	}
 
 	// Проверяем, что остались только комментарии с результатом "Not Fixed"
 	assert.Len(t, issuesList2.Issues[0].Comments, 1)
-	assert.Equal(t, "Not Fixed", issuesList2.Issues[0].Comments[0].TestResult)
+	assert.Equal(t, domain.OutcomeNotFixed, issuesList2.Issues[0].Comments[0].TestResult)
 	assert.Len(t, issuesList2.Issues[1].Comments, 0)
 }
 
@@ -126,13 +116,13 @@ func TestFilterMultipleIssuesByDate(t *testing.T) {
 						SoftwareVersion: "v1.0.0",
 						TestResult:      "Fixed",
 						Comment:         "All tests passed",
-						Created:         twoDaysAgo.Format(time.RFC3339),
+						Created:         twoDaysAgo,
 					},
 					{
 						SoftwareVersion: "v1.0.1",
 						TestResult:      "Not Fixed",
 						Comment:         "Issue still exists",
-						Created:         yesterday.Format(time.RFC3339),
+						Created:         yesterday,
 					},
 				},
 			},
@@ -143,7 +133,7 @@ func TestFilterMultipleIssuesByDate(t *testing.T) {
 						SoftwareVersion: "v1.0.2",
 						TestResult:      "Fixed",
 						Comment:         "Fixed in this version",
-						Created:         now.Format(time.RFC3339),
+						Created:         now,
 					},
 				},
 			},
@@ -151,21 +141,10 @@ func TestFilterMultipleIssuesByDate(t *testing.T) {
 	}
 
 	// Тестируем фильтрацию по дате "date-from"
-	dateFrom := yesterday.Add(-time.Hour).Format("2006-01-02")
+	filter, err := filterFlags{dateFrom: yesterday.Add(-time.Hour).Format(time.RFC3339)}.filter("")
+	assert.NoError(t, err)
 	for i := range issuesList.Issues {
-		var filteredComments []domain.QAComment
-		for _, comment := range issuesList.Issues[i].Comments {
-			commentTime, err := time.Parse(time.RFC3339, comment.Created)
-			assert.NoError(t, err)
-
-			fromTime, err := time.Parse("2006-01-02", dateFrom)
-			assert.NoError(t, err)
-
-			if !commentTime.Before(fromTime) {
-				filteredComments = append(filteredComments, comment)
-			}
-		}
-		issuesList.Issues[i].Comments = filteredComments
+		issuesList.Issues[i].Comments = filter.Apply(issuesList.Issues[i].Comments)
 	}
 
 	// Проверяем, что остались только комментарии, созданные после указанной даты
@@ -191,13 +170,13 @@ func TestFilterMultipleIssuesByDate(t *testing.T) {
 						SoftwareVersion: "v1.0.0",
 						TestResult:      "Fixed",
 						Comment:         "All tests passed",
-						Created:         twoDaysAgoFixed.Format(time.RFC3339),
+						Created:         twoDaysAgoFixed,
 					},
 					{
 						SoftwareVersion: "v1.0.1",
 						TestResult:      "Not Fixed",
 						Comment:         "Issue still exists",
-						Created:         yesterdayFixed.Format(time.RFC3339),
+						Created:         yesterdayFixed,
 					},
 				},
 			},
@@ -208,7 +187,7 @@ func TestFilterMultipleIssuesByDate(t *testing.T) {
 						SoftwareVersion: "v1.0.2",
 						TestResult:      "Fixed",
 						Comment:         "Fixed in this version",
-						Created:         nowFixed.Format(time.RFC3339),
+						Created:         nowFixed,
 					},
 				},
 			},
@@ -216,22 +195,11 @@ func TestFilterMultipleIssuesByDate(t *testing.T) {
 	}
 
 	// dateTo - это "вчера плюс один час"
-	dateToTime := yesterdayFixed.Add(time.Hour)
-	dateTo := dateToTime.Format(time.RFC3339)
+	dateTo := yesterdayFixed.Add(time.Hour).Format(time.RFC3339)
+	filter, err = filterFlags{dateTo: dateTo}.filter("")
+	assert.NoError(t, err)
 	for i := range issuesList2.Issues {
-		var filteredComments []domain.QAComment
-		for _, comment := range issuesList2.Issues[i].Comments {
-			commentTime, err := time.Parse(time.RFC3339, comment.Created)
-			assert.NoError(t, err)
-
-			toTime, err := time.Parse(time.RFC3339, dateTo)
-			assert.NoError(t, err)
-
-			if !commentTime.After(toTime) {
-				filteredComments = append(filteredComments, comment)
-			}
-		}
-		issuesList2.Issues[i].Comments = filteredComments
+		issuesList2.Issues[i].Comments = filter.Apply(issuesList2.Issues[i].Comments)
 	}
 
 	// Проверяем, что остались только комментарии, созданные до указанной даты
@@ -44,12 +44,15 @@ func TestPrintMultipleIssues(t *testing.T) {
 		Issues: []domain.Issue{
 			{
 				Key: "TOS-30690",
+				Rejections: []domain.CommentRejection{
+					{CommentID: "10042", AuthorEmail: "qa@example.com", Reason: `missing required field "result" (labels: Result)`},
+				},
 				Comments: []domain.QAComment{
 					{
 						SoftwareVersion: "v1.0.0",
 						TestResult:      "Fixed",
 						Comment:         "All tests passed",
-						Created:         "2025-08-12T16:35:38.514+0300",
+						Created:         jiraTime("2025-08-12T16:35:38.514+0300"),
 					},
 				},
 			},
@@ -60,12 +63,16 @@ func TestPrintMultipleIssues(t *testing.T) {
 						SoftwareVersion: "v1.0.1",
 						TestResult:      "Not Fixed",
 						Comment:         "Issue still exists",
-						Created:         "2025-08-12T16:35:38.514+0300",
+						Created:         jiraTime("2025-08-12T16:35:38.514+0300"),
+						Results: []domain.TestCaseResult{
+							{Scenario: "Login", Result: "Fixed"},
+							{Scenario: "Logout", Result: "Not Fixed", Note: "session is kept"},
+						},
 					},
 					{
 						SoftwareVersion: "v1.0.2",
 						TestResult:      "Fixed",
-						Created:         "2025-08-12T16:35:38.514+0300",
+						Created:         jiraTime("2025-08-12T16:35:38.514+0300"),
 					},
 				},
 			},
@@ -77,13 +84,14 @@ func TestPrintMultipleIssues(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	printMultipleIssues(issuesList)
+	err := printMultipleIssues(defaultTemplate(parseMultipleTemplate), issuesList)
 
 	_ = w.Close()
 	out, _ := io.ReadAll(r)
 	os.Stdout = old
 
 	output := string(out)
+	assert.NoError(t, err)
 
 	// Проверяем, что вывод содержит ожидаемые элементы
 	assert.Contains(t, output, "Checked 2 issues with QA comments:")
@@ -101,6 +109,11 @@ func TestPrintMultipleIssues(t *testing.T) {
 	assert.Contains(t, output, "v1.0.2") // Version value
 	assert.Contains(t, output, "Comment #1 (2025-08-12 16:35:38):")
 	assert.Contains(t, output, "Comment #2 (2025-08-12 16:35:38):")
+	assert.Contains(t, output, "  Scenarios:")
+	assert.Contains(t, output, "  - Login: ")
+	assert.Contains(t, output, "  - Logout: ")
+	assert.Contains(t, output, "(session is kept)")
+	assert.Contains(t, output, `  Comment 10042 from qa@example.com: missing required field "result" (labels: Result)`)
 }
 
 func TestParseMultipleCommand_Execute_Basic(t *testing.T) {
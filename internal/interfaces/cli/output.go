@@ -0,0 +1,266 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Форматы глобального флага --output
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputJSONL = "jsonl"
+	outputYAML  = "yaml"
+	outputTable = "table"
+)
+
+var outputFormats = []string{outputText, outputJSON, outputJSONL, outputYAML, outputTable}
+
+// outputAnnotation помечает команды чтения, которые поддерживают --output
+const outputAnnotation = "output"
+
+// tableColumns - столбцы --output table из числа столбцов CSV экспорта
+const tableColumns = "key,created,author,version,result,comment"
+
+// outputFormat - значение глобального флага --output
+var outputFormat string
+
+// checkOutputFormat проверяет --output: значение из outputFormats, а форматы кроме text
+// доступны только командам с outputAnnotation
+func checkOutputFormat(cmd *cobra.Command) error {
+	outputFormat = strings.ToLower(outputFormat)
+	valid := false
+	for _, format := range outputFormats {
+		if outputFormat == format {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("unknown output format %q, available formats: %s", outputFormat, strings.Join(outputFormats, ", "))
+	}
+	if outputFormat != outputText && cmd.Annotations[outputAnnotation] == "" {
+		return fmt.Errorf("--output %s is not supported by %s", outputFormat, cmd.Name())
+	}
+	return nil
+}
+
+// machineOutput сообщает, что stdout занят данными, и предупреждения нужно писать в stderr
+func machineOutput() bool {
+	return outputFormat != "" && outputFormat != outputText
+}
+
+// commandOutput возвращает вывод команды чтения: шаблон --template или встроенный шаблон fallback
+// для --output text, иначе запись тикетов в формате --output
+func commandOutput(templateSpec, fallback string, filters reportFilters) (*outputTemplate, error) {
+	if !machineOutput() {
+		return loadOutputTemplate(templateSpec, fallback)
+	}
+	if templateSpec != "" {
+		return nil, fmt.Errorf("--template can only be used with --output text")
+	}
+
+	var write func(w io.Writer, issuesList *domain.IssuesList) error
+	switch outputFormat {
+	case outputJSON:
+		write = func(w io.Writer, issuesList *domain.IssuesList) error {
+			return writeJSONOutput(w, issuesList, filters, true)
+		}
+	case outputJSONL:
+		write = writeJSONLOutput
+	case outputYAML:
+		write = func(w io.Writer, issuesList *domain.IssuesList) error {
+			return writeYAMLOutput(w, issuesList, filters)
+		}
+	case outputTable:
+		columns, err := parseCSVColumns(tableColumns)
+		if err != nil {
+			return nil, err
+		}
+		write = func(w io.Writer, issuesList *domain.IssuesList) error {
+			return writeTableOutput(w, issuesList, columns)
+		}
+	}
+	return &outputTemplate{name: outputFormat, execute: func(w io.Writer, data any) error {
+		return write(w, data.(*domain.IssuesList))
+	}}, nil
+}
+
+// reportDocument - документ JSON экспорта и --output json и yaml. Схема документа публикуется командой schema
+// (schema/report.schema.json) и должна меняться вместе с тегами ниже и в domain.
+type reportDocument struct {
+	SchemaVersion int                    `json:"schema_version" yaml:"schema_version"`
+	GeneratedAt   time.Time              `json:"generated_at" yaml:"generated_at"`
+	Tool          reportTool             `json:"tool" yaml:"tool"`
+	Source        reportSource           `json:"source" yaml:"source"`
+	Filters       reportFilters          `json:"filters" yaml:"filters"`
+	Issues        []domain.Issue         `json:"issues" yaml:"issues"`
+	Failures      []domain.TicketFailure `json:"failures" yaml:"failures"`
+}
+
+// reportTool - версия jira-parser, создавшего документ
+type reportTool struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	Commit  string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Date    string `json:"date,omitempty" yaml:"date,omitempty"`
+}
+
+// reportSource - откуда загружены тикеты: JIRA (base_url) или каталог выгрузок (--source file:<dir>)
+type reportSource struct {
+	Type    string `json:"type" yaml:"type"`
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	Dir     string `json:"dir,omitempty" yaml:"dir,omitempty"`
+}
+
+// reportFilters - фильтры и выбор тикетов, с которыми получен документ; пустые значения не выводятся
+type reportFilters struct {
+	Expression    string `json:"expression,omitempty" yaml:"expression,omitempty"`
+	Result        string `json:"result,omitempty" yaml:"result,omitempty"`
+	DateFrom      string `json:"date_from,omitempty" yaml:"date_from,omitempty"`
+	DateTo        string `json:"date_to,omitempty" yaml:"date_to,omitempty"`
+	MinVersion    string `json:"min_version,omitempty" yaml:"min_version,omitempty"`
+	MaxVersion    string `json:"max_version,omitempty" yaml:"max_version,omitempty"`
+	JQL           string `json:"jql,omitempty" yaml:"jql,omitempty"`
+	TicketsFilter string `json:"tickets_filter,omitempty" yaml:"tickets_filter,omitempty"`
+}
+
+// newReportDocument собирает документ; пустые списки выводятся как [], а не null
+func newReportDocument(issuesList *domain.IssuesList, filters reportFilters) reportDocument {
+	generatedAt := time.Now()
+	if displayLocation != nil {
+		generatedAt = generatedAt.In(displayLocation)
+	}
+	document := reportDocument{
+		SchemaVersion: domain.SchemaVersion,
+		GeneratedAt:   generatedAt.Truncate(time.Second),
+		Tool: reportTool{
+			Name:    "jira-parser",
+			Version: version.App.Version,
+			Commit:  version.App.Commit,
+			Date:    version.App.Date,
+		},
+		Source:   newReportSource(),
+		Filters:  filters,
+		Issues:   make([]domain.Issue, len(issuesList.Issues)),
+		Failures: append([]domain.TicketFailure{}, issuesList.Failures...),
+	}
+	for i, issue := range issuesList.Issues {
+		if issue.Comments == nil {
+			issue.Comments = []domain.QAComment{}
+		}
+		document.Issues[i] = issue
+	}
+	return document
+}
+
+// newReportSource описывает глобальный флаг --source; base_url берется из прочитанной конфигурации
+func newReportSource() reportSource {
+	if dir, err := offlineDir(source); err == nil && dir != "" {
+		return reportSource{Type: "file", Dir: dir}
+	}
+	return reportSource{Type: "jira", BaseURL: viper.GetString("jira.base_url")}
+}
+
+// Записи --output jsonl: строка на тикет, затем строка на каждый тикет, который не удалось обработать.
+// Поле type различает записи: issue или failure.
+type issueRecord struct {
+	SchemaVersion int    `json:"schema_version"`
+	Type          string `json:"type"`
+	domain.Issue
+}
+
+type failureRecord struct {
+	SchemaVersion int    `json:"schema_version"`
+	Type          string `json:"type"`
+	domain.TicketFailure
+}
+
+// writeJSONOutput записывает документ reportDocument; pretty выводит его с отступами
+func writeJSONOutput(w io.Writer, issuesList *domain.IssuesList, filters reportFilters, pretty bool) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(newReportDocument(issuesList, filters))
+}
+
+func writeJSONLOutput(w io.Writer, issuesList *domain.IssuesList) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, issue := range newReportDocument(issuesList, reportFilters{}).Issues {
+		if err := encoder.Encode(issueRecord{SchemaVersion: domain.SchemaVersion, Type: "issue", Issue: issue}); err != nil {
+			return err
+		}
+	}
+	for _, failure := range issuesList.Failures {
+		if err := encoder.Encode(failureRecord{SchemaVersion: domain.SchemaVersion, Type: "failure", TicketFailure: failure}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeYAMLOutput(w io.Writer, issuesList *domain.IssuesList, filters reportFilters) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(newReportDocument(issuesList, filters)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeTableOutput выводит строку на QA комментарий с выровненными столбцами, как CSV экспорт;
+// переводы строк в значениях заменяются пробелами
+func writeTableOutput(w io.Writer, issuesList *domain.IssuesList, columns []csvColumn) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column.name)
+	}
+	if _, err := fmt.Fprintln(table, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+
+	cleaner := strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ")
+	err := forEachCommentRow(issuesList, false, func(issue domain.Issue, comment *domain.QAComment) error {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = cleaner.Replace(column.value(issue, comment))
+		}
+		// Пустые столбцы в конце строки не дополняются пробелами
+		for len(cells) > 1 && cells[len(cells)-1] == "" {
+			cells = cells[:len(cells)-1]
+		}
+		_, err := fmt.Fprintln(table, strings.Join(cells, "\t"))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if len(issuesList.Failures) > 0 {
+		if _, err := fmt.Fprintf(w, "\nFailed to process %d tickets:\n", len(issuesList.Failures)); err != nil {
+			return err
+		}
+		for _, failure := range issuesList.Failures {
+			if _, err := fmt.Fprintf(w, "  %s: %s\n", failure.Key, failure.Error); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
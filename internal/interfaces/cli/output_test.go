@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+// renderOutput выводит тикеты в формате --output
+func renderOutput(t *testing.T, format string, issuesList *domain.IssuesList) string {
+	outputFormat = format
+	t.Cleanup(func() { outputFormat = outputText })
+
+	tmpl, err := commandOutput("", parseMultipleTemplate, reportFilters{})
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, tmpl.render(&buf, issuesList))
+	return buf.String()
+}
+
+func TestJSONOutput(t *testing.T) {
+	issuesList := csvTestIssues(t)
+	issuesList.Issues[0].Comments[0].Version = mustVersion(t, "1.0.0")
+	issuesList.Failures = []domain.TicketFailure{{Key: "TOS-9", Error: "not found"}}
+
+	var document map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(renderOutput(t, outputJSON, issuesList)), &document))
+	assert.Equal(t, float64(domain.SchemaVersion), document["schema_version"])
+	assert.Equal(t, "jira-parser", document["tool"].(map[string]any)["name"])
+	assert.Equal(t, []any{map[string]any{"key": "TOS-9", "error": "not found"}}, document["failures"])
+
+	issues := document["issues"].([]any)
+	assert.Len(t, issues, 2)
+	issue := issues[0].(map[string]any)
+	assert.Equal(t, "TOS-1", issue["key"])
+	assert.Equal(t, "qa@example.com", issue["qa_owner_email"])
+	assert.Equal(t, "1.0.1", issue["latest_version"])
+	assert.Equal(t, map[string]any{
+		"software_version": "v1.0.0",
+		"version":          "1.0.0",
+		"test_result":      "Not Fixed",
+		"category":         "fail",
+		"comment":          "Still broken:\nstep 3 fails",
+		"created":          "2025-08-12T16:35:38Z",
+		"author_email":     "qa@example.com",
+		"results": []any{
+			map[string]any{"scenario": "Login", "result": "Fixed", "category": ""},
+			map[string]any{"scenario": "Logout", "result": "Not Fixed", "category": "", "note": "session is kept"},
+		},
+	}, issue["comments"].([]any)[0])
+
+	// Тикет без QA комментариев выводится с пустым списком, а не null
+	assert.Equal(t, []any{}, issues[1].(map[string]any)["comments"])
+}
+
+func TestJSONLOutput(t *testing.T) {
+	issuesList := csvTestIssues(t)
+	issuesList.Failures = []domain.TicketFailure{{Key: "TOS-9", Error: "not found"}}
+
+	lines := strings.Split(strings.TrimSuffix(renderOutput(t, outputJSONL, issuesList), "\n"), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], `{"schema_version":1,"type":"issue","key":"TOS-1","summary":`))
+	assert.True(t, strings.HasPrefix(lines[1], `{"schema_version":1,"type":"issue","key":"TOS-2","summary":"No QA yet"`))
+	assert.Equal(t, `{"schema_version":1,"type":"failure","key":"TOS-9","error":"not found"}`, lines[2])
+}
+
+func TestYAMLOutput(t *testing.T) {
+	output := renderOutput(t, outputYAML, csvTestIssues(t))
+	assert.True(t, strings.HasPrefix(output, "schema_version: 1\ngenerated_at: "))
+	assert.Contains(t, output, "\nissues:\n  - key: TOS-1\n")
+
+	var document reportDocument
+	assert.NoError(t, yaml.Unmarshal([]byte(output), &document))
+	assert.Len(t, document.Issues, 2)
+	assert.Equal(t, "v1.0.1", document.Issues[0].Comments[1].SoftwareVersion)
+	assert.Equal(t, domain.CategoryPass, document.Issues[0].Comments[1].Category)
+	assert.Empty(t, document.Failures)
+}
+
+func TestTableOutput(t *testing.T) {
+	issuesList := csvTestIssues(t)
+	issuesList.Failures = []domain.TicketFailure{{Key: "TOS-9", Error: "not found"}}
+
+	assert.Equal(t, `KEY    CREATED              AUTHOR          VERSION  RESULT     COMMENT
+TOS-1  2025-08-12 16:35:38  qa@example.com  v1.0.0   Not Fixed  Still broken: step 3 fails
+TOS-1  2025-08-14 09:00:00  qa@example.com  v1.0.1   Fixed
+TOS-2
+
+Failed to process 1 tickets:
+  TOS-9: not found
+`, renderOutput(t, outputTable, issuesList))
+}
+
+func TestCheckOutputFormat(t *testing.T) {
+	t.Cleanup(func() { outputFormat = outputText })
+	readCmd := &cobra.Command{Use: "parse", Annotations: map[string]string{outputAnnotation: "true"}}
+	exportCmd := &cobra.Command{Use: "export"}
+
+	outputFormat = "JSON"
+	assert.NoError(t, checkOutputFormat(readCmd))
+	assert.Equal(t, outputJSON, outputFormat)
+	assert.ErrorContains(t, checkOutputFormat(exportCmd), "--output json is not supported by export")
+
+	outputFormat = "xml"
+	assert.ErrorContains(t, checkOutputFormat(readCmd), `unknown output format "xml"`)
+
+	outputFormat = outputText
+	assert.NoError(t, checkOutputFormat(exportCmd))
+
+	// Шаблон задает текстовый вывод и не сочетается с машинными форматами
+	outputFormat = outputYAML
+	_, err := commandOutput("./report.tmpl", parseTemplate, reportFilters{})
+	assert.ErrorContains(t, err, "--template can only be used with --output text")
+}
+
+func TestNewReportDocument(t *testing.T) {
+	oldSource := source
+	t.Cleanup(func() { source = oldSource })
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(configPath, []byte("jira:\n  base_url: https://jira.example.com\n"), 0644))
+	viper.SetConfigFile(configPath)
+	assert.NoError(t, viper.ReadInConfig())
+
+	filters := filterFlags{result: "fail", minVersion: "5.4"}
+	source = "jira"
+	document := newReportDocument(csvTestIssues(t), filters.used(ticketSelection{jql: "fixVersion = 5.4"}))
+	assert.Equal(t, domain.SchemaVersion, document.SchemaVersion)
+	assert.WithinDuration(t, time.Now(), document.GeneratedAt, time.Minute)
+	assert.Equal(t, reportTool{Name: "jira-parser", Version: version.App.Version, Commit: version.App.Commit, Date: version.App.Date}, document.Tool)
+	assert.Equal(t, reportSource{Type: "jira", BaseURL: "https://jira.example.com"}, document.Source)
+
+	var buf bytes.Buffer
+	assert.NoError(t, json.NewEncoder(&buf).Encode(document.Filters))
+	// В filters выводятся только использованные фильтры
+	assert.Equal(t, `{"result":"fail","min_version":"5.4","jql":"fixVersion = 5.4"}`+"\n", buf.String())
+
+	source = "file:./dump"
+	assert.Equal(t, reportSource{Type: "file", Dir: "./dump"}, newReportDocument(csvTestIssues(t), reportFilters{}).Source)
+}
@@ -122,19 +122,19 @@ func TestFilterCommentsByResult(t *testing.T) {
 			SoftwareVersion: "v1.0.0",
 			TestResult:      "Fixed",
 			Comment:         "All tests passed",
-			Created:         time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+			Created:         time.Now().Add(-24 * time.Hour),
 		},
 		{
 			SoftwareVersion: "v1.0.1",
 			TestResult:      "Not Fixed",
 			Comment:         "Issue still exists",
-			Created:         time.Now().Add(-12 * time.Hour).Format(time.RFC3339),
+			Created:         time.Now().Add(-12 * time.Hour),
 		},
 		{
 			SoftwareVersion: "v1.0.2",
 			TestResult:      "Fixed",
 			Comment:         "Fixed in this version",
-			Created:         time.Now().Format(time.RFC3339),
+			Created:         time.Now(),
 		},
 	}
 
@@ -152,8 +152,8 @@ func TestFilterCommentsByResult(t *testing.T) {
 	}
 
 	assert.Len(t, filteredComments, 2)
-	assert.Equal(t, "Fixed", filteredComments[0].TestResult)
-	assert.Equal(t, "Fixed", filteredComments[1].TestResult)
+	assert.Equal(t, domain.OutcomeFixed, filteredComments[0].TestResult)
+	assert.Equal(t, domain.OutcomeFixed, filteredComments[1].TestResult)
 
 	// Тестируем фильтрацию по результату "Not Fixed"
 	filteredComments = []domain.QAComment{}
@@ -164,7 +164,7 @@ func TestFilterCommentsByResult(t *testing.T) {
 	}
 
 	assert.Len(t, filteredComments, 1)
-	assert.Equal(t, "Not Fixed", filteredComments[0].TestResult)
+	assert.Equal(t, domain.OutcomeNotFixed, filteredComments[0].TestResult)
 }
 
 func TestFilterCommentsByDate(t *testing.T) {
@@ -177,19 +177,19 @@ func TestFilterCommentsByDate(t *testing.T) {
 			SoftwareVersion: "v1.0.0",
 			TestResult:      "Fixed",
 			Comment:         "All tests passed",
-			Created:         twoDaysAgo.Format(time.RFC3339),
+			Created:         twoDaysAgo,
 		},
 		{
 			SoftwareVersion: "v1.0.1",
 			TestResult:      "Not Fixed",
 			Comment:         "Issue still exists",
-			Created:         yesterday.Format(time.RFC3339),
+			Created:         yesterday,
 		},
 		{
 			SoftwareVersion: "v1.0.2",
 			TestResult:      "Fixed",
 			Comment:         "Fixed in this version",
-			Created:         now.Format(time.RFC3339),
+			Created:         now,
 		},
 	}
 
@@ -199,44 +199,42 @@ func TestFilterCommentsByDate(t *testing.T) {
 	}
 
 	// Тестируем фильтрацию по дате "date-from"
-	var filteredComments []domain.QAComment
-	dateFrom := yesterday.Add(-time.Hour).Format("2006-01-02")
-
-	for _, comment := range issue.Comments {
-		commentTime, err := time.Parse(time.RFC3339, comment.Created)
-		assert.NoError(t, err)
-
-		fromTime, err := time.Parse("2006-01-02", dateFrom)
-		assert.NoError(t, err)
-
-		if !commentTime.Before(fromTime) {
-			filteredComments = append(filteredComments, comment)
-		}
-	}
+	dateFrom := yesterday.Add(-time.Hour).Format(time.RFC3339)
+	filter, err := filterFlags{dateFrom: dateFrom}.filter("")
+	assert.NoError(t, err)
+	filteredComments := filter.Apply(issue.Comments)
 
 	assert.Len(t, filteredComments, 2)
 	assert.Equal(t, "v1.0.1", filteredComments[0].SoftwareVersion)
 	assert.Equal(t, "v1.0.2", filteredComments[1].SoftwareVersion)
 
 	// Тестируем фильтрацию по дате "date-to"
-	filteredComments = []domain.QAComment{}
 	dateTo := yesterday.Add(time.Hour).Format(time.RFC3339)
-
-	for _, comment := range issue.Comments {
-		commentTime, err := time.Parse(time.RFC3339, comment.Created)
-		assert.NoError(t, err)
-
-		toTime, err := time.Parse(time.RFC3339, dateTo)
-		assert.NoError(t, err)
-
-		if !commentTime.After(toTime) {
-			filteredComments = append(filteredComments, comment)
-		}
-	}
+	filter, err = filterFlags{dateTo: dateTo}.filter("")
+	assert.NoError(t, err)
+	filteredComments = filter.Apply(issue.Comments)
 
 	assert.Len(t, filteredComments, 2)
 	assert.Equal(t, "v1.0.0", filteredComments[0].SoftwareVersion)
 	assert.Equal(t, "v1.0.1", filteredComments[1].SoftwareVersion)
+
+	// Относительная дата: комментарии за последние 36 часов
+	filter, err = filterFlags{dateFrom: "36h"}.filter("")
+	assert.NoError(t, err)
+	filteredComments = filter.Apply(issue.Comments)
+	assert.Len(t, filteredComments, 2)
+
+	// Комментарий с неизвестной датой не проходит фильтр по дате, но проходит фильтр по результату
+	undated := append(issue.Comments, domain.QAComment{SoftwareVersion: "v1.0.3", TestResult: domain.OutcomeFixed})
+	filter, err = filterFlags{dateTo: dateTo}.filter("")
+	assert.NoError(t, err)
+	assert.Len(t, filter.Apply(undated), 2)
+	filter, err = filterFlags{result: "Fixed"}.filter("")
+	assert.NoError(t, err)
+	assert.Len(t, filter.Apply(undated), 3)
+
+	_, err = filterFlags{dateFrom: "yesterday"}.filter("")
+	assert.ErrorContains(t, err, `date-from: invalid date "yesterday"`)
 }
 
 func TestPrintIssueCommentsDateFormat(t *testing.T) {
@@ -245,13 +243,13 @@ func TestPrintIssueCommentsDateFormat(t *testing.T) {
 			SoftwareVersion: "v1.0.0",
 			TestResult:      "Fixed",
 			Comment:         "All tests passed",
-			Created:         "2025-08-12T16:35:38.514+0300", // JIRA format with milliseconds and timezone
+			Created:         jiraTime("2025-08-12T16:35:38.514+0300"), // JIRA format with milliseconds and timezone
 		},
 		{
 			SoftwareVersion: "v1.0.1",
 			TestResult:      "Not Fixed",
 			Comment:         "Issue still exists",
-			Created:         "2025-08-18T11:28:56.224+0300", // JIRA format with milliseconds and timezone
+			Created:         jiraTime("2025-08-18T11:28:56.224+0300"), // JIRA format with milliseconds and timezone
 		},
 	}
 
@@ -266,7 +264,7 @@ func TestPrintIssueCommentsDateFormat(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	printIssueComments(issue)
+	err := printIssueComments(defaultTemplate(parseTemplate), issue)
 
 	// Close and restore
 	_ = w.Close()
@@ -274,6 +272,7 @@ func TestPrintIssueCommentsDateFormat(t *testing.T) {
 
 	// Read captured output
 	output, _ := io.ReadAll(r)
+	assert.NoError(t, err)
 
 	// Check that the output contains properly formatted dates
 	outputStr := string(output)
@@ -284,3 +283,37 @@ func TestPrintIssueCommentsDateFormat(t *testing.T) {
 	assert.NotContains(t, outputStr, "225-08-12") // Wrong year due to incorrect format
 	assert.NotContains(t, outputStr, "225-08-18") // Wrong year due to incorrect format
 }
+
+// jiraTime разбирает дату в формате JIRA REST API для тестовых данных
+func jiraTime(value string) time.Time {
+	t, err := time.Parse("2006-01-02T15:04:05.000-0700", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestFormatTimeWithTimezone(t *testing.T) {
+	created := jiraTime("2025-08-12T16:35:38.514+0300")
+	defer func() { displayLocation = nil }()
+
+	// Без --tz дата выводится в поясе, в котором ее вернула JIRA
+	assert.Equal(t, "2025-08-12 16:35:38", formatTime(created))
+	assert.Equal(t, "", formatTime(time.Time{}))
+
+	for tz, expected := range map[string]string{
+		"UTC":           "2025-08-12 13:35:38",
+		"+05:00":        "2025-08-12 18:35:38",
+		"Asia/Tokyo":    "2025-08-12 22:35:38",
+		"-0400":         "2025-08-12 09:35:38",
+		"Europe/Moscow": "2025-08-12 16:35:38",
+	} {
+		location, err := loadTimezone(tz)
+		assert.NoError(t, err, tz)
+		displayLocation = location
+		assert.Equal(t, expected, formatTime(created), tz)
+	}
+
+	_, err := loadTimezone("Mars/Olympus")
+	assert.ErrorContains(t, err, `invalid --tz "Mars/Olympus"`)
+}
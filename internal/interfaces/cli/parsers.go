@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/rd2w/jira-parser/internal/application"
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/config"
+	"github.com/rd2w/jira-parser/internal/infrastructure/parser"
+)
+
+// commentParsers создает парсер по умолчанию из секции parsing и опции сервиса
+// с парсерами отдельных проектов из секции parsers. В строгом режиме все проекты
+// разбираются парсером по шаблону, независимо от выбранного в конфигурации.
+func commentParsers(cfg *config.JiraConfig, strict bool) (domain.CommentParser, []application.Option, error) {
+	defaultName := parser.DefaultParser
+	if strict {
+		defaultName = parser.TemplateParserName
+	}
+
+	defaultParser, err := parser.New(defaultName, cfg.Parsing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opts []application.Option
+	for _, parserCfg := range cfg.Parsers {
+		parsing := cfg.Parsing
+		if parserCfg.Parsing != nil {
+			parsing = *parserCfg.Parsing
+		}
+
+		name := parserCfg.Parser
+		if strict {
+			name = parser.TemplateParserName
+		}
+
+		projectParser, err := parser.New(name, parsing)
+		if err != nil {
+			return nil, nil, fmt.Errorf("parser for projects %v: %w", parserCfg.Projects, err)
+		}
+		for _, project := range parserCfg.Projects {
+			opts = append(opts, application.WithProjectParser(project, projectParser))
+		}
+	}
+
+	return defaultParser, opts, nil
+}
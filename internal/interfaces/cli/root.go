@@ -1,14 +1,19 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/rd2w/jira-parser/internal/application"
 	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/cache"
 	"github.com/rd2w/jira-parser/internal/infrastructure/config"
 	"github.com/rd2w/jira-parser/internal/infrastructure/jira"
 	"github.com/rd2w/jira-parser/internal/version"
@@ -19,15 +24,77 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "jira-parser",
 	Short: "Parse QA comments from JIRA issues",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		location, err := loadTimezone(timezone)
+		if err != nil {
+			return err
+		}
+		displayLocation = location
+		return checkOutputFormat(cmd)
+	},
 }
 
+var (
+	// requestTimeout ограничивает общее время выполнения команды (0 - без ограничения)
+	requestTimeout time.Duration
+	// noCache отключает локальный кэш ответов JIRA
+	noCache bool
+	// refreshCache игнорирует записи кэша и перезаписывает их свежими данными
+	refreshCache bool
+	// source задает источник тикетов: JIRA или каталог выгрузок (file:<dir>)
+	source string
+	// strict принимает только QA комментарии, соответствующие шаблону, и сообщает причины отказа
+	strict bool
+	// timezone - часовой пояс вывода дат (--tz); пусто - пояс, в котором дату вернула JIRA
+	timezone string
+	// displayLocation - разобранное значение timezone; nil - даты выводятся без перевода
+	displayLocation *time.Location
+)
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Первый Ctrl-C отменяет контекст, чтобы команда успела вывести готовые результаты;
+	// после этого обработка сигналов возвращается по умолчанию и повторный Ctrl-C завершает процесс
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
+// commandContext возвращает контекст команды с учетом глобального флага --timeout
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if requestTimeout > 0 {
+		return context.WithTimeout(ctx, requestTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// reportInterruption сообщает, что пакет обработан не полностью, чтобы команда
+// могла вывести или экспортировать уже готовые результаты
+func reportInterruption(err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// С --output json и другими машинными форматами stdout занят данными
+		out := color.Output
+		if machineOutput() {
+			out = color.Error
+		}
+		_, _ = color.New(color.FgHiYellow).Fprintf(out, "Warning: %v; showing partial results\n", err)
+		return
+	}
+	log.Printf("Warning: %v", err)
+}
+
 func init() {
 	rootCmd.AddCommand(NewParseCommand())
 	rootCmd.AddCommand(NewLastCommentCommand())
@@ -36,6 +103,17 @@ func init() {
 	rootCmd.AddCommand(NewParseMultipleCommand())
 	rootCmd.AddCommand(NewDocsCommand())
 	rootCmd.AddCommand(NewTutorialCommand())
+	rootCmd.AddCommand(NewCacheCommand())
+	rootCmd.AddCommand(NewExplainCommand())
+	rootCmd.AddCommand(NewSchemaCommand())
+
+	rootCmd.PersistentFlags().StringVar(&source, "source", "jira", "Where to read issues from: jira, or file:<dir> with XML/JSON exports for offline parsing")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Do not read or write the local issue cache")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "Ignore cached issues and refetch them from JIRA")
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "Accept only QA comments matching the comment template and report why the others were rejected")
+	rootCmd.PersistentFlags().StringVar(&timezone, "tz", "", "Time zone for displayed dates and plain date filters, e.g. UTC, Local, Europe/Moscow or +03:00 (default: as returned by JIRA for display, local for filters)")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "Output format of parse, parse-multiple and last-comment: text, json, jsonl, yaml or table")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "timeout", 0, "Abort the command after the given duration, e.g. 30s or 5m (0 disables the timeout)")
 
 	// Настройка конфигурации
 	viper.SetConfigName("config")
@@ -62,207 +140,110 @@ func NewVersionCommand() *cobra.Command {
 }
 
 func NewParseMultipleCommand() *cobra.Command {
-	var resultFilter string
-	var dateFrom string
-	var dateTo string
+	var filters filterFlags
 	var ticketsFile string
+	var jql string
+	var concurrency int
+	var templateSpec string
 
 	cmd := &cobra.Command{
 		Use:   "parse-multiple [tickets...]",
 		Short: "Parse QA comments for multiple tickets from tickets file or command line arguments",
 		Long: `Parse QA comments for multiple tickets.
 If tickets are provided as arguments, they will be used instead of the tickets file.
+If --jql is provided, tickets are resolved through the JIRA search API.
 If no arguments are provided, loads tickets from the specified file or from ./configs/tickets.yaml by default.
 Example: jira-parser parse-multiple TOS-30690 TOS-30692
-Example: jira-parser parse-multiple --tickets-file ./my-tickets.yaml`,
-		Args: cobra.ArbitraryArgs,
+Example: jira-parser parse-multiple --jql "project = TOS AND fixVersion = 5.4"
+Example: jira-parser parse-multiple --tickets-file ./my-tickets.yaml
+Example: jira-parser parse-multiple --jql "filter = 12345" --concurrency 8
+Example: jira-parser parse-multiple --jql "fixVersion = 5.4" --output jsonl`,
+		Args:        cobra.ArbitraryArgs,
+		Annotations: map[string]string{outputAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
-			service, err := createCommentService()
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+
+			// Аргументы имеют приоритет над --jql и файлом тикетов
+			selection, err := selectTickets(args, ticketsFile, jql)
 			if err != nil {
-				log.Fatalf("Error: %v", err)
+				log.Fatalf("Failed to resolve tickets: %v", err)
 			}
 
-			var ticketKeys []string
+			// Фильтры проверяются до обращения к JIRA
+			commentFilter, err := filters.filter(selection.filter)
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
 
-			// Если переданы аргументы, используем их как тикеты
-			if len(args) > 0 {
-				ticketKeys = args
-			} else {
-				// Иначе загружаем список тикетов из файла
-				ticketsFilePath := ticketsFile
-				if ticketsFilePath == "" {
-					ticketsFilePath = "./configs/tickets.yaml"
-				}
+			tmpl, err := commandOutput(templateSpec, parseMultipleTemplate, filters.used(selection))
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
 
-				ticketsConfig, err := config.LoadTickets(ticketsFilePath)
-				if err != nil {
-					log.Fatalf("Failed to read tickets file: %v", err)
-				}
+			service, err := createCommentService(ctx, application.WithConcurrency(concurrency), withCommentFilter(commentFilter))
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
 
-				ticketKeys = ticketsConfig.Tickets
+			ticketKeys, err := selection.resolve(ctx, service)
+			if err != nil {
+				log.Fatalf("Failed to resolve tickets: %v", err)
 			}
 
 			if len(ticketKeys) == 0 {
-				log.Fatalf("No tickets provided either as arguments or in tickets file")
+				log.Fatalf("No tickets provided as arguments, by JQL query or in tickets file")
 			}
 
-			issuesList, err := service.ParseMultipleTickets(ticketKeys)
+			issuesList, err := service.ParseMultipleTicketsWithContext(ctx, ticketKeys)
 			if err != nil {
-				log.Fatalf("Failed to parse multiple tickets: %v", err)
-			}
-
-			// Apply filters if specified
-			if resultFilter != "" || dateFrom != "" || dateTo != "" {
-				for i := range issuesList.Issues {
-					var filteredComments []domain.QAComment
-					for _, comment := range issuesList.Issues[i].Comments {
-						// Apply result filter
-						if resultFilter != "" && comment.TestResult != resultFilter {
-							continue
-						}
-
-						// Apply date filters
-						if dateFrom != "" || dateTo != "" {
-							// Parse comment creation date - try multiple formats since JIRA can return different timestamp formats
-							var commentTime time.Time
-							var err error
-
-							// Try the JIRA format with milliseconds and timezone offset: 2025-08-12T16:35:38.514+0300
-							commentTime, err = time.Parse("2006-01-02T15:04:05.000-0700", comment.Created)
-							if err != nil {
-								// Try standard RFC3339 format
-								commentTime, err = time.Parse(time.RFC3339, comment.Created)
-							}
-							if err != nil {
-								// Try another common format
-								commentTime, err = time.Parse("2006-01-02T15:04:05-0700", comment.Created)
-							}
-
-							if err != nil {
-								log.Printf("Warning: Could not parse comment creation date: %s", comment.Created)
-								// Skip date filtering for this comment if we can't parse the date
-								filteredComments = append(filteredComments, comment)
-								continue
-							}
-
-							// Apply date-from filter
-							if dateFrom != "" {
-								fromTime, err := time.Parse("2006-01-02", dateFrom)
-								if err != nil {
-									log.Printf("Warning: Invalid date-from format: %s", dateFrom)
-								} else if commentTime.Before(fromTime) {
-									continue
-								}
-							}
-
-							// Apply date-to filter
-							if dateTo != "" {
-								toTime, err := time.Parse("2006-01-02", dateTo)
-								if err != nil {
-									log.Printf("Warning: Invalid date-to format: %s", dateTo)
-								} else if commentTime.After(toTime) {
-									continue
-								}
-							}
-						}
-
-						filteredComments = append(filteredComments, comment)
-					}
-
-					// Update issue with filtered comments
-					issuesList.Issues[i].Comments = filteredComments
+				if issuesList == nil {
+					log.Fatalf("Failed to parse multiple tickets: %v", err)
 				}
+				reportInterruption(err)
 			}
 
-			printMultipleIssues(issuesList)
+			if err := printMultipleIssues(tmpl, issuesList); err != nil {
+				log.Fatalf("Error: %v", err)
+			}
 		},
 	}
 
-	cmd.Flags().StringVarP(&resultFilter, "result", "r", "", "Filter comments by test result (e.g., Fixed, Not Fixed, etc.)")
-	cmd.Flags().StringVarP(&dateFrom, "date-from", "d", "", "Filter comments created after specified date (format: YYYY-MM-DD)")
-	cmd.Flags().StringVarP(&dateTo, "date-to", "t", "", "Filter comments created before specified date (format: YYYY-MM-DD)")
+	filters.register(cmd)
 	cmd.Flags().StringVarP(&ticketsFile, "tickets-file", "f", "", "Path to the YAML file containing the list of tickets (default: ./configs/tickets.yaml)")
+	cmd.Flags().StringVar(&jql, "jql", "", "JQL query used to select tickets (e.g., 'filter = 12345')")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Number of tickets processed in parallel")
+	cmd.Flags().StringVar(&templateSpec, "template", "", templateFlagUsage)
 
 	return cmd
 }
 
-func printMultipleIssues(issuesList *domain.IssuesList) {
-	fmt.Printf("\nChecked %d issues with QA comments:\n\n", len(issuesList.Issues))
-
-	for _, issue := range issuesList.Issues {
-		if issue.Summary != "" {
-			fmt.Printf("\n%s: %s\n", issue.Key, issue.Summary)
-		} else {
-			fmt.Printf("\n%s\n", issue.Key)
-		}
-
-		// Выводим информацию о назначенном и QA владельце
-		if issue.AssigneeEmail != "" {
-			fmt.Printf("Assigned: %s\n", issue.AssigneeEmail)
-		}
-		if issue.QaOwnerEmail != "" {
-			fmt.Printf("QA Owner: %s\n", issue.QaOwnerEmail)
-		}
-
-		fmt.Printf("Found %d QA comments:\n\n", len(issue.Comments))
-
-		for i, comment := range issue.Comments {
-			// Format the creation date for display
-			createdTime := ""
-			if comment.Created != "" {
-				// Parse the timestamp and format it as "YYYY-MM-DD HH:MM:SS"
-				// Try multiple formats since JIRA can return different timestamp formats
-				var t time.Time
-				var err error
-
-				// Try the JIRA format with milliseconds and timezone offset: 2025-08-12T16:35:38.514+0300
-				t, err = time.Parse("2006-01-02T15:04:05.000-0700", comment.Created)
-				if err != nil {
-					// Try standard RFC3339 format
-					t, err = time.Parse(time.RFC3339, comment.Created)
-				}
-				if err != nil {
-					// Try another common format
-					t, err = time.Parse("2006-01-02T15:04:05-0700", comment.Created)
-				}
-
-				if err == nil {
-					createdTime = t.Format("2006-01-02 15:04:05")
-					if comment.AuthorEmail != "" {
-						fmt.Printf("Comment #%d (%s) from %s:\n", i+1, createdTime, comment.AuthorEmail)
-					} else {
-						fmt.Printf("Comment #%d (%s):\n", i+1, createdTime)
-					}
-				} else {
-					if comment.AuthorEmail != "" {
-						fmt.Printf("Comment #%d from %s:\n", i+1, comment.AuthorEmail)
-					} else {
-						fmt.Printf("Comment #%d:\n", i+1)
-					}
-				}
-			} else {
-				if comment.AuthorEmail != "" {
-					fmt.Printf("Comment #%d from %s:\n", i+1, comment.AuthorEmail)
-				} else {
-					fmt.Printf("Comment #%d:\n", i+1)
-				}
-			}
-			fmt.Printf("  Version: %s\n", comment.SoftwareVersion)
+// printMultipleIssues выводит тикеты шаблоном команды parse-multiple или в формате --output
+func printMultipleIssues(tmpl *outputTemplate, issuesList *domain.IssuesList) error {
+	return tmpl.render(os.Stdout, issuesList)
+}
 
-			// Use colored output for test result
-			resultColor := getColorForStatus(comment.TestResult)
-			_, _ = resultColor.Printf("  Result: %s\n", comment.TestResult)
+// printFailures выводит тикеты, которые не удалось обработать
+func printFailures(failures []domain.TicketFailure) {
+	if len(failures) == 0 {
+		return
+	}
 
-			if comment.Comment != "" {
-				fmt.Printf("  Comment: %s\n", comment.Comment)
-			}
-			fmt.Println()
-		}
-		fmt.Println(strings.Repeat("-", 50))
+	_, _ = color.New(color.FgRed).Printf("\nFailed to process %d tickets:\n", len(failures))
+	for _, failure := range failures {
+		fmt.Printf("  %s: %s\n", failure.Key, failure.Error)
 	}
 }
 
-func createCommentService() (*application.CommentService, error) {
+func createCommentService(ctx context.Context, opts ...application.Option) (*application.CommentService, error) {
+	dir, err := offlineDir(source)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		return createOfflineCommentService(dir, opts...)
+	}
+
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
@@ -273,10 +254,40 @@ func createCommentService() (*application.CommentService, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	jiraClient, err := jira.NewJiraClient(cfg.BaseURL, cfg.Username, cfg.Token, cfg.Parsing)
+	commentFormat, err := jira.ParseCommentFormat(cfg.CommentFormat)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	clientOpts := []jira.ClientOption{
+		jira.WithRetryPolicy(jira.RetryPolicy{
+			MaxAttempts:    cfg.Retry.MaxAttempts,
+			InitialBackoff: cfg.Retry.InitialBackoff,
+			MaxBackoff:     cfg.Retry.MaxBackoff,
+		}),
+		jira.WithCommentFormat(commentFormat),
+		jira.WithRateLimit(cfg.RateLimit),
+	}
+	if !noCache {
+		cacheRoot, err := cacheDir(cfg.Cache.Dir)
+		if err != nil {
+			log.Printf("Warning: issue cache disabled: %v", err)
+		} else {
+			clientOpts = append(clientOpts, jira.WithCache(cache.ForInstance(cacheRoot, cfg.BaseURL), refreshCache))
+		}
+	}
+
+	defaultParser, parserOpts, err := commentParsers(cfg, strict)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	jiraClient, err := jira.NewJiraClientWithContext(ctx, cfg.BaseURL, cfg.Username, cfg.Token, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create JIRA client: %w", err)
 	}
 
-	return application.NewCommentService(jiraClient), nil
+	// Парсеры проектов из конфигурации идут первыми, чтобы явно переданные опции могли их переопределить
+	opts = append(parserOpts, opts...)
+	return application.NewCommentService(jiraClient, defaultParser, opts...), nil
 }
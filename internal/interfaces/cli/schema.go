@@ -0,0 +1,28 @@
+package cli
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// reportSchema - JSON Schema документа reportDocument
+//
+//go:embed schema/report.schema.json
+var reportSchema []byte
+
+func NewSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of exported JSON and --output json/yaml documents",
+		Long: `Print the JSON Schema (draft 2020-12) of the document written by export --format json
+and printed by --output json and --output yaml, so downstream tools can validate it.
+schema_version in the document is increased whenever a field is renamed or removed.
+Example: jira-parser schema > jira-parser-report.schema.json`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Print(string(reportSchema))
+		},
+	}
+}
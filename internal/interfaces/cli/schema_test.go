@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// jsonSchemaObject - часть JSON Schema, которую сверяет тест
+type jsonSchemaObject struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// jsonFields возвращает имена полей JSON типа и обязательные поля (без omitempty и omitzero)
+func jsonFields(typ reflect.Type) (fields, required []string) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		fields = append(fields, name)
+		if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") {
+			required = append(required, name)
+		}
+	}
+	return fields, required
+}
+
+func schemaFields(t *testing.T, raw json.RawMessage) (fields, required []string) {
+	var object jsonSchemaObject
+	assert.NoError(t, json.Unmarshal(raw, &object))
+	for name := range object.Properties {
+		fields = append(fields, name)
+	}
+	return fields, object.Required
+}
+
+func TestReportSchemaMatchesTypes(t *testing.T) {
+	var schema struct {
+		jsonSchemaObject
+		Defs map[string]json.RawMessage `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(reportSchema, &schema))
+
+	var version struct {
+		Const int `json:"const"`
+	}
+	assert.NoError(t, json.Unmarshal(schema.Properties["schema_version"], &version))
+	assert.Equal(t, domain.SchemaVersion, version.Const)
+
+	// Схема перечисляет те же поля, что и теги json, а обязательны в ней поля без omitempty
+	root, _ := json.Marshal(schema.jsonSchemaObject)
+	objects := map[string]json.RawMessage{
+		"report":    root,
+		"tool":      schema.Properties["tool"],
+		"source":    schema.Properties["source"],
+		"filters":   schema.Properties["filters"],
+		"issue":     schema.Defs["issue"],
+		"comment":   schema.Defs["comment"],
+		"result":    schema.Defs["result"],
+		"rejection": schema.Defs["rejection"],
+		"failure":   schema.Defs["failure"],
+	}
+	types := map[string]reflect.Type{
+		"report":    reflect.TypeOf(reportDocument{}),
+		"tool":      reflect.TypeOf(reportTool{}),
+		"source":    reflect.TypeOf(reportSource{}),
+		"filters":   reflect.TypeOf(reportFilters{}),
+		"issue":     reflect.TypeOf(domain.Issue{}),
+		"comment":   reflect.TypeOf(domain.QAComment{}),
+		"result":    reflect.TypeOf(domain.TestCaseResult{}),
+		"rejection": reflect.TypeOf(domain.CommentRejection{}),
+		"failure":   reflect.TypeOf(domain.TicketFailure{}),
+	}
+	for name, typ := range types {
+		t.Run(name, func(t *testing.T) {
+			wantFields, wantRequired := jsonFields(typ)
+			gotFields, gotRequired := schemaFields(t, objects[name])
+			sort.Strings(wantFields)
+			sort.Strings(gotFields)
+			assert.Equal(t, wantFields, gotFields)
+			assert.ElementsMatch(t, wantRequired, gotRequired)
+		})
+	}
+}
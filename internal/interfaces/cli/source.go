@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/rd2w/jira-parser/internal/application"
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/config"
+	"github.com/rd2w/jira-parser/internal/infrastructure/jira"
+	"github.com/spf13/viper"
+)
+
+// fileSourcePrefix marks a --source pointing at a directory of JIRA exports
+const fileSourcePrefix = "file:"
+
+// offlineDir возвращает каталог выгрузок из значения --source
+// или пустую строку, если данные берутся из JIRA
+func offlineDir(source string) (string, error) {
+	switch {
+	case source == "" || source == "jira":
+		return "", nil
+	case strings.HasPrefix(source, fileSourcePrefix):
+		dir := strings.TrimPrefix(source, fileSourcePrefix)
+		if dir == "" {
+			return "", fmt.Errorf("--source %s: directory is missing", source)
+		}
+		return dir, nil
+	default:
+		return "", fmt.Errorf("unsupported --source %q: use \"jira\" or \"file:<dir>\"", source)
+	}
+}
+
+// createOfflineCommentService строит сервис поверх каталога выгрузок JIRA.
+// Параметры подключения не нужны; правила разбора берутся из конфигурации, если она есть.
+func createOfflineCommentService(dir string, opts ...application.Option) (*application.CommentService, error) {
+	defaultParser, parserOpts, err := offlineCommentParsers()
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := jira.NewDumpRepository(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return application.NewCommentService(repo, defaultParser, append(parserOpts, opts...)...), nil
+}
+
+// offlineCommentParsers создает парсеры из конфигурации, не требуя параметров подключения к JIRA;
+// без файла конфигурации используются правила разбора по умолчанию
+func offlineCommentParsers() (domain.CommentParser, []application.Option, error) {
+	cfg := &config.JiraConfig{Parsing: config.DefaultParsingConfig()}
+
+	if err := viper.ReadInConfig(); err == nil {
+		cfg, err = config.LoadOfflineConfig(viper.ConfigFileUsed())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load config: %w", err)
+		}
+	} else if !isConfigNotFound(err) {
+		return nil, nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	defaultParser, parserOpts, err := commentParsers(cfg, strict)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return defaultParser, parserOpts, nil
+}
+
+// isConfigNotFound сообщает, что файл конфигурации отсутствует
+func isConfigNotFound(err error) bool {
+	var notFound viper.ConfigFileNotFoundError
+	return errors.As(err, &notFound) || errors.Is(err, fs.ErrNotExist)
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfflineDir(t *testing.T) {
+	dir, err := offlineDir("jira")
+	assert.NoError(t, err)
+	assert.Empty(t, dir)
+
+	dir, err = offlineDir("file:./dump")
+	assert.NoError(t, err)
+	assert.Equal(t, "./dump", dir)
+
+	_, err = offlineDir("file:")
+	assert.Error(t, err)
+
+	_, err = offlineDir("http://jira")
+	assert.Error(t, err)
+}
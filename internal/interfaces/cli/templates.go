@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/spf13/viper"
+)
+
+// Встроенные шаблоны повторяют вывод команд без --template
+const (
+	parseTemplate         = "parse.txt.tmpl"
+	parseMultipleTemplate = "parse-multiple.txt.tmpl"
+	lastCommentTemplate   = "last-comment.txt.tmpl"
+	htmlReportTemplate    = "export.html.tmpl"
+)
+
+// templateFlagUsage - описание флага --template команд вывода
+const templateFlagUsage = "Go template file or template name from the templates section of config.yaml; *.html templates use html/template"
+
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+// outputTemplate - шаблон вывода: встроенный или заданный --template. Данные шаблона - *domain.IssuesList.
+type outputTemplate struct {
+	name    string
+	execute func(w io.Writer, data any) error
+}
+
+// ext возвращает расширение файла export: report.md.tmpl -> .md, report.tmpl -> .txt
+func (t *outputTemplate) ext() string {
+	name := filepath.Base(t.name)
+	for _, suffix := range []string{".tmpl", ".gotmpl", ".tpl"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	if ext := filepath.Ext(name); ext != "" {
+		return ext
+	}
+	return ".txt"
+}
+
+// render выполняет шаблон для списка тикетов
+func (t *outputTemplate) render(w io.Writer, issuesList *domain.IssuesList) error {
+	if err := t.execute(w, issuesList); err != nil {
+		return fmt.Errorf("failed to render template %s: %w", t.name, err)
+	}
+	return nil
+}
+
+// templateFuncs - функции, доступные в шаблонах:
+//   - formatDate - дата в формате 2006-01-02 15:04:05 в поясе --tz, пустая для неизвестной даты
+//   - resultColor - CSS цвет категории результата: green, red, orange, blue или gray
+//   - jiraURL - ссылка на тикет в JIRA по ключу (base_url из config.yaml)
+//   - latest - последний QA комментарий тикета или nil
+//   - inc - номер элемента range, начиная с 1
+//   - summary - сводка результатов для диаграмм: последние вердикты тикетов, результаты комментариев и версии
+//   - colorize - только в текстовых шаблонах: текст в цвете категории результата, как в терминале
+func templateFuncs(html bool) map[string]any {
+	funcs := map[string]any{
+		"formatDate":  formatTime,
+		"resultColor": resultColor,
+		"jiraURL": func(key string) string {
+			// Конфигурация прочитана при создании сервиса, до выполнения шаблона
+			return jiraBrowseURL(viper.GetString("jira.base_url"), key)
+		},
+		"latest":  latestComment,
+		"inc":     func(i int) int { return i + 1 },
+		"summary": buildReportSummary,
+	}
+	if !html {
+		funcs["colorize"] = func(category domain.OutcomeCategory, text any) string {
+			return getColorForCategory(category).Sprint(text)
+		}
+	}
+	return funcs
+}
+
+// resultColor возвращает CSS цвет категории, как в HTML отчете
+func resultColor(category domain.OutcomeCategory) string {
+	return resultColors[categoryOrUnknown(category)]
+}
+
+// latestComment возвращает последний QA комментарий тикета
+func latestComment(issue domain.Issue) *domain.QAComment {
+	if len(issue.Comments) == 0 {
+		return nil
+	}
+	return &issue.Comments[len(issue.Comments)-1]
+}
+
+// parseOutputTemplate разбирает шаблон: файлы .html и .htm - через html/template с экранированием,
+// остальные - через text/template
+func parseOutputTemplate(name, content string) (*outputTemplate, error) {
+	html := strings.Contains(strings.ToLower(filepath.Base(name)), ".htm")
+	if html {
+		tmpl, err := htmltemplate.New(filepath.Base(name)).Funcs(templateFuncs(true)).Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", name, err)
+		}
+		return &outputTemplate{name: name, execute: tmpl.Execute}, nil
+	}
+	tmpl, err := template.New(filepath.Base(name)).Funcs(templateFuncs(false)).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return &outputTemplate{name: name, execute: tmpl.Execute}, nil
+}
+
+// defaultTemplate возвращает встроенный шаблон; встроенные шаблоны проверяются тестами
+func defaultTemplate(name string) *outputTemplate {
+	content, err := embeddedTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		panic(err)
+	}
+	tmpl, err := parseOutputTemplate(name, string(content))
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// loadOutputTemplate загружает шаблон --template: имя из секции templates файла config.yaml
+// или путь к файлу. Без --template возвращается встроенный шаблон fallback.
+func loadOutputTemplate(spec, fallback string) (*outputTemplate, error) {
+	if spec == "" {
+		return defaultTemplate(fallback), nil
+	}
+
+	path := spec
+	// viper приводит ключи конфигурации к нижнему регистру
+	if configured, ok := configuredTemplates()[strings.ToLower(spec)]; ok {
+		path = configured
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	return parseOutputTemplate(path, string(content))
+}
+
+// configuredTemplates читает шаблоны команд из секции templates файла config.yaml:
+//
+//	templates:
+//	  mobile: ./templates/mobile.html.tmpl
+//
+// Относительные пути считаются от каталога файла конфигурации.
+func configuredTemplates() map[string]string {
+	if err := viper.ReadInConfig(); err != nil {
+		return nil
+	}
+	templates := viper.GetStringMapString("templates")
+	dir := filepath.Dir(viper.ConfigFileUsed())
+	for name, path := range templates {
+		if !filepath.IsAbs(path) {
+			templates[name] = filepath.Join(dir, path)
+		}
+	}
+	return templates
+}
+
+// renderString выполняет шаблон в строку
+func renderString(tmpl *outputTemplate, issuesList *domain.IssuesList) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.render(&buf, issuesList); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
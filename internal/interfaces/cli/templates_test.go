@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultTemplates(t *testing.T) {
+	for _, name := range []string{parseTemplate, parseMultipleTemplate, lastCommentTemplate, htmlReportTemplate} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NotPanics(t, func() {
+				assert.NoError(t, defaultTemplate(name).render(&buf, csvTestIssues(t)))
+			})
+			assert.Contains(t, buf.String(), "TOS-1")
+		})
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	assert.NoError(t, os.WriteFile(configPath, []byte(`jira:
+  base_url: "https://jira.example.com/"
+templates:
+  Release-Notes: ./templates/release.md.tmpl
+`), 0644))
+	viper.SetConfigFile(configPath)
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "templates"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "templates", "release.md.tmpl"), []byte(
+		`{{range .Issues}}{{.Key}} {{jiraURL .Key}}{{with latest .}} {{.TestResult}} {{resultColor .Category}} {{formatDate .Created}}{{else}} none{{end}}
+{{range $i, $c := .Comments}}  {{inc $i}}. {{$c.SoftwareVersion}}
+{{end}}{{end}}`), 0644))
+
+	// Имя шаблона из config.yaml сравнивается без учета регистра, путь считается от каталога конфигурации
+	tmpl, err := loadOutputTemplate("release-notes", parseTemplate)
+	assert.NoError(t, err)
+	assert.Equal(t, ".md", tmpl.ext())
+
+	var buf bytes.Buffer
+	assert.NoError(t, tmpl.render(&buf, csvTestIssues(t)))
+	assert.Equal(t, `TOS-1 https://jira.example.com/browse/TOS-1 Fixed green 2025-08-14 09:00:00
+  1. v1.0.0
+  2. v1.0.1
+TOS-2 https://jira.example.com/browse/TOS-2 none
+`, buf.String())
+}
+
+func TestHTMLTemplateEscaping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "team.html")
+	assert.NoError(t, os.WriteFile(path, []byte(
+		`{{range .Issues}}<a href="{{jiraURL .Key}}" style="color: {{with latest .}}{{resultColor .Category}}{{end}}">{{.Summary}}</a>{{end}}`), 0644))
+
+	tmpl, err := loadOutputTemplate(path, "")
+	assert.NoError(t, err)
+	assert.Equal(t, ".html", tmpl.ext())
+
+	issuesList := &domain.IssuesList{Issues: []domain.Issue{{
+		Key:      "TOS-1",
+		Summary:  `<script>alert("x")</script>`,
+		Comments: []domain.QAComment{{TestResult: domain.OutcomeNotFixed, Category: domain.CategoryFail}},
+	}}}
+	var buf bytes.Buffer
+	assert.NoError(t, tmpl.render(&buf, issuesList))
+	assert.Contains(t, buf.String(), `style="color: red"`)
+	assert.Contains(t, buf.String(), `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;`)
+
+	// Встроенный HTML отчет тоже экранирует данные из JIRA
+	html, err := generateHTMLReport(issuesList)
+	assert.NoError(t, err)
+	assert.Contains(t, html, `<td>&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</td>`)
+	assert.NotContains(t, html, `<script>alert`)
+}
+
+func TestLoadOutputTemplateErrors(t *testing.T) {
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	_, err := loadOutputTemplate("./missing.tmpl", parseTemplate)
+	assert.ErrorContains(t, err, "failed to read template")
+
+	path := filepath.Join(t.TempDir(), "broken.tmpl")
+	assert.NoError(t, os.WriteFile(path, []byte(`{{range .Issues}}`), 0644))
+	_, err = loadOutputTemplate(path, parseTemplate)
+	assert.ErrorContains(t, err, "invalid template")
+
+	path = filepath.Join(t.TempDir(), "unknown.tmpl")
+	assert.NoError(t, os.WriteFile(path, []byte(`{{.Unknown}}`), 0644))
+	tmpl, err := loadOutputTemplate(path, parseTemplate)
+	assert.NoError(t, err)
+	assert.Equal(t, ".txt", tmpl.ext())
+	assert.ErrorContains(t, tmpl.render(&bytes.Buffer{}, csvTestIssues(t)), "failed to render template")
+}
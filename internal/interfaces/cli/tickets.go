@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/rd2w/jira-parser/internal/infrastructure/config"
+)
+
+// defaultTicketsFile is used when neither arguments, --jql nor --tickets-file are given
+const defaultTicketsFile = "./configs/tickets.yaml"
+
+// ticketSelection - тикеты пакетной команды, определенные без обращения к JIRA
+type ticketSelection struct {
+	keys []string
+	// jql - запрос, тикеты которого добавляются к keys
+	jql string
+	// filter - выражение фильтра комментариев из файла тикетов
+	filter string
+}
+
+// selectTickets определяет источник тикетов для пакетных команд.
+// Приоритет: аргументы командной строки, затем --jql, затем файл тикетов
+// (где явный список объединяется с результатами его собственного jql).
+// Выражение filter из файла тикетов действует, только если тикеты берутся из файла.
+func selectTickets(args []string, ticketsFile, jql string) (ticketSelection, error) {
+	if len(args) > 0 {
+		return ticketSelection{keys: args}, nil
+	}
+
+	if jql != "" {
+		return ticketSelection{jql: jql}, nil
+	}
+
+	ticketsFilePath := ticketsFile
+	if ticketsFilePath == "" {
+		ticketsFilePath = defaultTicketsFile
+	}
+
+	ticketsConfig, err := config.LoadTickets(ticketsFilePath)
+	if err != nil {
+		return ticketSelection{}, fmt.Errorf("failed to read tickets file: %w", err)
+	}
+
+	return ticketSelection{keys: ticketsConfig.Tickets, jql: ticketsConfig.JQL, filter: ticketsConfig.Filter}, nil
+}
+
+// resolve возвращает список тикетов, дополненный результатами jql
+func (s ticketSelection) resolve(ctx context.Context, service domain.CommentService) ([]string, error) {
+	if s.jql == "" {
+		return s.keys, nil
+	}
+	jqlKeys, err := service.SearchTicketsWithContext(ctx, s.jql)
+	if err != nil {
+		return nil, err
+	}
+	return mergeTicketKeys(s.keys, jqlKeys), nil
+}
+
+// mergeTicketKeys объединяет списки тикетов, сохраняя порядок и убирая дубликаты
+func mergeTicketKeys(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, list := range lists {
+		for _, key := range list {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, key)
+		}
+	}
+	return merged
+}
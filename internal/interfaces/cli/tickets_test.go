@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rd2w/jira-parser/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubSearchService подменяет SearchTickets, остальные методы не используются
+type stubSearchService struct {
+	domain.CommentService
+	results map[string][]string
+}
+
+func (s *stubSearchService) SearchTicketsWithContext(ctx context.Context, jql string) ([]string, error) {
+	return s.results[jql], nil
+}
+
+func TestResolveTicketKeys(t *testing.T) {
+	tempDir := t.TempDir()
+	ticketsPath := filepath.Join(tempDir, "tickets.yaml")
+	ticketsContent := `tickets:
+  - "TOS-1"
+  - "TOS-2"
+jql: "filter = 100"
+`
+	err := os.WriteFile(ticketsPath, []byte(ticketsContent), 0644)
+	assert.NoError(t, err)
+
+	service := &stubSearchService{results: map[string][]string{
+		"filter = 100":  {"TOS-2", "TOS-3"},
+		"project = TOS": {"TOS-9"},
+	}}
+
+	resolve := func(args []string, ticketsFile, jql string) ([]string, error) {
+		selection, err := selectTickets(args, ticketsFile, jql)
+		if err != nil {
+			return nil, err
+		}
+		return selection.resolve(context.Background(), service)
+	}
+
+	// Аргументы имеют наивысший приоритет
+	keys, err := resolve([]string{"TOS-7"}, ticketsPath, "project = TOS")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TOS-7"}, keys)
+
+	// Флаг --jql используется вместо файла тикетов
+	keys, err = resolve(nil, ticketsPath, "project = TOS")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TOS-9"}, keys)
+
+	// Тикеты из файла объединяются с результатами jql без дубликатов
+	keys, err = resolve(nil, ticketsPath, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"TOS-1", "TOS-2", "TOS-3"}, keys)
+
+	_, err = resolve(nil, filepath.Join(tempDir, "missing.yaml"), "")
+	assert.Error(t, err)
+}
+
+func TestSelectTicketsFilter(t *testing.T) {
+	ticketsPath := filepath.Join(t.TempDir(), "tickets.yaml")
+	ticketsContent := `tickets:
+  - "TOS-1"
+filter: 'result in (Fixed, "Partially Fixed") and author ~ "@qa.example.com"'
+`
+	assert.NoError(t, os.WriteFile(ticketsPath, []byte(ticketsContent), 0644))
+
+	selection, err := selectTickets(nil, ticketsPath, "")
+	assert.NoError(t, err)
+	assert.Equal(t, `result in (Fixed, "Partially Fixed") and author ~ "@qa.example.com"`, selection.filter)
+
+	// Выражение из файла объединяется с флагами
+	filter, err := filterFlags{minVersion: "5.2"}.filter(selection.filter)
+	assert.NoError(t, err)
+	assert.Equal(t, `result in (Fixed, "Partially Fixed") and author ~ @qa.example.com and version >= 5.2`, filter.String())
+
+	// Фильтр файла не действует, если тикеты заданы аргументами
+	selection, err = selectTickets([]string{"TOS-7"}, ticketsPath, "")
+	assert.NoError(t, err)
+	assert.Empty(t, selection.filter)
+
+	_, err = filterFlags{}.filter("result =")
+	assert.ErrorContains(t, err, "tickets file: invalid filter")
+}
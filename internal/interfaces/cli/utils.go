@@ -1,21 +1,71 @@
 package cli
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/fatih/color"
+	"github.com/rd2w/jira-parser/internal/domain"
 )
 
-// getColorForStatus возвращает цвет в зависимости от статуса тестирования
-func getColorForStatus(status string) *color.Color {
-	switch status {
-	case "Fixed", "OK", "Passed", "Verified", "Resolved":
+// getColorForCategory возвращает цвет для категории результата тестирования
+func getColorForCategory(category domain.OutcomeCategory) *color.Color {
+	switch category {
+	case domain.CategoryPass:
 		return color.New(color.FgGreen)
-	case "Not Fixed", "NOK", "Failed", "Blocked":
+	case domain.CategoryFail:
 		return color.New(color.FgRed)
-	case "Partially Fixed", "Partially OK":
+	case domain.CategoryPartial:
 		return color.New(color.FgHiYellow)
-	case "Could not test", "Pending":
+	case domain.CategoryBlocked:
 		return color.New(color.FgBlue)
 	default:
 		return color.New(color.Reset)
 	}
 }
+
+// loadTimezone разбирает значение --tz: имя IANA (Europe/Moscow), UTC, Local или смещение (+03:00).
+// Пустое значение возвращает nil - даты выводятся в поясе, в котором их вернула JIRA.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"-07:00", "-0700", "-07"} {
+		if offset, err := time.Parse(layout, name); err == nil {
+			return offset.Location(), nil
+		}
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tz %q: %w", name, err)
+	}
+	return location, nil
+}
+
+// formatTime форматирует дату для вывода в поясе --tz; нулевая дата дает пустую строку
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if displayLocation != nil {
+		t = t.In(displayLocation)
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+// printTestCaseResults выводит результаты отдельных сценариев комментария с отступом indent
+func printTestCaseResults(results []domain.TestCaseResult, indent string) {
+	if len(results) == 0 {
+		return
+	}
+
+	fmt.Printf("%sScenarios:\n", indent)
+	for _, result := range results {
+		fmt.Printf("%s  - %s: ", indent, result.Scenario)
+		_, _ = getColorForCategory(result.Category).Print(result.Result)
+		if result.Note != "" {
+			fmt.Printf(" (%s)", result.Note)
+		}
+		fmt.Println()
+	}
+}